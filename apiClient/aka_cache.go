@@ -0,0 +1,158 @@
+package apiClient
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// resourceAkaCache caches id <-> aka resolution for the life of the provider process.
+//
+// storeAkas resolves every referenced resource - parent, identity, permission type and level - to
+// its akas on every create AND every read, and each resolution is a full ReadResource round trip.
+// turbot_grant alone makes four per resource, so refreshing a 2,000-resource state made ~8,000
+// queries, nearly all of them for the same few dozen parents, permission types and levels.
+//
+// An entry is stored under every name the resource answers to - the identifier it was looked up by,
+// its numeric id and each of its akas - so a lookup by id after a lookup by aka (the normal pattern:
+// config holds the aka, state holds the id) is a hit.
+//
+// Staleness: a resource's numeric id never changes, but its akas can, so every mutation that
+// targets an existing resource invalidates it (see invalidateMutationTarget, called from
+// doRequest). A delete cascades to the resource's descendants, so it also drops every entry whose
+// path runs through the deleted resource. A lookup that was in flight when an invalidation happened
+// may have read the resource before the mutation, so entries are only stored if no invalidation
+// happened since the lookup started (see akaCacheGeneration). What is NOT caught is a change made
+// outside this process mid-apply, e.g. a parent deleted by hand between two reads; the window is a
+// single Terraform run, and the resource's own read - never cached - still reports it. Terraform
+// runs each provider configuration, aliases included, in its own process, so the cache never mixes
+// workspaces.
+var resourceAkaCache sync.Map // identifier -> *cachedAkas
+
+// akaCacheGeneration counts invalidations. A lookup reads it before reading the resource and passes
+// it to storeCachedAkas, which stores nothing if it has moved on. akaCacheLock makes the check and
+// the store one step with respect to invalidation.
+var (
+	akaCacheGeneration uint64
+	akaCacheLock       sync.Mutex
+)
+
+type cachedAkas struct {
+	id string
+	// the ids of the resource's ancestors and the resource itself, separated by "."
+	path string
+	akas []string
+}
+
+// currentAkaCacheGeneration returns the generation to pass to storeCachedAkas for a lookup starting now
+func currentAkaCacheGeneration() uint64 {
+	akaCacheLock.Lock()
+	defer akaCacheLock.Unlock()
+	return akaCacheGeneration
+}
+
+// lookupCachedAkas returns a copy of the cached akas for identifier, so a caller appending to the
+// result cannot corrupt the entry other goroutines share.
+func lookupCachedAkas(identifier string) ([]string, bool) {
+	value, ok := resourceAkaCache.Load(identifier)
+	if !ok {
+		return nil, false
+	}
+	entry := value.(*cachedAkas)
+	return append([]string(nil), entry.akas...), true
+}
+
+// storeCachedAkas records a resolution under identifier, the resource's id and each of its akas,
+// unless the cache was invalidated since generation was read.
+func storeCachedAkas(identifier, id, path string, akas []string, generation uint64) {
+	akaCacheLock.Lock()
+	defer akaCacheLock.Unlock()
+	if generation != akaCacheGeneration {
+		return
+	}
+	entry := &cachedAkas{id: id, path: path, akas: append([]string(nil), akas...)}
+	resourceAkaCache.Store(identifier, entry)
+	if id != "" {
+		resourceAkaCache.Store(id, entry)
+	}
+	for _, aka := range akas {
+		resourceAkaCache.Store(aka, entry)
+	}
+}
+
+// invalidateCachedAkas drops the entry for identifier and every other name it was stored under.
+func invalidateCachedAkas(identifier string) {
+	akaCacheLock.Lock()
+	defer akaCacheLock.Unlock()
+	akaCacheGeneration++
+	value, ok := resourceAkaCache.Load(identifier)
+	if !ok {
+		return
+	}
+	deleteCachedAkas(identifier, value.(*cachedAkas))
+}
+
+// invalidateCachedDescendants drops the entry for identifier and the entries of all its descendants.
+// A descendant is only known by the path it was cached with, so identifier must be cached to find
+// them by id; if it is not, identifier is taken to be the id.
+func invalidateCachedDescendants(identifier string) {
+	akaCacheLock.Lock()
+	defer akaCacheLock.Unlock()
+	akaCacheGeneration++
+	id := identifier
+	if value, ok := resourceAkaCache.Load(identifier); ok {
+		entry := value.(*cachedAkas)
+		deleteCachedAkas(identifier, entry)
+		if entry.id != "" {
+			id = entry.id
+		}
+	}
+	resourceAkaCache.Range(func(key, value interface{}) bool {
+		entry := value.(*cachedAkas)
+		for _, ancestorId := range strings.Split(entry.path, ".") {
+			if ancestorId == id {
+				resourceAkaCache.Delete(key)
+				break
+			}
+		}
+		return true
+	})
+}
+
+// deleteCachedAkas deletes entry under identifier and every other name it was stored under. The
+// caller holds akaCacheLock.
+func deleteCachedAkas(identifier string, entry *cachedAkas) {
+	resourceAkaCache.Delete(identifier)
+	if entry.id != "" {
+		resourceAkaCache.Delete(entry.id)
+	}
+	for _, aka := range entry.akas {
+		resourceAkaCache.Delete(aka)
+	}
+}
+
+// invalidateMutationTarget drops the cache entry for the resource a mutation updates or deletes.
+// Every update/delete mutation in queries.go names its target as `input.id` or as a top-level `$id`,
+// so hooking doRequest covers them all - including ones added later - without each wrapper having to
+// remember. Creates carry no id, so nothing is invalidated for them. A delete also deletes the
+// target's descendants, so their entries are dropped as well.
+func invalidateMutationTarget(query string, vars map[string]interface{}) {
+	invalidate := invalidateCachedAkas
+	if deleteMutationPattern.MatchString(query) {
+		invalidate = invalidateCachedDescendants
+	}
+	if id, ok := vars["id"].(string); ok {
+		invalidate(id)
+	}
+	switch input := vars["input"].(type) {
+	case map[string]interface{}:
+		if id, ok := input["id"].(string); ok {
+			invalidate(id)
+		}
+	case map[string]string:
+		invalidate(input["id"])
+	}
+}
+
+// deleteMutationPattern matches the delete mutations in queries.go, e.g. `mutation DeleteResource(`
+var deleteMutationPattern = regexp.MustCompile(`^\s*mutation\s+(?i:delete)`)
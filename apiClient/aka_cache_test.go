@@ -0,0 +1,186 @@
+package apiClient
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/machinebox/graphql"
+	"github.com/stretchr/testify/assert"
+)
+
+// resourceServer answers every query with one resource and every mutation with its metadata,
+// counting queries so tests can assert how many reads actually reached the workspace.
+func resourceServer(id string, akas string) (*httptest.Server, *int32) {
+	var reads int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		var body struct{ Query string }
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if !isMutation(body.Query) {
+			atomic.AddInt32(&reads, 1)
+		}
+		_, _ = w.Write([]byte(`{"data":{"resource":{"type":{"uri":"tmod:@turbot/turbot#/resource/types/folder"},"turbot":{"id":"` + id + `","akas":` + akas + `}}}}`))
+	}))
+	return server, &reads
+}
+
+func resetAkaCache() {
+	resourceAkaCache.Range(func(key, _ interface{}) bool {
+		resourceAkaCache.Delete(key)
+		return true
+	})
+}
+
+// Repeated lookups of one resource - by the same name or by any other name it answers to - must cost
+// a single read. This is the whole point: storeAkas resolves the same parents and permission types
+// over and over.
+func TestGetResourceAkasIsCached(t *testing.T) {
+	resetAkaCache()
+	defer resetAkaCache()
+	server, reads := resourceServer("111", `["my_folder","arn:folder"]`)
+	defer server.Close()
+	client := &Client{Graphql: graphql.NewClient(server.URL + "/graphql")}
+
+	for i := 0; i < 5; i++ {
+		akas, err := client.GetResourceAkas("my_folder")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"my_folder", "arn:folder"}, akas)
+	}
+	// config holds the aka, state holds the id: both must hit the same entry
+	akas, err := client.GetResourceAkas("111")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"my_folder", "arn:folder"}, akas)
+	_, err = client.GetResourceAkas("arn:folder")
+	assert.NoError(t, err)
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(reads), "every name for one resource must share one read")
+}
+
+// A caller mutating the returned slice must not corrupt what other goroutines read from the cache.
+func TestGetResourceAkasReturnsCopy(t *testing.T) {
+	resetAkaCache()
+	defer resetAkaCache()
+	storeCachedAkas("222", "222", "222", []string{"a", "b"}, currentAkaCacheGeneration())
+
+	first, err := (&Client{}).GetResourceAkas("222")
+	assert.NoError(t, err)
+	first[0] = "mutated"
+
+	second, _ := (&Client{}).GetResourceAkas("222")
+	assert.Equal(t, []string{"a", "b"}, second)
+}
+
+// Updating or deleting a resource must drop it under every name, so the next lookup re-reads.
+func TestMutationInvalidatesCachedAkas(t *testing.T) {
+	resetAkaCache()
+	defer resetAkaCache()
+	server, reads := resourceServer("333", `["my_folder"]`)
+	defer server.Close()
+	client := &Client{Graphql: graphql.NewClient(server.URL + "/graphql")}
+
+	_, err := client.GetResourceAkas("my_folder")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(reads))
+
+	// update by numeric id invalidates the entry stored under the aka too
+	_, err = client.UpdateResource(map[string]interface{}{"id": "333", "data": map[string]interface{}{}})
+	assert.NoError(t, err)
+	_, ok := lookupCachedAkas("my_folder")
+	assert.False(t, ok, "an update must invalidate every name the resource was cached under")
+
+	_, err = client.GetResourceAkas("my_folder")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(reads), "the lookup after an update must re-read")
+
+	// delete, which passes its input as map[string]string
+	assert.NoError(t, client.DeleteResource("my_folder"))
+	_, ok = lookupCachedAkas("333")
+	assert.False(t, ok, "a delete must invalidate the entry")
+}
+
+func TestInvalidateMutationTarget(t *testing.T) {
+	var tests = []struct {
+		name string
+		vars map[string]interface{}
+	}{
+		{"top level id", map[string]interface{}{"id": "444"}},
+		{"input map", map[string]interface{}{"input": map[string]interface{}{"id": "444"}}},
+		{"input string map", map[string]interface{}{"input": map[string]string{"id": "444"}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resetAkaCache()
+			storeCachedAkas("444", "444", "444", []string{"x"}, currentAkaCacheGeneration())
+			invalidateMutationTarget("mutation UpdateResource($input: UpdateResourceInput!) {}", test.vars)
+			_, ok := lookupCachedAkas("x")
+			assert.False(t, ok)
+		})
+	}
+	// a create carries no id and must leave the cache alone
+	resetAkaCache()
+	storeCachedAkas("555", "555", "555", []string{"y"}, currentAkaCacheGeneration())
+	invalidateMutationTarget("mutation CreateResource($input: CreateResourceInput!) {}", map[string]interface{}{"input": map[string]interface{}{"parent": "555"}})
+	_, ok := lookupCachedAkas("y")
+	assert.True(t, ok, "a create must not invalidate its parent")
+	resetAkaCache()
+}
+
+// Deleting a resource deletes its descendants too, so their entries must go with it - and only theirs.
+func TestDeleteInvalidatesCachedDescendants(t *testing.T) {
+	resetAkaCache()
+	defer resetAkaCache()
+	storeCachedAkas("folder", "10", "1.10", []string{"folder"}, currentAkaCacheGeneration())
+	storeCachedAkas("child", "11", "1.10.11", []string{"child"}, currentAkaCacheGeneration())
+	storeCachedAkas("grandchild", "12", "1.10.11.12", []string{"grandchild"}, currentAkaCacheGeneration())
+	storeCachedAkas("sibling", "20", "1.20", []string{"sibling"}, currentAkaCacheGeneration())
+
+	invalidateMutationTarget(deleteResourceMutation(), map[string]interface{}{"input": map[string]string{"id": "folder"}})
+	for _, name := range []string{"folder", "10", "child", "11", "grandchild", "12"} {
+		_, ok := lookupCachedAkas(name)
+		assert.False(t, ok, "%s must be dropped when its ancestor is deleted", name)
+	}
+	_, ok := lookupCachedAkas("sibling")
+	assert.True(t, ok, "a resource outside the deleted tree must stay cached")
+}
+
+// A lookup that started before an invalidation may have read the resource as it was before the
+// mutation, so it must not store what it read.
+func TestStaleLookupIsNotStored(t *testing.T) {
+	resetAkaCache()
+	defer resetAkaCache()
+	generation := currentAkaCacheGeneration()
+	invalidateCachedAkas("777")
+	storeCachedAkas("777", "777", "777", []string{"old_aka"}, generation)
+	_, ok := lookupCachedAkas("old_aka")
+	assert.False(t, ok, "a lookup in flight during an invalidation must not be stored")
+
+	storeCachedAkas("777", "777", "777", []string{"new_aka"}, currentAkaCacheGeneration())
+	_, ok = lookupCachedAkas("new_aka")
+	assert.True(t, ok)
+}
+
+// The cache is shared by every goroutine Terraform runs; concurrent lookups, stores and
+// invalidations must be race-free (run with -race) and always return a complete entry.
+func TestAkaCacheConcurrentAccess(t *testing.T) {
+	resetAkaCache()
+	defer resetAkaCache()
+	var wait sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wait.Add(1)
+		go func(i int) {
+			defer wait.Done()
+			storeCachedAkas("666", "666", "666", []string{"z1", "z2"}, currentAkaCacheGeneration())
+			if akas, ok := lookupCachedAkas("z1"); ok {
+				assert.Equal(t, []string{"z1", "z2"}, akas)
+			}
+			if i%5 == 0 {
+				invalidateCachedAkas("666")
+			}
+		}(i)
+	}
+	wait.Wait()
+}
@@ -0,0 +1,268 @@
+package apiClient
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	errorsHandler "github.com/turbot/terraform-provider-turbot/errors"
+)
+
+// Sentinels for the kinds of API failure callers act on. Test for them with errors.Is - an
+// *APIError matches the sentinel for its kind, and every wrapper in this package keeps the APIError
+// reachable:
+//
+//	if errors.Is(err, apiClient.ErrNotFound) {
+//		d.SetId("")
+//		return nil
+//	}
+//
+// ErrValidation is any input the API rejected. ErrSchemaValidation is the narrower case of a value
+// that failed its JSON schema - the "data validation failed" error that a policy setting value is
+// retried as a value source for - and also matches ErrValidation.
+var (
+	ErrNotFound         = errors.New("not found")
+	ErrValidation       = errors.New("invalid input")
+	ErrSchemaValidation = errors.New("data validation failed")
+	ErrUnauthorized     = errors.New("unauthorized")
+	ErrConflict         = errors.New("conflict")
+)
+
+// APIError is a failure reported by the Guardrails API, decoded from the first entry of the
+// response's `errors` array - the same entry machinebox/graphql reduces to a message - or, for a
+// request the gateway rejected outright, from the HTTP status. Use errors.As to get at its fields.
+type APIError struct {
+	// Code is errors[].extensions.code, e.g. "NOT_FOUND". Empty if the server sent none.
+	Code string
+	// Status is errors[].extensions.status, or the HTTP status of the response when the extensions
+	// carry none and it was not 200.
+	Status int
+	// Path is errors[].path: the field that failed, e.g. ["resource", "turbot"].
+	Path []interface{}
+	// Operation is the name of the query or mutation, e.g. "CreateResource".
+	Operation string
+	// RequestId identifies the request in the Guardrails logs, for a support ticket.
+	RequestId string
+	// Message is errors[].message as the server sent it.
+	Message string
+
+	// text is what Error returns. It is kept exactly as the provider reported the failure before
+	// APIError existed, so diagnostics - and configurations matching on them - do not change.
+	text string
+}
+
+func (e *APIError) Error() string {
+	return e.text
+}
+
+// Is matches the sentinel for the kind of failure, so errors.Is(err, ErrNotFound) works for any
+// error wrapping an *APIError.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrValidation:
+		kind := e.kind()
+		return kind == ErrValidation || kind == ErrSchemaValidation
+	case ErrNotFound, ErrSchemaValidation, ErrUnauthorized, ErrConflict:
+		return e.kind() == target
+	}
+	return false
+}
+
+// kind classifies the failure, preferring what the server said about it: its extensions code, then
+// its status. Only when it says neither is the message consulted - except that a generic bad input
+// whose message is the schema failure's is a schema failure, as it was before codes were read.
+func (e *APIError) kind() error {
+	kind, ok := errorCodeKinds[normaliseErrorCode(e.Code)]
+	if !ok {
+		kind, ok = errorStatusKinds[e.Status]
+	}
+	if !ok {
+		return messageKind(e.Message)
+	}
+	if kind == ErrValidation && messageKind(e.Message) == ErrSchemaValidation {
+		return ErrSchemaValidation
+	}
+	return kind
+}
+
+// errorCodeKinds maps extensions codes to sentinels. Guardrails and the GraphQL servers in front of
+// it spell codes differently between versions, so codes are normalised before lookup.
+var errorCodeKinds = map[string]error{
+	"NOT_FOUND":              ErrNotFound,
+	"DATA_VALIDATION_FAILED": ErrSchemaValidation,
+	"VALIDATION_FAILED":      ErrSchemaValidation,
+	"BAD_USER_INPUT":         ErrValidation,
+	"BAD_REQUEST":            ErrValidation,
+	"UNAUTHENTICATED":        ErrUnauthorized,
+	"UNAUTHORIZED":           ErrUnauthorized,
+	"FORBIDDEN":              ErrUnauthorized,
+	"PERMISSION_DENIED":      ErrUnauthorized,
+	"CONFLICT":               ErrConflict,
+	"ALREADY_EXISTS":         ErrConflict,
+}
+
+var errorStatusKinds = map[int]error{
+	http.StatusNotFound:            ErrNotFound,
+	http.StatusBadRequest:          ErrValidation,
+	http.StatusUnprocessableEntity: ErrValidation,
+	http.StatusUnauthorized:        ErrUnauthorized,
+	http.StatusForbidden:           ErrUnauthorized,
+	http.StatusConflict:            ErrConflict,
+}
+
+var camelCaseRegex = regexp.MustCompile(`([a-z])([A-Z])`)
+
+// normaliseErrorCode folds "NotFound", "not-found" and "NOT_FOUND" to the same key.
+func normaliseErrorCode(code string) string {
+	code = camelCaseRegex.ReplaceAllString(code, "${1}_${2}")
+	return strings.ToUpper(strings.NewReplacer("-", "_", " ", "_").Replace(code))
+}
+
+var (
+	unauthorizedMessageRegex = regexp.MustCompile(`(?i)^(permission denied|unauthori[sz]ed|forbidden)\b`)
+	conflictMessageRegex     = regexp.MustCompile(`(?i)^conflict:|already exists`)
+)
+
+// messageKind classifies a failure from its message alone. This is the fallback for workspaces that
+// send no extensions, and the only place left in the provider that reads error text; the not-found
+// and validation shapes are those the errors package has always recognised.
+func messageKind(message string) error {
+	switch err := errors.New(message); {
+	case errorsHandler.NotFoundError(err):
+		return ErrNotFound
+	case errorsHandler.FailedValidationError(err):
+		return ErrSchemaValidation
+	case unauthorizedMessageRegex.MatchString(message):
+		return ErrUnauthorized
+	case conflictMessageRegex.MatchString(message):
+		return ErrConflict
+	}
+	return nil
+}
+
+// graphqlError is one entry of a response's `errors` array.
+type graphqlError struct {
+	Message    string
+	Path       []interface{}
+	Extensions map[string]interface{}
+}
+
+// decodeGraphqlErrors returns the `errors` array of a response body, or nil if the body is not a
+// GraphQL response - a gateway's HTML error page, say.
+func decodeGraphqlErrors(body []byte) []graphqlError {
+	var response struct {
+		Errors []graphqlError
+	}
+	if len(body) == 0 || json.Unmarshal(body, &response) != nil {
+		return nil
+	}
+	return response.Errors
+}
+
+// apiError builds the APIError for one GraphQL error. httpStatus and requestId come from the
+// response carrying it; the extensions win over both.
+func (graphqlError graphqlError) apiError(operation string, httpStatus int, requestId string) *APIError {
+	apiError := &APIError{
+		Code:      extensionString(graphqlError.Extensions, "code"),
+		Path:      graphqlError.Path,
+		Operation: operation,
+		RequestId: requestId,
+		Message:   graphqlError.Message,
+		// the format machinebox/graphql gives the same error
+		text: "graphql: " + graphqlError.Message,
+	}
+	if id := extensionString(graphqlError.Extensions, "requestId", "request_id"); id != "" {
+		apiError.RequestId = id
+	}
+	apiError.Status = extensionInt(graphqlError.Extensions, "status", "statusCode")
+	if apiError.Status == 0 && httpStatus != http.StatusOK {
+		apiError.Status = httpStatus
+	}
+	return apiError
+}
+
+func extensionString(extensions map[string]interface{}, keys ...string) string {
+	for _, key := range keys {
+		if value, ok := extensions[key].(string); ok && value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func extensionInt(extensions map[string]interface{}, keys ...string) int {
+	for _, key := range keys {
+		switch value := extensions[key].(type) {
+		case float64:
+			return int(value)
+		case string:
+			var status int
+			if _, err := fmt.Sscanf(value, "%d", &status); err == nil {
+				return status
+			}
+		}
+	}
+	return 0
+}
+
+// newAPIError turns the raw error from execute into the error doRequest returns. A failure the API
+// reported becomes an *APIError; anything else - the workspace was unreachable, the request timed
+// out - is returned as BuildErrorMessage has always reported it.
+func newAPIError(err error, trace *requestTrace, operation string) error {
+	if isConnectionError(err) {
+		return errorsHandler.BuildErrorMessage(err)
+	}
+	if trace != nil {
+		// machinebox/graphql reports the first error of the response; so does this
+		if graphqlErrors := decodeGraphqlErrors(trace.responseBody()); len(graphqlErrors) > 0 {
+			return graphqlErrors[0].apiError(operation, trace.status(), trace.requestIdHeader())
+		}
+	}
+	if status, _ := errorsHandler.ExtractErrorCode(err); status != 0 {
+		// the gateway rejected the request without a GraphQL response
+		apiError := &APIError{Status: status, Operation: operation, text: errorsHandler.BuildErrorMessage(err).Error()}
+		if trace != nil {
+			apiError.RequestId = trace.requestIdHeader()
+		}
+		return apiError
+	}
+	if message := strings.TrimPrefix(err.Error(), "graphql: "); message != err.Error() {
+		// A GraphQL error whose body was not traced - a Client built with a plain graphql.Client -
+		// carries only its message.
+		return &APIError{Operation: operation, Message: message, text: err.Error()}
+	}
+	return errorsHandler.BuildErrorMessage(err)
+}
+
+var operationNameRegex = regexp.MustCompile(`^\s*(?:query|mutation|subscription)\s+(\w+)`)
+
+// operationName returns the name of the operation a document declares, or "" if it is anonymous.
+func operationName(query string) string {
+	if match := operationNameRegex.FindStringSubmatch(query); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
+// wrappedError replaces an error's message while keeping the error itself reachable through
+// errors.Is and errors.As. The handle*Error wrappers use it where the message they have always
+// reported does not include the underlying error.
+type wrappedError struct {
+	message string
+	cause   error
+}
+
+func (e *wrappedError) Error() string {
+	return e.message
+}
+
+func (e *wrappedError) Unwrap() error {
+	return e.cause
+}
+
+func wrapError(cause error, format string, args ...interface{}) error {
+	return &wrappedError{message: fmt.Sprintf(format, args...), cause: cause}
+}
@@ -0,0 +1,176 @@
+package apiClient
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/machinebox/graphql"
+	"github.com/stretchr/testify/assert"
+)
+
+// errorServer answers every request with status and body, tagging the response with a request id.
+func errorServer(status int, body string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Request-Id", "req-123")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+}
+
+var sentinels = []error{ErrNotFound, ErrValidation, ErrSchemaValidation, ErrUnauthorized, ErrConflict}
+
+// sentinelMatches returns whether an error of the kind sentinel matches other: its own sentinel, and
+// ErrValidation for a schema validation failure
+func sentinelMatches(sentinel, other error) bool {
+	return other == sentinel || (sentinel == ErrSchemaValidation && other == ErrValidation)
+}
+
+// The extensions decide the kind, whatever the message says - these messages match none of the
+// text shapes the errors package recognises.
+func TestDoRequestDecodesExtensions(t *testing.T) {
+	var tests = []struct {
+		name       string
+		extensions string
+		sentinel   error
+	}{
+		{"not found code", `{"code":"NOT_FOUND"}`, ErrNotFound},
+		{"camel case code", `{"code":"DataValidationFailed"}`, ErrSchemaValidation},
+		{"bad input code", `{"code":"BAD_USER_INPUT"}`, ErrValidation},
+		{"bad request status", `{"status":400}`, ErrValidation},
+		{"unauthenticated code", `{"code":"UNAUTHENTICATED"}`, ErrUnauthorized},
+		{"conflict status", `{"status":409}`, ErrConflict},
+		{"string status", `{"statusCode":"404"}`, ErrNotFound},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := errorServer(http.StatusOK, `{"data":null,"errors":[{"message":"no such thing","path":["resource","turbot"],"extensions":`+test.extensions+`}]}`)
+			defer server.Close()
+			client := &Client{Graphql: newGraphqlClient(server.URL + "/graphql")}
+
+			var resp map[string]interface{}
+			err := client.doRequest(readResourceQuery(nil), map[string]interface{}{"id": "1"}, &resp)
+			assert.True(t, errors.Is(err, test.sentinel), "%s", err)
+			for _, other := range sentinels {
+				if !sentinelMatches(test.sentinel, other) {
+					assert.False(t, errors.Is(err, other), "%s must not also match %s", err, other)
+				}
+			}
+			assert.Equal(t, "graphql: no such thing", err.Error(), "the message is unchanged")
+		})
+	}
+}
+
+func TestAPIErrorFields(t *testing.T) {
+	server := errorServer(http.StatusOK, `{"errors":[{"message":"Not Found: Resource not found or not accessible","path":["resource"],"extensions":{"code":"NOT_FOUND"}}]}`)
+	defer server.Close()
+	client := &Client{Graphql: newGraphqlClient(server.URL + "/graphql")}
+
+	_, err := client.ReadFolder("missing")
+	var apiError *APIError
+	if assert.True(t, errors.As(err, &apiError), "the APIError must survive handleReadError: %s", err) {
+		assert.Equal(t, "NOT_FOUND", apiError.Code)
+		assert.Equal(t, 0, apiError.Status, "a 200 response carries no status of its own")
+		assert.Equal(t, []interface{}{"resource"}, apiError.Path)
+		assert.Equal(t, "ReadResource", apiError.Operation)
+		assert.Equal(t, "req-123", apiError.RequestId)
+		assert.Equal(t, "Not Found: Resource not found or not accessible", apiError.Message)
+	}
+	assert.Equal(t, "error reading folder: resource not found: missing", err.Error())
+	assert.True(t, errors.Is(err, ErrNotFound))
+}
+
+// A gateway rejecting the request outright has no GraphQL body; its HTTP status decides the kind, and
+// the message is the one BuildErrorMessage has always given it.
+func TestDoRequestClassifiesHttpStatus(t *testing.T) {
+	server := errorServer(http.StatusForbidden, `<html>forbidden</html>`)
+	defer server.Close()
+	client := &Client{Graphql: newGraphqlClient(server.URL + "/graphql")}
+
+	var resp map[string]interface{}
+	err := client.doRequest(`{ __typename }`, nil, &resp)
+	assert.True(t, errors.Is(err, ErrUnauthorized), "%s", err)
+	assert.Contains(t, err.Error(), "Forbidden error (403)")
+	var apiError *APIError
+	if assert.True(t, errors.As(err, &apiError)) {
+		assert.Equal(t, http.StatusForbidden, apiError.Status)
+		assert.Equal(t, "req-123", apiError.RequestId)
+	}
+}
+
+// Without extensions - an older workspace, or a Client whose transport does not trace the body - the
+// message is classified by the shapes the errors package recognises, and no more loosely.
+func TestDoRequestFallsBackToMessage(t *testing.T) {
+	var tests = []struct {
+		message  string
+		sentinel error
+	}{
+		{`Not Found: Table \"resources\" column \"id\" with value \"1\"`, ErrNotFound},
+		{"Data Validation Failed: value must be a string", ErrSchemaValidation},
+		{"Permission denied: resource 1", ErrUnauthorized},
+		{"folder my_folder already exists", ErrConflict},
+		{"policy type tmod:@turbot/foo not found. Is the mod installed?", nil},
+	}
+	for _, test := range tests {
+		t.Run(test.message, func(t *testing.T) {
+			server := errorServer(http.StatusOK, `{"errors":[{"message":"`+test.message+`"}]}`)
+			defer server.Close()
+			for _, graphqlClient := range []*graphql.Client{newGraphqlClient(server.URL + "/graphql"), graphql.NewClient(server.URL + "/graphql")} {
+				client := &Client{Graphql: graphqlClient}
+				var resp map[string]interface{}
+				err := client.doRequest(`{ __typename }`, nil, &resp)
+				for _, sentinel := range sentinels {
+					assert.Equal(t, sentinelMatches(test.sentinel, sentinel), errors.Is(err, sentinel), "%s vs %s", err, sentinel)
+				}
+			}
+		})
+	}
+}
+
+// Only a schema failure is retried as a value source. A generic bad input is not one - unless its
+// message is the schema failure's, which is how such failures were recognised before codes were read.
+func TestSchemaValidationIsNarrowerThanValidation(t *testing.T) {
+	var tests = []struct {
+		name   string
+		body   string
+		schema bool
+	}{
+		{"bad input", `{"errors":[{"message":"Variable \"$input\" got invalid value","extensions":{"code":"BAD_USER_INPUT"}}]}`, false},
+		{"bad input failing the schema", `{"errors":[{"message":"Data Validation Failed: must be a string","extensions":{"code":"BAD_USER_INPUT"}}]}`, true},
+		{"schema code", `{"errors":[{"message":"must be a string","extensions":{"code":"DATA_VALIDATION_FAILED"}}]}`, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := errorServer(http.StatusOK, test.body)
+			defer server.Close()
+			client := &Client{Graphql: newGraphqlClient(server.URL + "/graphql")}
+
+			var resp map[string]interface{}
+			err := client.doRequest(`{ __typename }`, nil, &resp)
+			assert.True(t, errors.Is(err, ErrValidation), "%s", err)
+			assert.Equal(t, test.schema, errors.Is(err, ErrSchemaValidation), "%s", err)
+		})
+	}
+}
+
+// A failure that never reached the API is not an APIError and matches no sentinel.
+func TestDoRequestConnectionErrorIsNotAnAPIError(t *testing.T) {
+	server := errorServer(http.StatusOK, "")
+	server.Close()
+	client := &Client{Graphql: newGraphqlClient(server.URL + "/graphql")}
+
+	var resp map[string]interface{}
+	err := client.doRequest(`{ __typename }`, nil, &resp)
+	var apiError *APIError
+	assert.Error(t, err)
+	assert.False(t, errors.As(err, &apiError))
+	assert.False(t, errors.Is(err, ErrNotFound))
+}
+
+func TestOperationName(t *testing.T) {
+	assert.Equal(t, "CreateResource", operationName("mutation CreateResource($input: CreateResourceInput!) {}"))
+	assert.Equal(t, "ReadFolder", operationName("\n\tquery ReadFolder($id: ID!) {}"))
+	assert.Equal(t, "", operationName("{ __typename }"))
+}
@@ -0,0 +1,269 @@
+package apiClient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	errorsHandler "github.com/turbot/terraform-provider-turbot/errors"
+)
+
+// Batching defaults installed by CreateClient. During a refresh Terraform calls Exists and Read for
+// up to -parallelism resources at once, so reads arrive in bursts; a 10ms window gathers a burst
+// into one request at a latency cost that is small next to the round trip it saves.
+const (
+	DefaultBatchWindow  = 10 * time.Millisecond
+	DefaultBatchMaxSize = 50
+)
+
+// Batcher coalesces concurrent reads of the same shape - ReadResource, ReadPolicySetting and
+// ReadGrant - made within a short window into a single aliased GraphQL document:
+//
+//	query BatchRead($id0: ID!, $id1: ID!) {
+//		r0: resource(id: $id0) { ... }
+//		r1: resource(id: $id1) { ... }
+//	}
+//
+// and hands each caller back its own alias. Every identifier stays a $idN variable, so batching
+// keeps the variable-only discipline of queries.go.
+//
+// Errors are attributed per alias from the `path` of each GraphQL error, so one missing resource
+// fails only its own caller. Anything that cannot be attributed - a batch of one, an error with no
+// path, an alias with neither data nor an error - falls back to the ordinary single read, so a
+// caller never sees a failure it would not have seen unbatched. A nil *Batcher disables batching,
+// which is what a hand-built Client gets.
+//
+// A read made while no other read is in flight has nothing to wait for, so it is sent at once
+// rather than after the window; only reads that overlap another are batched.
+type Batcher struct {
+	window  time.Duration
+	maxSize int
+
+	mutex   sync.Mutex
+	pending map[batchKey]*readBatch
+	// the number of reads started and not yet finished, batched or not
+	active int
+}
+
+// reads batch together only when they select exactly the same fields from the same root field
+type batchKey struct {
+	field     string
+	selection string
+}
+
+type readBatch struct {
+	key   batchKey
+	calls []*batchCall
+	timer *time.Timer
+}
+
+type batchCall struct {
+	id       string
+	result   json.RawMessage
+	err      error
+	fallback bool
+	done     chan struct{}
+}
+
+// NewBatcher returns a Batcher that waits up to window for further reads to join a batch, and
+// sends a batch early once it holds maxSize reads.
+func NewBatcher(window time.Duration, maxSize int) *Batcher {
+	return &Batcher{
+		window:  window,
+		maxSize: maxSize,
+		pending: map[batchKey]*readBatch{},
+	}
+}
+
+// batchAlias names the result of the i'th read in a batched document.
+func batchAlias(i int) string {
+	return fmt.Sprintf("r%d", i)
+}
+
+// readById runs query, which must read exactly `field(id: $id) { selection }`, and decodes the
+// result of field into target. When batching is enabled the read is coalesced with concurrent reads
+// of the same shape; otherwise, or when the batch cannot answer for this read, it is sent on its own.
+func (client *Client) readById(query, field, selection, id string, target interface{}) error {
+	if client.Batcher != nil {
+		defer client.Batcher.finish()
+		if call := client.Batcher.enqueue(client, batchKey{field: field, selection: selection}, id); call != nil {
+			<-call.done
+			if !call.fallback {
+				if call.err != nil {
+					return call.err
+				}
+				return decodeField(call.result, target)
+			}
+		}
+	}
+
+	responseData := map[string]json.RawMessage{}
+	if err := client.doRequest(query, map[string]interface{}{"id": id}, &responseData); err != nil {
+		return err
+	}
+	return decodeField(responseData[field], target)
+}
+
+// decodeField decodes one field of a response. An absent field leaves target at its zero value, as
+// decoding the whole response into a struct would.
+func decodeField(raw json.RawMessage, target interface{}) error {
+	if len(raw) == 0 {
+		return nil
+	}
+	return json.Unmarshal(raw, target)
+}
+
+// enqueue adds a read to the pending batch for key, starting the batch's window if it is the first,
+// and returns the call to wait on. It returns nil if no other read is in flight, for the caller to
+// send the read on its own at once. Either way the caller calls finish when the read is done.
+func (batcher *Batcher) enqueue(client *Client, key batchKey, id string) *batchCall {
+	batcher.mutex.Lock()
+	batcher.active++
+	if batcher.active == 1 {
+		batcher.mutex.Unlock()
+		return nil
+	}
+	call := &batchCall{id: id, done: make(chan struct{})}
+	batch, ok := batcher.pending[key]
+	if !ok {
+		batch = &readBatch{key: key}
+		batcher.pending[key] = batch
+		batch.timer = time.AfterFunc(batcher.window, func() { batcher.flushIfPending(client, batch) })
+	}
+	batch.calls = append(batch.calls, call)
+	full := len(batch.calls) >= batcher.maxSize
+	if full {
+		// detach under the lock, so no later read can join a batch that is already being sent
+		delete(batcher.pending, key)
+	}
+	batcher.mutex.Unlock()
+
+	if full {
+		batch.timer.Stop()
+		go batcher.send(client, batch)
+	}
+	return call
+}
+
+// finish records that a read started by enqueue is done.
+func (batcher *Batcher) finish() {
+	batcher.mutex.Lock()
+	batcher.active--
+	batcher.mutex.Unlock()
+}
+
+// flushIfPending sends a batch whose window has elapsed, unless it already filled up and was sent.
+func (batcher *Batcher) flushIfPending(client *Client, batch *readBatch) {
+	batcher.mutex.Lock()
+	if batcher.pending[batch.key] != batch {
+		batcher.mutex.Unlock()
+		return
+	}
+	delete(batcher.pending, batch.key)
+	batcher.mutex.Unlock()
+	batcher.send(client, batch)
+}
+
+// send issues a detached batch and distributes the results to its callers.
+func (batcher *Batcher) send(client *Client, batch *readBatch) {
+	defer func() {
+		for _, call := range batch.calls {
+			close(call.done)
+		}
+	}()
+
+	// Two callers reading the same id share one alias.
+	var ids []string
+	aliases := map[string]string{}
+	variables := map[string]interface{}{}
+	for _, call := range batch.calls {
+		if _, ok := aliases[call.id]; !ok {
+			aliases[call.id] = batchAlias(len(ids))
+			variables[fmt.Sprintf("id%d", len(ids))] = call.id
+			ids = append(ids, call.id)
+		}
+	}
+	if len(ids) < 2 {
+		// nothing to coalesce; the single read reports its own errors in the usual way
+		for _, call := range batch.calls {
+			call.fallback = true
+		}
+		return
+	}
+
+	query := batchReadQuery(batch.key.field, batch.key.selection, len(ids))
+	data := map[string]json.RawMessage{}
+	trace, err := client.execute(query, variables, &data)
+
+	if err != nil && !isGraphqlResponseError(err, trace) {
+		// The request itself failed - the workspace was unreachable or answered with an HTTP error,
+		// already retried by execute. Every read in the batch would fail the same way on its own, so
+		// report it to all of them rather than repeating the failing request once per caller.
+		err = newAPIError(err, trace, operationName(query))
+		for _, call := range batch.calls {
+			call.err = err
+		}
+		return
+	}
+
+	var aliasErrors map[string]error
+	if err != nil {
+		aliasErrors = batchAliasErrors(trace)
+	}
+	for _, call := range batch.calls {
+		alias := aliases[call.id]
+		if aliasErr, ok := aliasErrors[alias]; ok {
+			call.err = aliasErr
+			continue
+		}
+		if raw := data[alias]; len(raw) > 0 && string(raw) != "null" {
+			call.result = raw
+			continue
+		}
+		call.fallback = true
+	}
+}
+
+// isGraphqlResponseError reports whether err came from a well-formed GraphQL response - the server
+// answered, and some or all fields failed - rather than from the request failing outright.
+func isGraphqlResponseError(err error, trace *requestTrace) bool {
+	if isConnectionError(err) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if code, _ := errorsHandler.ExtractErrorCode(err); code != 0 {
+		return false
+	}
+	return trace.status() == 0 || trace.status() == http.StatusOK
+}
+
+// batchAliasErrors attributes the errors in a batched response to the alias each one names in its
+// path. Errors without a path - a document-level failure - are left unattributed, so their callers
+// fall back to the single read and receive whatever error that produces.
+func batchAliasErrors(trace *requestTrace) map[string]error {
+	graphqlErrors := decodeGraphqlErrors(trace.responseBody())
+	if len(graphqlErrors) == 0 {
+		return nil
+	}
+	aliasErrors := map[string]error{}
+	for _, graphqlError := range graphqlErrors {
+		if len(graphqlError.Path) == 0 {
+			continue
+		}
+		alias, ok := graphqlError.Path[0].(string)
+		if !ok {
+			continue
+		}
+		if _, seen := aliasErrors[alias]; seen {
+			// keep the first, matching what an unbatched read reports
+			continue
+		}
+		// Build the same APIError an unbatched read would get, so errors.Is and the handle*Error
+		// wrappers treat it identically. Its path still names the alias rather than the root field.
+		aliasErrors[alias] = graphqlError.apiError("BatchRead", trace.status(), trace.requestIdHeader())
+	}
+	return aliasErrors
+}
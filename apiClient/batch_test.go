@@ -0,0 +1,253 @@
+package apiClient
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// batchServer answers single and batched grant reads. A batched document carries $id0..$idN; the
+// server answers each rN alias from the matching variable, and reports any id named "missing" as a
+// not-found error whose path names that alias - the shape Guardrails uses for a partial failure.
+func batchServer(t *testing.T) (*httptest.Server, *int32, *int32) {
+	var requests, batched int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		var body struct {
+			Query     string
+			Variables map[string]string
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		w.Header().Set("Content-Type", "application/json")
+
+		grant := func(id string) string {
+			return fmt.Sprintf(`{"permissionTypeId":"1","permissionLevelId":"2","turbot":{"id":%q,"profileId":"3","resourceId":"4"}}`, id)
+		}
+		if !strings.Contains(body.Query, "BatchRead") {
+			id := body.Variables["id"]
+			if id == "missing" {
+				_, _ = w.Write([]byte(`{"data":{"grant":null},"errors":[{"message":"Not Found: Table \"grants\" column \"id\" with value \"missing\"","path":["grant"]}]}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"data":{"grant":` + grant(id) + `}}`))
+			return
+		}
+
+		atomic.AddInt32(&batched, 1)
+		var data, errs []string
+		for i := 0; i < len(body.Variables); i++ {
+			alias, id := batchAlias(i), body.Variables[fmt.Sprintf("id%d", i)]
+			if id == "missing" {
+				data = append(data, fmt.Sprintf("%q:null", alias))
+				errs = append(errs, fmt.Sprintf(`{"message":"Not Found: Table \"grants\" column \"id\" with value \"missing\"","path":[%q]}`, alias))
+				continue
+			}
+			data = append(data, fmt.Sprintf("%q:%s", alias, grant(id)))
+		}
+		response := `{"data":{` + strings.Join(data, ",") + `}`
+		if len(errs) > 0 {
+			response += `,"errors":[` + strings.Join(errs, ",") + `]`
+		}
+		_, _ = w.Write([]byte(response + "}"))
+	}))
+	return server, &requests, &batched
+}
+
+// readConcurrently issues ReadGrant for every id at once and returns the results by id. A read
+// is held in flight throughout, as in a refresh, so every read joins a batch rather than the first
+// being sent on its own.
+func readConcurrently(client *Client, ids []string) (map[string]*Grant, map[string]error) {
+	defer holdRead(client.Batcher)()
+	var mutex sync.Mutex
+	grants, errs := map[string]*Grant{}, map[string]error{}
+	var wait sync.WaitGroup
+	for _, id := range ids {
+		wait.Add(1)
+		go func(id string) {
+			defer wait.Done()
+			grant, err := client.ReadGrant(id)
+			mutex.Lock()
+			grants[id], errs[id] = grant, err
+			mutex.Unlock()
+		}(id)
+	}
+	wait.Wait()
+	return grants, errs
+}
+
+// holdRead counts a read as in flight until the returned func is called
+func holdRead(batcher *Batcher) func() {
+	batcher.mutex.Lock()
+	batcher.active++
+	batcher.mutex.Unlock()
+	return batcher.finish
+}
+
+// Concurrent reads within the window must go out as ONE request, and each caller must get back its
+// own result.
+func TestBatcherCoalescesConcurrentReads(t *testing.T) {
+	server, requests, batched := batchServer(t)
+	defer server.Close()
+	client := &Client{Graphql: newGraphqlClient(server.URL + "/graphql"), Batcher: NewBatcher(50*time.Millisecond, 100)}
+
+	ids := []string{"10", "11", "12", "13", "14", "15", "16", "17"}
+	grants, errs := readConcurrently(client, ids)
+
+	for _, id := range ids {
+		assert.NoError(t, errs[id])
+		if assert.NotNil(t, grants[id]) {
+			assert.Equal(t, id, grants[id].Turbot.Id, "each caller must receive its own alias")
+			assert.Equal(t, "1", grants[id].PermissionTypeId)
+		}
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(requests), "eight concurrent reads must cost one request")
+	assert.Equal(t, int32(1), atomic.LoadInt32(batched))
+}
+
+// One missing grant in a batch must fail only its own caller, with the same error - and the same
+// not-found classification - that an unbatched read produces.
+func TestBatcherDemultiplexesPerAliasErrors(t *testing.T) {
+	server, requests, _ := batchServer(t)
+	defer server.Close()
+	client := &Client{Graphql: newGraphqlClient(server.URL + "/graphql"), Batcher: NewBatcher(50*time.Millisecond, 100)}
+
+	grants, errs := readConcurrently(client, []string{"20", "missing", "21"})
+
+	assert.NoError(t, errs["20"])
+	assert.NoError(t, errs["21"])
+	assert.Equal(t, "20", grants["20"].Turbot.Id)
+	assert.Equal(t, "21", grants["21"].Turbot.Id)
+	if assert.Error(t, errs["missing"]) {
+		assert.True(t, errors.Is(errs["missing"], ErrNotFound), "a batched not-found must still classify as not-found: %s", errs["missing"])
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(requests), "a partial failure must not cost extra requests")
+
+	// the unbatched error for the same id is identical
+	unbatched := &Client{Graphql: newGraphqlClient(server.URL + "/graphql")}
+	_, err := unbatched.ReadGrant("missing")
+	assert.Equal(t, err.Error(), errs["missing"].Error())
+}
+
+// A batch of one has nothing to coalesce and is sent as the ordinary single read.
+func TestBatcherSingleReadIsNotAliased(t *testing.T) {
+	server, requests, batched := batchServer(t)
+	defer server.Close()
+	client := &Client{Graphql: newGraphqlClient(server.URL + "/graphql"), Batcher: NewBatcher(time.Millisecond, 100)}
+
+	grant, err := client.ReadGrant("30")
+	assert.NoError(t, err)
+	assert.Equal(t, "30", grant.Turbot.Id)
+	assert.Equal(t, int32(1), atomic.LoadInt32(requests))
+	assert.Equal(t, int32(0), atomic.LoadInt32(batched))
+}
+
+// A read with no other read in flight has nothing to coalesce with, so it must not wait out the window.
+func TestBatcherLoneReadDoesNotWait(t *testing.T) {
+	server, requests, batched := batchServer(t)
+	defer server.Close()
+	client := &Client{Graphql: newGraphqlClient(server.URL + "/graphql"), Batcher: NewBatcher(time.Minute, 100)}
+
+	start := time.Now()
+	for _, id := range []string{"31", "32"} {
+		grant, err := client.ReadGrant(id)
+		assert.NoError(t, err)
+		assert.Equal(t, id, grant.Turbot.Id)
+	}
+	assert.True(t, time.Since(start) < 10*time.Second, "a lone read must be sent at once")
+	assert.Equal(t, int32(2), atomic.LoadInt32(requests))
+	assert.Equal(t, int32(0), atomic.LoadInt32(batched))
+}
+
+// The size limit splits a burst into several batches, each sent as soon as it is full.
+func TestBatcherDeduplicatesAndRespectsMaxSize(t *testing.T) {
+	server, _, batched := batchServer(t)
+	defer server.Close()
+	client := &Client{Graphql: newGraphqlClient(server.URL + "/graphql"), Batcher: NewBatcher(time.Second, 4)}
+
+	start := time.Now()
+	grants, errs := readConcurrently(client, []string{"40", "41", "42", "43", "44", "45", "46", "47"})
+	for id, grant := range grants {
+		assert.NoError(t, errs[id])
+		assert.Equal(t, id, grant.Turbot.Id)
+	}
+	assert.Equal(t, int32(2), atomic.LoadInt32(batched), "eight reads with a max size of four make two batches")
+	assert.True(t, time.Since(start) < time.Second, "a full batch must be sent without waiting out the window")
+}
+
+// Two callers reading the same id share one alias, and both get the result.
+func TestBatcherDeduplicatesIds(t *testing.T) {
+	var variables int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct{ Variables map[string]string }
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		atomic.StoreInt32(&variables, int32(len(body.Variables)))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(fmt.Sprintf(`{"data":{"r0":{"turbot":{"id":%q}},"r1":{"turbot":{"id":%q}}}}`, body.Variables["id0"], body.Variables["id1"])))
+	}))
+	defer server.Close()
+	client := &Client{Graphql: newGraphqlClient(server.URL + "/graphql"), Batcher: NewBatcher(50*time.Millisecond, 100)}
+	defer holdRead(client.Batcher)()
+
+	var wait sync.WaitGroup
+	for _, id := range []string{"60", "60", "61"} {
+		wait.Add(1)
+		go func(id string) {
+			defer wait.Done()
+			grant, err := client.ReadGrant(id)
+			assert.NoError(t, err)
+			assert.Equal(t, id, grant.Turbot.Id)
+		}(id)
+	}
+	wait.Wait()
+	assert.Equal(t, int32(2), atomic.LoadInt32(&variables), "a repeated id must be sent once")
+}
+
+// An error with no path cannot be attributed to an alias, so every caller falls back to its own
+// single read and gets exactly the error that read produces.
+func TestBatchAliasErrorsIgnoresUnattributedErrors(t *testing.T) {
+	errs := batchAliasErrors(&requestTrace{statusCode: http.StatusOK, body: []byte(`{"errors":[{"message":"Cannot query field \"x\""},{"message":"Not Found: gone","path":["r1","turbot"]},{"message":"second","path":["r1"]}]}`)})
+	assert.Len(t, errs, 1)
+	assert.Contains(t, errs["r1"].Error(), "Not Found: gone", "the first error for an alias wins")
+
+	assert.Nil(t, batchAliasErrors(&requestTrace{}))
+	assert.Nil(t, batchAliasErrors(&requestTrace{body: []byte("<html>bad gateway</html>")}))
+}
+
+// A request that fails outright - here a gateway error - is reported to every caller in the batch
+// rather than repeated once per caller.
+func TestBatcherReportsTransportFailureToEveryCaller(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+	client := &Client{Graphql: newGraphqlClient(server.URL + "/graphql"), Batcher: NewBatcher(50*time.Millisecond, 100)}
+
+	_, errs := readConcurrently(client, []string{"50", "51", "52"})
+	for id, err := range errs {
+		assert.Error(t, err, "id %s", id)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+}
+
+// Reads only coalesce when their selections are identical, so the same properties must always build
+// the same selection, whatever order the map ranges in.
+func TestReadResourceSelectionIsStable(t *testing.T) {
+	properties := []interface{}{map[string]string{"version": "version", "build": "build", "status": "status", "akas": "turbot.akas"}}
+	selection := readResourceSelection(properties)
+	for i := 0; i < 20; i++ {
+		assert.Equal(t, selection, readResourceSelection(properties))
+	}
+	assert.True(t, strings.Index(selection, "akas:") < strings.Index(selection, "build:"))
+	assert.True(t, strings.Index(selection, "build:") < strings.Index(selection, "version:"))
+}
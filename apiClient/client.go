@@ -8,8 +8,8 @@ import (
 	"github.com/go-yaml/yaml"
 	"github.com/machinebox/graphql"
 	"github.com/mitchellh/go-homedir"
-	errorsHandler "github.com/turbot/terraform-provider-turbot/errors"
-	"github.com/turbot/terraform-provider-turbot/helpers"
+	"log"
+	"net"
 	"net/url"
 	"os"
 	"path"
@@ -30,10 +30,21 @@ const DefaultRequestTimeout = 15 * time.Minute
 type Client struct {
 	AccessKey string
 	SecretKey string
+	// Workspace is the url of the workspace the client is connected to
+	Workspace string
 	Graphql   *graphql.Client
 	// RequestTimeout bounds every doRequest. Zero means no deadline; CreateClient installs
 	// DefaultRequestTimeout when the config leaves it unset.
 	RequestTimeout time.Duration
+	// Retry governs how doRequest retries transient failures. The zero value disables retries;
+	// CreateClient installs DefaultRetryPolicy, adjusted by the config.
+	Retry RetryPolicy
+	// Throttle bounds the request rate and concurrency against the workspace. Nil means unlimited;
+	// CreateClient only installs one when the config sets a limit.
+	Throttle *Throttle
+	// Batcher coalesces concurrent single-id reads into one request. Nil disables batching;
+	// CreateClient installs one.
+	Batcher *Batcher
 }
 
 func CreateClient(config ClientConfig) (*Client, error) {
@@ -48,11 +59,31 @@ func CreateClient(config ClientConfig) (*Client, error) {
 	if timeout <= 0 {
 		timeout = DefaultRequestTimeout
 	}
+	retry := DefaultRetryPolicy()
+	if config.MaxRetries != nil {
+		retry.MaxRetries = *config.MaxRetries
+	}
+	if config.RetryMaxBackoff > 0 {
+		retry.MaxBackoff = config.RetryMaxBackoff
+	}
+	var throttle *Throttle
+	if config.MaxRequestsPerSecond > 0 || config.MaxConcurrentRequests > 0 {
+		throttle = NewThrottle(config.MaxRequestsPerSecond, config.MaxConcurrentRequests)
+	}
+	// batched documents vary with timing, so a recording or replaying transport reads one at a time
+	var batcher *Batcher
+	if Transport == nil {
+		batcher = NewBatcher(DefaultBatchWindow, DefaultBatchMaxSize)
+	}
 	return &Client{
 		AccessKey:      credentials.AccessKey,
 		SecretKey:      credentials.SecretKey,
-		Graphql:        graphql.NewClient(credentials.Workspace),
+		Workspace:      workspaceUrl(credentials.Workspace),
+		Graphql:        newGraphqlClient(credentials.Workspace),
 		RequestTimeout: timeout,
+		Retry:          retry,
+		Throttle:       throttle,
+		Batcher:        batcher,
 	}, nil
 }
 
@@ -161,14 +192,19 @@ func BuildApiUrl(rawWorkspace string) (string, error) {
 
 	workspace := strings.TrimSuffix(rawWorkspace, "/")
 
-	// check for "https://"' prefix
-	if !strings.HasPrefix(workspace, "https://") {
+	// check for "https://"' prefix - plain http is only accepted for a loopback workspace, such as
+	// the mock server the acceptance tests can run against (see testing/mockserver)
+	plainHttp := strings.HasPrefix(workspace, "http://")
+	if !plainHttp && !strings.HasPrefix(workspace, "https://") {
 		workspace = "https://" + workspace
 	}
 	u, err := url.Parse(workspace)
 	if err != nil {
 		return "", fmt.Errorf("failed to create client - could not parse workspace url %s, error %s", rawWorkspace, err.Error())
 	}
+	if plainHttp && !isLoopback(u.Hostname()) {
+		return "", fmt.Errorf("failed to create client - workspace url '%s' must use https", rawWorkspace)
+	}
 	if u.Path == "invalid" {
 		return "", fmt.Errorf("failed to create client - could not parse workspace url '%s'", rawWorkspace)
 	}
@@ -187,6 +223,14 @@ func BuildApiUrl(rawWorkspace string) (string, error) {
 	return baseUrl, nil
 }
 
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
 func CredentialsSet(credentials ClientCredentials) bool {
 	return len(credentials.AccessKey) != 0 && len(credentials.SecretKey) != 0 && len(credentials.Workspace) != 0
 }
@@ -244,7 +288,7 @@ func (client *Client) BuildPropertiesFromUpdateSchema(resourceId string, propert
 	responseData := &ResourceResponse{}
 	// execute api call
 	if err := client.doRequest(getResourceQuery, map[string]interface{}{"id": resourceId}, &responseData); err != nil {
-		return nil, fmt.Errorf("error reading resource type id: %s", err.Error())
+		return nil, fmt.Errorf("error reading resource type id: %w", err)
 	}
 
 	resourceTypeId := responseData.Resource.Turbot.ResourceTypeId
@@ -253,32 +297,25 @@ func (client *Client) BuildPropertiesFromUpdateSchema(resourceId string, propert
 	response := &ResourceSchema{}
 	// execute api call
 	if err := client.doRequest(query, map[string]interface{}{"id": resourceTypeId}, &response); err != nil {
-		return nil, fmt.Errorf("error reading resource type id: %s", err.Error())
-	}
-
-	if response.Resource.UpdateSchema == nil {
-		return nil, nil
+		return nil, fmt.Errorf("error reading resource type id: %w", err)
 	}
 
-	var m = response.Resource.UpdateSchema.(map[string]interface{})
-	var excluded []interface{}
-	if value, ok := m["allOf"]; ok {
-		for _, schema := range value.([]interface{}) {
-			if res, ok := schema.(map[string]interface{}); ok {
-				if res["type"] == "object" {
-					// loop to flatten interface, so we will not get this structure - [[id1,id2],[id3,id4]]
-					for _, element := range helpers.GetNullProperties(res) {
-						excluded = append(excluded, element)
-					}
-				}
-			}
-		}
-	}
-	return excluded, nil
+	return UpdateSchemaExcludedProperties(response.Resource.UpdateSchema), nil
 }
 
 // execute graphql request
 func (client *Client) doRequest(query string, vars map[string]interface{}, responseData interface{}) error {
+	if trace, err := client.execute(query, vars, responseData); err != nil {
+		return newAPIError(err, trace, operationName(query))
+	}
+	return nil
+}
+
+// execute runs a GraphQL request, retrying transient failures, and returns the trace of the final
+// attempt along with its raw, unformatted error. The trace keeps the raw response body for callers
+// that need more than machinebox/graphql exposes - doRequest decodes typed errors from it, and the
+// batched read (see batch.go) attributes errors to individual aliases.
+func (client *Client) execute(query string, vars map[string]interface{}, responseData interface{}) (*requestTrace, error) {
 	// make a request
 	req := graphql.NewRequest(query)
 
@@ -293,13 +330,45 @@ func (client *Client) doRequest(query string, vars map[string]interface{}, respo
 	req.Header.Set("Cache-Control", "no-cache")
 	req.Header.Set("Authorization", basicAuthHeader(client.AccessKey, client.SecretKey))
 
-	// Bound every request with a deadline. Without one, a hung connection hangs the whole apply
+	// Transient failures - throttling, a gateway blip, a dropped connection - are retried per
+	// client.Retry rather than failing the whole apply. See shouldRetry for what is retried, and
+	// why a mutation is only replayed when it provably never reached the server.
+	mutation := isMutation(query)
+	if mutation {
+		// Invalidate whether or not the mutation succeeds: a failed call may still have been
+		// applied, and a spurious cache miss only costs one read.
+		defer invalidateMutationTarget(query, vars)
+	}
+	for attempt := 0; ; attempt++ {
+		trace := &requestTrace{}
+		err := client.runRequest(req, trace, responseData)
+		if err == nil {
+			return trace, nil
+		}
+		if attempt >= client.Retry.MaxRetries || !shouldRetry(err, trace, mutation) {
+			return trace, err
+		}
+		delay := client.Retry.retryDelay(attempt+1, trace.retryAfterHeader())
+		log.Printf("[WARN] Guardrails API request failed with a transient error, retrying in %s (retry %d of %d): %s", delay, attempt+1, client.Retry.MaxRetries, err)
+		time.Sleep(delay)
+	}
+}
+
+// runRequest makes a single attempt at req, recording what happened on the wire into trace.
+func (client *Client) runRequest(req *graphql.Request, trace *requestTrace, responseData interface{}) error {
+	// Throttle per attempt rather than per doRequest: a retry is real load on the workspace, and a
+	// request sleeping out its backoff must not hold an in-flight slot. The wait for a slot happens
+	// before the deadline below starts, so queueing behind a busy apply does not eat into
+	// RequestTimeout.
+	defer client.Throttle.acquire()()
+
+	// Bound every attempt with a deadline. Without one, a hung connection hangs the whole apply
 	// indefinitely - and since attachment writes now serialise per target (see
 	// smart_folder_attachment.go), a hung call also holds that target's lock and stalls every
 	// sibling write. A zero timeout means "no deadline"; CreateClient installs a default so a
 	// client built through the provider is always bounded, but a hand-built Client (e.g. in tests)
 	// opts in explicitly.
-	ctx := context.Background()
+	ctx := withRequestTrace(context.Background(), trace)
 	if client.RequestTimeout > 0 {
 		var cancel context.CancelFunc
 		ctx, cancel = context.WithTimeout(ctx, client.RequestTimeout)
@@ -307,32 +376,28 @@ func (client *Client) doRequest(query string, vars map[string]interface{}, respo
 	}
 
 	// run it and capture the response
-	if err := client.Graphql.Run(ctx, req, &responseData); err != nil {
-		err = errorsHandler.BuildErrorMessage(err)
-		return err
-	}
-	return nil
+	return client.Graphql.Run(ctx, req, &responseData)
 }
 
 func (client *Client) handleCreateError(err error, input map[string]interface{}, resourceType string) error {
 	parent := input["parent"]
-	if errorsHandler.NotFoundError(err) {
-		return errors.New(fmt.Sprintf("error creating %s: parent resource not found: %s", resourceType, parent))
+	if errors.Is(err, ErrNotFound) {
+		return wrapError(err, "error creating %s: parent resource not found: %s", resourceType, parent)
 	}
-	return errors.New(fmt.Sprintf("error creating %s: %s ", resourceType, err.Error()))
+	return wrapError(err, "error creating %s: %s ", resourceType, err.Error())
 }
 
 func (client *Client) handleReadError(err error, resource string, resourceType string) error {
-	if errorsHandler.NotFoundError(err) {
-		return errors.New(fmt.Sprintf("error reading %s: resource not found: %s", resourceType, resource))
+	if errors.Is(err, ErrNotFound) {
+		return wrapError(err, "error reading %s: resource not found: %s", resourceType, resource)
 	}
-	return errors.New(fmt.Sprintf("error reading %s: %s ", resourceType, err.Error()))
+	return wrapError(err, "error reading %s: %s ", resourceType, err.Error())
 }
 
 func (client *Client) handleUpdateError(err error, input map[string]interface{}, resourceType string) error {
 	resource := input["id"]
-	if errorsHandler.NotFoundError(err) {
-		return errors.New(fmt.Sprintf("error updating %s: resource not found: %s", resourceType, resource))
+	if errors.Is(err, ErrNotFound) {
+		return wrapError(err, "error updating %s: resource not found: %s", resourceType, resource)
 	}
-	return errors.New(fmt.Sprintf("error updating %s: %s ", resourceType, err.Error()))
+	return wrapError(err, "error updating %s: %s ", resourceType, err.Error())
 }
@@ -9,6 +9,15 @@ type ClientConfig struct {
 	// RequestTimeout bounds every GraphQL request. Zero leaves it to CreateClient, which
 	// installs DefaultRequestTimeout.
 	RequestTimeout time.Duration
+	// MaxRetries overrides DefaultMaxRetries. A pointer because zero is meaningful - it disables
+	// retries - so nil is the only way to say "use the default".
+	MaxRetries *int
+	// RetryMaxBackoff caps a single retry wait. Zero leaves DefaultRetryMaxBackoff.
+	RetryMaxBackoff time.Duration
+	// MaxRequestsPerSecond bounds the sustained request rate. Zero means unlimited.
+	MaxRequestsPerSecond float64
+	// MaxConcurrentRequests bounds how many requests are in flight at once. Zero means unlimited.
+	MaxConcurrentRequests int
 }
 
 type ClientCredentials struct {
@@ -89,3 +89,31 @@ func TestCredentialsPrecedence(t *testing.T) {
 		}
 	}
 }
+
+func TestBuildApiUrl(t *testing.T) {
+	var tests = []struct {
+		workspace string
+		expected  string
+		err       bool
+	}{
+		{"bananaman-turbot.putney.turbot.io", "https://bananaman-turbot.putney.turbot.io/api/latest/graphql", false},
+		{"https://bananaman-turbot.putney.turbot.io/", "https://bananaman-turbot.putney.turbot.io/api/latest/graphql", false},
+		{"https://bananaman-turbot.putney.turbot.io/api/v5", "https://bananaman-turbot.putney.turbot.io/api/v5/graphql", false},
+		{"http://127.0.0.1:8080", "http://127.0.0.1:8080/api/latest/graphql", false},
+		{"http://localhost:8080/api/latest", "http://localhost:8080/api/latest/graphql", false},
+		{"http://[::1]:8080", "http://[::1]:8080/api/latest/graphql", false},
+		{"http://bananaman-turbot.putney.turbot.io", "", true},
+		{"https://bananaman-turbot.putney.turbot.io/foo", "", true},
+	}
+	for _, test := range tests {
+		t.Run(test.workspace, func(t *testing.T) {
+			url, err := BuildApiUrl(test.workspace)
+			if test.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, url)
+		})
+	}
+}
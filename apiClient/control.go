@@ -11,13 +11,40 @@ func (client *Client) ReadControl(args string) (*Control, error) {
 	// execute api call
 	err := client.doRequest(query, nil, responseData)
 	if err != nil {
-		return nil, fmt.Errorf("error reading control: %s", err.Error())
+		return nil, fmt.Errorf("error reading control: %w", err)
 	}
 	control := responseData.Control
 
 	return &control, nil
 }
 
+// ReadControlById reads the control with the given id
+func (client *Client) ReadControlById(id string) (*Control, error) {
+	var responseData = &ReadControlResponse{}
+
+	// execute api call
+	if err := client.doRequest(readControlByIdQuery(), map[string]interface{}{"id": id}, responseData); err != nil {
+		return nil, fmt.Errorf("error reading control: %w", err)
+	}
+	return &responseData.Control, nil
+}
+
+// ReadAllControls returns every control matching filter, reading all the pages of the result
+func (client *Client) ReadAllControls(filter string) ([]ControlListItem, error) {
+	var controls []ControlListItem
+	variables := map[string]interface{}{"filter": []string{filter}}
+	newPage := func() pagedResponse { return &ReadControlPageResponse{} }
+	collect := func(page pagedResponse) {
+		controls = append(controls, page.(*ReadControlPageResponse).ControlList.Items...)
+	}
+
+	// execute api calls
+	if err := client.readAllPages(readControlPageQuery(), variables, newPage, collect); err != nil {
+		return nil, fmt.Errorf("error fetching control list: %w", err)
+	}
+	return controls, nil
+}
+
 func (client *Client) MuteControl(input map[string]interface{}) (*MuteControl, error) {
 	query := muteControlMutation()
 	responseData := &MuteControlResponse{}
@@ -23,13 +23,29 @@ func (client *Client) ReadGrant(id string) (*Grant, error) {
 	query := readGrantQuery()
 	responseData := &ReadGrantResponse{}
 
-	// execute api call
-	if err := client.doRequest(query, map[string]interface{}{"id": id}, responseData); err != nil {
+	// execute api call - coalesced with concurrent grant reads, see Batcher
+	if err := client.readById(query, "grant", readGrantSelection(), id, &responseData.Grant); err != nil {
 		return nil, client.handleReadError(err, id, "grant")
 	}
 	return &responseData.Grant, nil
 }
 
+// ReadAllGrants returns every grant matching filter, reading all the pages of the result
+func (client *Client) ReadAllGrants(filter string) ([]Grant, error) {
+	var grants []Grant
+	variables := map[string]interface{}{"filter": []string{filter}}
+	newPage := func() pagedResponse { return &ReadGrantPageResponse{} }
+	collect := func(page pagedResponse) {
+		grants = append(grants, page.(*ReadGrantPageResponse).Grants.Items...)
+	}
+
+	// execute api calls
+	if err := client.readAllPages(readGrantPageQuery(), variables, newPage, collect); err != nil {
+		return nil, fmt.Errorf("error fetching grant list: %w", err)
+	}
+	return grants, nil
+}
+
 func (client *Client) DeleteGrant(id string) error {
 	query := deleteGrantMutation()
 	var responseData interface{}
@@ -41,7 +57,7 @@ func (client *Client) DeleteGrant(id string) error {
 
 	// execute api call
 	if err := client.doRequest(query, variables, &responseData); err != nil {
-		return fmt.Errorf("error deleting grant: %s", err.Error())
+		return fmt.Errorf("error deleting grant: %w", err)
 	}
 	return nil
 }
@@ -41,7 +41,7 @@ func (client *Client) DeleteGrantActivation(id string) error {
 
 	// execute api call
 	if err := client.doRequest(query, variables, &responseData); err != nil {
-		return fmt.Errorf("error deleting grant activation: %s", err.Error())
+		return fmt.Errorf("error deleting grant activation: %w", err)
 	}
 	return nil
 }
@@ -54,3 +54,19 @@ func (client *Client) GrantActivationExists(id string) (bool, error) {
 	exists := grantActivate.Turbot.Id != ""
 	return exists, nil
 }
+
+// ReadAllActiveGrants returns every active grant matching filter, reading all the pages of the result
+func (client *Client) ReadAllActiveGrants(filter string) ([]ActiveGrantListItem, error) {
+	var activeGrants []ActiveGrantListItem
+	variables := map[string]interface{}{"filter": []string{filter}}
+	newPage := func() pagedResponse { return &ReadActiveGrantPageResponse{} }
+	collect := func(page pagedResponse) {
+		activeGrants = append(activeGrants, page.(*ReadActiveGrantPageResponse).ActiveGrants.Items...)
+	}
+
+	// execute api calls
+	if err := client.readAllPages(readActiveGrantPageQuery(), variables, newPage, collect); err != nil {
+		return nil, fmt.Errorf("error fetching active grant list: %w", err)
+	}
+	return activeGrants, nil
+}
@@ -0,0 +1,66 @@
+package apiClient
+
+import (
+	"fmt"
+)
+
+// AddGroupProfileMember makes a profile a member of a group profile. Both are given by id or aka.
+func (client *Client) AddGroupProfileMember(groupProfileAka, profileAka string) (*GroupMembership, error) {
+	responseData := &AddGroupProfileMemberResponse{}
+	input := map[string]interface{}{
+		"groupProfile": groupProfileAka,
+		"profile":      profileAka,
+	}
+
+	// execute api call
+	if err := client.doRequest(addGroupProfileMemberMutation(), map[string]interface{}{"input": input}, responseData); err != nil {
+		return nil, client.handleCreateError(err, input, "group membership")
+	}
+	return &responseData.GroupMembership, nil
+}
+
+// RemoveGroupProfileMember removes a profile from the members of a group profile
+func (client *Client) RemoveGroupProfileMember(groupProfileAka, profileAka string) error {
+	// we do not care about the response
+	var responseData interface{}
+	input := map[string]interface{}{
+		"groupProfile": groupProfileAka,
+		"profile":      profileAka,
+	}
+
+	// execute api call
+	if err := client.doRequest(removeGroupProfileMemberMutation(), map[string]interface{}{"input": input}, &responseData); err != nil {
+		return fmt.Errorf("error deleting group membership: %w", err)
+	}
+	return nil
+}
+
+// ReadGroupProfileMembers returns every profile that is a member of the group profile, reading all
+// the pages of the result
+func (client *Client) ReadGroupProfileMembers(groupProfileAka string) ([]Profile, error) {
+	return client.readGroupProfileMembers(fmt.Sprintf("groupProfile:%s", groupProfileAka))
+}
+
+// ReadGroupProfileMember returns the profile if it is a member of the group profile, and nil if not
+func (client *Client) ReadGroupProfileMember(groupProfileAka, profileAka string) (*Profile, error) {
+	members, err := client.readGroupProfileMembers(fmt.Sprintf("groupProfile:%s resource:%s level:self", groupProfileAka, profileAka))
+	if err != nil || len(members) == 0 {
+		return nil, err
+	}
+	return &members[0], nil
+}
+
+func (client *Client) readGroupProfileMembers(filter string) ([]Profile, error) {
+	var members []Profile
+	variables := map[string]interface{}{"filter": []string{filter}}
+	newPage := func() pagedResponse { return &ReadGroupProfileMemberPageResponse{} }
+	collect := func(page pagedResponse) {
+		members = append(members, page.(*ReadGroupProfileMemberPageResponse).ResourceList.Items...)
+	}
+
+	// execute api calls
+	if err := client.readAllPages(readGroupProfileMemberPageQuery(), variables, newPage, collect); err != nil {
+		return nil, fmt.Errorf("error fetching group profile members: %w", err)
+	}
+	return members, nil
+}
@@ -4,9 +4,9 @@ import (
 	"fmt"
 )
 
+// the directory of a group profile is its parent
 var groupProfileProperties = []interface{}{
 	map[string]string{"parent": "turbot.parentId"},
-	"directory",
 	"title",
 	"status",
 	"groupProfileId",
@@ -64,7 +64,7 @@ func (client *Client) DeleteGroupProfile(aka string) error {
 
 	// execute api call
 	if err := client.doRequest(query, variables, &responseData); err != nil {
-		return fmt.Errorf("error deleting resource: %s", err.Error())
+		return fmt.Errorf("error deleting group profile: %w", err)
 	}
 	return nil
 }
@@ -98,7 +98,7 @@ func (client *Client) DeleteLdapDirectory(aka string) error {
 
 	// execute api call
 	if err := client.doRequest(query, variables, &responseData); err != nil {
-		return fmt.Errorf("error deleting ldap directory: %s", err.Error())
+		return fmt.Errorf("error deleting ldap directory: %w", err)
 	}
 	return nil
 }
@@ -15,7 +15,7 @@ func (client *Client) InstallMod(input map[string]interface{}) (*InstallModData,
 
 	// execute api call
 	if err := client.doRequest(query, variables, responseData); err != nil {
-		return nil, fmt.Errorf("error installing mod: %s", err.Error())
+		return nil, fmt.Errorf("error installing mod: %w", err)
 	}
 	return &responseData.Mod, nil
 }
@@ -61,7 +61,7 @@ func (client *Client) UninstallMod(modId string) error {
 
 	// execute api call
 	if err := client.doRequest(query, variables, responseData); err != nil {
-		return fmt.Errorf("error uninstalling mod: %s", err.Error())
+		return fmt.Errorf("error uninstalling mod: %w", err)
 	}
 	if !responseData.UninstallMod.Success {
 		return fmt.Errorf(" uninstallMod mutation ran with no errors but failed to uninstall the mod")
@@ -77,7 +77,7 @@ func (client *Client) GetModVersions(org, mod string) ([]ModRegistryVersion, err
 
 	// execute api call
 	if err := client.doRequest(query, variables, responseData); err != nil {
-		return nil, fmt.Errorf("error fetching mod versions mod: %s", err.Error())
+		return nil, fmt.Errorf("error fetching mod versions mod: %w", err)
 	}
 
 	return responseData.Versions.Items, nil
@@ -0,0 +1,44 @@
+package apiClient
+
+import "fmt"
+
+// Paging is the cursor block of a list query's response. Next is empty on the last page.
+type Paging struct {
+	Next     string
+	Previous string
+}
+
+// pagedResponse is the response to one page of a list query
+type pagedResponse interface {
+	paging() Paging
+}
+
+// readAllPages runs a list query page by page - each page's paging.next is passed back as $paging -
+// until the last page. newPage returns the response to decode each page into, and collect is called
+// with each page once it is decoded. A workspace that hands back a cursor it has already returned
+// would otherwise page forever, so that is an error.
+func (client *Client) readAllPages(query string, variables map[string]interface{}, newPage func() pagedResponse, collect func(pagedResponse)) error {
+	seen := map[string]bool{}
+	var cursor interface{}
+	for {
+		pageVariables := map[string]interface{}{"paging": cursor}
+		for name, value := range variables {
+			pageVariables[name] = value
+		}
+		page := newPage()
+		if err := client.doRequest(query, pageVariables, page); err != nil {
+			return err
+		}
+		collect(page)
+
+		next := page.paging().Next
+		if next == "" {
+			return nil
+		}
+		if seen[next] {
+			return fmt.Errorf("list query returned paging cursor %q twice", next)
+		}
+		seen[next] = true
+		cursor = next
+	}
+}
@@ -0,0 +1,70 @@
+package apiClient
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// pagingServer answers ReadResourcePage with pages of one resource each, the cursor being the index
+// of the next page. Once cursors run out it keeps returning loopCursor, if set.
+func pagingServer(t *testing.T, pages int, loopCursor string) (*httptest.Server, *[]interface{}) {
+	var cursors []interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Variables map[string]interface{}
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		cursor := body.Variables["paging"]
+		cursors = append(cursors, cursor)
+		assert.Equal(t, []interface{}{"resourceType:folder"}, body.Variables["filter"])
+
+		index := 0
+		if cursor != nil {
+			fmt.Sscanf(cursor.(string), "page-%d", &index)
+		}
+		next := "null"
+		if index+1 < pages {
+			next = fmt.Sprintf(`"page-%d"`, index+1)
+		} else if loopCursor != "" {
+			next = fmt.Sprintf("%q", loopCursor)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(fmt.Sprintf(`{"data":{"resourceList":{"items":[{"type":{"uri":"folder"},"data":{"title":"%d","turbot":{}},"turbot":{"id":"%d"}}],"paging":{"next":%s}}}}`, index, index, next)))
+	}))
+	return server, &cursors
+}
+
+func TestReadAllResourcesFollowsPaging(t *testing.T) {
+	server, cursors := pagingServer(t, 3, "")
+	defer server.Close()
+	client := &Client{Graphql: newGraphqlClient(server.URL + "/graphql")}
+
+	resources, err := client.ReadAllResources("resourceType:folder")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []interface{}{nil, "page-1", "page-2"}, *cursors)
+	if assert.Len(t, resources, 3) {
+		for i, resource := range resources {
+			assert.Equal(t, fmt.Sprint(i), resource.Turbot.Id)
+			assert.Equal(t, map[string]interface{}{"title": fmt.Sprint(i)}, resource.Data)
+		}
+	}
+}
+
+// a cursor that comes round again would page forever
+func TestReadAllResourcesStopsOnRepeatedCursor(t *testing.T) {
+	server, cursors := pagingServer(t, 2, "page-1")
+	defer server.Close()
+	client := &Client{Graphql: newGraphqlClient(server.URL + "/graphql")}
+
+	_, err := client.ReadAllResources("resourceType:folder")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `"page-1" twice`)
+	assert.Len(t, *cursors, 2)
+}
@@ -9,10 +9,10 @@ import (
 // response shape - `resource: null`, via the query's notFound: RETURN_NULL option - and never from
 // error text. Exists uses it to drop an attachment whose target is gone.
 //
-// The alternative, errors.NotFoundError, matches `(?i)not found` anywhere in an error string, so an
-// unrelated not-found ("policy type not found") would also drop the attachment from state and have
-// Terraform recreate it. That helper is used across the provider and is left alone; this narrows
-// only the decision that governs state membership for attachments.
+// ErrNotFound is not enough here: it reports that the read failed because SOMETHING was missing,
+// which for an attachment may be the policy pack rather than the target, and dropping the
+// attachment from state would then have Terraform recreate it against a pack that does not exist.
+// This narrows the decision that governs state membership for attachments to the target alone.
 var ErrTargetNotFound = errors.New("attachment target not found")
 
 // IsTargetNotFound reports whether err is ErrTargetNotFound. Provided so callers in the turbot
@@ -22,8 +22,8 @@ func (client *Client) ReadPolicySetting(id string) (*PolicySetting, error) {
 	query := readPolicySettingQuery()
 	responseData := &PolicySettingResponse{}
 
-	// execute api call
-	if err := client.doRequest(query, map[string]interface{}{"id": id}, responseData); err != nil {
+	// execute api call - coalesced with concurrent policy setting reads, see Batcher
+	if err := client.readById(query, "policySetting", readPolicySettingSelection(), id, &responseData.PolicySetting); err != nil {
 		return nil, client.handleReadError(err, id, "policy setting")
 	}
 	return &responseData.PolicySetting, nil
@@ -53,7 +53,7 @@ func (client *Client) DeletePolicySetting(id string) error {
 	}
 	// execute api call
 	if err := client.doRequest(query, variables, responseData); err != nil {
-		return fmt.Errorf("error deleting policy: %s", err.Error())
+		return fmt.Errorf("error deleting policy: %w", err)
 	}
 	return nil
 }
@@ -78,3 +78,20 @@ func (client *Client) FindPolicySetting(policyTypeUri, resourceAka string) (Poli
 	}
 	return PolicySetting{}, nil
 }
+
+// ReadAllPolicySettings returns every policy setting matching filter, reading all the pages of the
+// result
+func (client *Client) ReadAllPolicySettings(filter string) ([]PolicySetting, error) {
+	var settings []PolicySetting
+	variables := map[string]interface{}{"filter": []string{filter}}
+	newPage := func() pagedResponse { return &ReadPolicySettingPageResponse{} }
+	collect := func(page pagedResponse) {
+		settings = append(settings, page.(*ReadPolicySettingPageResponse).PolicySettingList.Items...)
+	}
+
+	// execute api calls
+	if err := client.readAllPages(readPolicySettingPageQuery(), variables, newPage, collect); err != nil {
+		return nil, fmt.Errorf("error fetching policy setting list: %w", err)
+	}
+	return settings, nil
+}
@@ -2,6 +2,8 @@ package apiClient
 
 import "fmt"
 
+const policyTypeResourceType = "tmod:@turbot/turbot#/resource/types/policyType"
+
 func (client *Client) FindPolicyType(policyTypeUri string) (PolicyType, error) {
 	responseData := &FindPolicyTypeResponse{}
 
@@ -21,3 +23,17 @@ func (client *Client) FindPolicyType(policyTypeUri string) (PolicyType, error) {
 
 	return PolicyType{}, nil
 }
+
+// ReadPolicyType reads the definition of the policy type with the given uri or id
+func (client *Client) ReadPolicyType(policyTypeUri string) (*PolicyTypeDefinition, error) {
+	responseData := &ReadPolicyTypeResponse{}
+
+	// execute api call
+	if err := client.doRequest(readPolicyTypeQuery(), map[string]interface{}{"id": policyTypeUri}, responseData); err != nil {
+		return nil, client.handleReadError(err, policyTypeUri, "policy type")
+	}
+	if responseData.PolicyType.Type.Uri != policyTypeResourceType {
+		return nil, fmt.Errorf("error reading policy type: %s is a %s, not a policy type", policyTypeUri, responseData.PolicyType.Type.Uri)
+	}
+	return &responseData.PolicyType, nil
+}
@@ -3,6 +3,7 @@ package apiClient
 import (
 	"bytes"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/blang/semver"
@@ -87,9 +88,17 @@ func createPolicySettingMutation() string {
 // TestReadBuildersDoNotInterpolateIdentifier). policySetting.id is a nullable ID; a non-null
 // variable is assignable to it.
 func readPolicySettingQuery() string {
-	return `query ReadPolicySetting($id: ID!) {
+	return fmt.Sprintf(`query ReadPolicySetting($id: ID!) {
 policySetting(id: $id) {
-	type {
+%s
+}
+}`, readPolicySettingSelection())
+}
+
+// readPolicySettingSelection is the field selection read for a policy setting, shared by the single
+// read and the batched read (see batchReadQuery).
+func readPolicySettingSelection() string {
+	return `	type {
 		uri
 	}
 	value: secretValue
@@ -105,9 +114,7 @@ policySetting(id: $id) {
 	turbot {
 		id
 		resourceId
-	}
-}
-}`
+	}`
 }
 
 func updatePolicySettingMutation() string {
@@ -178,6 +185,36 @@ func findPolicySettingQuery() string {
 `
 }
 
+// readPolicySettingPageQuery reads one page of the policy settings matching a filter. The filter and
+// the paging cursor are GraphQL variables, never interpolated.
+func readPolicySettingPageQuery() string {
+	return `query ReadPolicySettingPage($filter: [String!], $paging: String) {
+	policySettingList(filter: $filter, paging: $paging) {
+		items {
+			type {
+				uri
+			}
+			value: secretValue
+			valueSource: secretValueSource
+			default
+			precedence
+			template
+			templateInput
+			note
+			validFromTimestamp
+			validToTimestamp
+			turbot {
+				id
+				resourceId
+			}
+		}
+		paging {
+			next
+		}
+	}
+}`
+}
+
 // policy value
 //
 // uri and resourceId are passed as GraphQL variables, never interpolated - both are config-reachable
@@ -214,6 +251,7 @@ func findPolicyTypeQuery() string {
   policyTypes: policyTypes(filter: $filter) {
     items {
 		modUri
+		schema
 		turbot {
 			id
 		}
@@ -223,6 +261,46 @@ func findPolicyTypeQuery() string {
 `
 }
 
+// readPolicyTypeQuery reads a policy type's definition, which is a resource like any other. The id -
+// a policy type uri or id - is a GraphQL variable, never interpolated.
+func readPolicyTypeQuery() string {
+	return `query ReadPolicyType($id: ID!) {
+	policyType: resource(id: $id) {
+		type {
+			uri
+		}
+		title: get(path:"title")
+		description: get(path:"description")
+		schema: get(path:"schema")
+		default: get(path:"default")
+		defaultTemplate: get(path:"defaultTemplate")
+		defaultTemplateInput: get(path:"defaultTemplateInput")
+		targets: get(path:"targets")
+		secret: get(path:"secret")
+		turbot: get(path:"turbot")
+	}
+}`
+}
+
+// readResourceTypeQuery reads a resource type's definition. As for readPolicyTypeQuery, the id is a
+// GraphQL variable, never interpolated.
+func readResourceTypeQuery() string {
+	return `query ReadResourceType($id: ID!) {
+	resourceType: resource(id: $id) {
+		type {
+			uri
+		}
+		title: get(path:"title")
+		description: get(path:"description")
+		category: get(path:"category")
+		parent: get(path:"parent")
+		createSchema: get(path:"createSchema")
+		updateSchema: get(path:"updateSchema")
+		turbot: get(path:"turbot")
+	}
+}`
+}
+
 // watch
 func createWatchMutation() string {
 	return fmt.Sprintf(`mutation CreateWatch($input: CreateWatchInput!) {
@@ -437,13 +515,19 @@ func deleteResourceMutation() string {
 func readResourceQuery(properties []interface{}) string {
 	return fmt.Sprintf(`query ReadResource($id: ID!) {
 	resource(id: $id) {
-		type {
+%s
+  	}
+}`, readResourceSelection(properties))
+}
+
+// readResourceSelection is the field selection read for a resource, shared by the single read and
+// the batched read (see batchReadQuery).
+func readResourceSelection(properties []interface{}) string {
+	return fmt.Sprintf(`		type {
 			uri
 		}
 %s
-		turbot: get(path:"turbot")
-  	}
-}`, buildResourceProperties(properties))
+		turbot: get(path:"turbot")`, buildResourceProperties(properties))
 }
 
 func getResourceTypeIdQuery() string {
@@ -461,11 +545,9 @@ func getResourceTypeIdQuery() string {
 // from a caller-supplied field-name map, never from a config identifier - is interpolated into
 // get(path:"..."). See TestNoBuilderInterpolatesIntoQuotedArg.
 func readResourceListQuery(properties map[string]string) string {
-	var propertiesString bytes.Buffer
+	var propertiesArray []interface{}
 	if properties != nil {
-		for alias, propertyPath := range properties {
-			propertiesString.WriteString(fmt.Sprintf("\t\t\t%s: get(path: \"%s\")\n", alias, propertyPath))
-		}
+		propertiesArray = []interface{}{properties}
 	}
 	return fmt.Sprintf(`query ReadResourceList($filter: [String!]) {
 	resourceList(filter: $filter) {
@@ -474,7 +556,44 @@ func readResourceListQuery(properties map[string]string) string {
 			turbot: get(path:"turbot")
 		}
 	}
-}`, propertiesString.String())
+}`, buildResourceProperties(propertiesArray))
+}
+
+// readResourcePageQuery reads one page of the resources matching a filter. The filter and the paging
+// cursor are GraphQL variables, never interpolated.
+func readResourcePageQuery() string {
+	return `query ReadResourcePage($filter: [String!], $paging: String) {
+	resourceList(filter: $filter, paging: $paging) {
+		items {
+			type {
+				uri
+			}
+			data
+			turbot: get(path:"turbot")
+		}
+		paging {
+			next
+		}
+	}
+}`
+}
+
+// readResourceMetadataPageQuery is readResourcePageQuery without the resource data, for a caller which
+// only needs the type and turbot metadata of many resources
+func readResourceMetadataPageQuery() string {
+	return `query ReadResourceMetadataPage($filter: [String!], $paging: String) {
+	resourceList(filter: $filter, paging: $paging) {
+		items {
+			type {
+				uri
+			}
+			turbot: get(path:"turbot")
+		}
+		paging {
+			next
+		}
+	}
+}`
 }
 
 func readFullResourceQuery() string {
@@ -515,11 +634,33 @@ func readGoogleDirectoryQuery() string {
 func readGrantQuery() string {
 	return fmt.Sprintf(`query ReadGrant($id: ID!) {
 	grant: grant(id: $id) {
-		permissionTypeId
+%s
+	}
+  }`, readGrantSelection())
+}
+
+// readGrantSelection is the field selection read for a grant, shared by the single read and the
+// batched read (see batchReadQuery).
+func readGrantSelection() string {
+	return fmt.Sprintf(`		permissionTypeId
 		permissionLevelId
-		%s
+		%s`, turbotGrantMetadataFragment("\t\t"))
+}
+
+// batchReadQuery aliases count reads of the same shape - field(id: $idN) { selection } - into one
+// document, answered as r0, r1, ... (see batchAlias). Each identifier is its own $idN variable, so
+// the document depends only on field, selection and count, never on a caller-supplied value; field
+// and selection come from the fixed builders above. See TestBatchReadQueryUsesIdVariables.
+func batchReadQuery(field, selection string, count int) string {
+	var variables, fields bytes.Buffer
+	for i := 0; i < count; i++ {
+		if i > 0 {
+			variables.WriteString(", ")
+		}
+		variables.WriteString(fmt.Sprintf("$id%d: ID!", i))
+		fields.WriteString(fmt.Sprintf("\t%s: %s(id: $id%d) {\n%s\n\t}\n", batchAlias(i), field, i, selection))
 	}
-  }`, turbotGrantMetadataFragment("\t\t"))
+	return fmt.Sprintf("query BatchRead(%s) {\n%s}", variables.String(), fields.String())
 }
 
 func createGrantMutation() string {
@@ -538,6 +679,20 @@ func deleteGrantMutation() string {
 }`, turbotGrantMetadataFragment("\t\t\t"))
 }
 
+// readGrantPageQuery reads a page of the grants matching a filter
+func readGrantPageQuery() string {
+	return fmt.Sprintf(`query ReadGrantPage($filter: [String!], $paging: String) {
+	grants(filter: $filter, paging: $paging) {
+		items {
+	%s
+		}
+		paging {
+			next
+		}
+	}
+}`, readGrantSelection())
+}
+
 // active grant
 func readActiveGrantQuery() string {
 	return fmt.Sprintf(`query ReadActiveGrant($id: ID!) {
@@ -547,6 +702,36 @@ func readActiveGrantQuery() string {
 }`, turbotActiveGrantMetadataFragment("\t\t"))
 }
 
+// readActiveGrantPageQuery reads a page of the active grants matching a filter, with the permission
+// type and level each grants
+func readActiveGrantPageQuery() string {
+	return `query ReadActiveGrantPage($filter: [String!], $paging: String) {
+	activeGrants(filter: $filter, paging: $paging) {
+		items {
+			grant {
+				type {
+					uri
+				}
+				level {
+					uri
+				}
+				turbot {
+					profileId
+				}
+			}
+			turbot {
+				id
+				grantId
+				resourceId
+			}
+		}
+		paging {
+			next
+		}
+	}
+}`
+}
+
 func activateGrantMutation() string {
 	return fmt.Sprintf(`mutation ActivateGrant($input: ActivateGrantInput!) {
 	grantActivate: activateGrant(input: $input) {
@@ -588,8 +773,15 @@ func buildResourceProperties(resourceProperties []interface{}) string {
 		for _, propertyPath := range resourceProperties {
 			property, ok := propertyPath.(map[string]string)
 			if ok {
-				for alias, property := range property {
-					propertiesString.WriteString(fmt.Sprintf("\t\t\t%s: get(path: \"%s\")\n", alias, property))
+				// write the aliases in order, so the same properties always make the same query - the
+				// batcher and the test cassettes both key on the query text
+				var aliases []string
+				for alias := range property {
+					aliases = append(aliases, alias)
+				}
+				sort.Strings(aliases)
+				for _, alias := range aliases {
+					propertiesString.WriteString(fmt.Sprintf("\t\t\t%s: get(path: \"%s\")\n", alias, property[alias]))
 				}
 			} else {
 				propertiesString.WriteString(fmt.Sprintf("\t\t\t%s: get(path: \"%s\")\n", propertyPath, propertyPath))
@@ -660,7 +852,20 @@ func updateSamlDirectoryMutation(properties []interface{}) string {
 // control
 func readControlQuery(args string) string {
 	return fmt.Sprintf(`{
-control(%s){
+control(%s)%s
+}`, args, readControlSelection)
+}
+
+// readControlByIdQuery reads a control by id. The id is a GraphQL variable, never interpolated - it
+// comes from the config, or from the user on import.
+func readControlByIdQuery() string {
+	return `query ReadControl($id: ID!) {
+control(id: $id)` + readControlSelection + `
+}`
+}
+
+// the fields of a control the client reads
+const readControlSelection = `{
 	type{
 		uri
 	}
@@ -672,8 +877,30 @@ control(%s){
 		id
 		resourceId
 	}
-}
-}`, args)
+}`
+
+// readControlPageQuery reads one page of the controls matching a filter. The filter and the paging
+// cursor are GraphQL variables, never interpolated.
+func readControlPageQuery() string {
+	return `query ReadControlPage($filter: [String!], $paging: String) {
+	controlList(filter: $filter, paging: $paging) {
+		items {
+			type {
+				uri
+			}
+			state
+			reason
+			details
+			turbot {
+				id
+				resourceId
+			}
+		}
+		paging {
+			next
+		}
+	}
+}`
 }
 
 func muteControlMutation() string {
@@ -735,6 +962,47 @@ func deleteGroupProfileMutation() string {
 }`)
 }
 
+// group membership
+func addGroupProfileMemberMutation() string {
+	return `mutation AddGroupProfileMember($input: AddGroupProfileMemberInput!) {
+	groupMembership: addGroupProfileMember(input: $input) {
+		turbot {
+			groupProfileId
+			profileId
+		}
+	}
+}`
+}
+
+func removeGroupProfileMemberMutation() string {
+	return `mutation RemoveGroupProfileMember($input: RemoveGroupProfileMemberInput!) {
+	groupMembership: removeGroupProfileMember(input: $input) {
+		turbot {
+			groupProfileId
+			profileId
+		}
+	}
+}`
+}
+
+// readGroupProfileMemberPageQuery reads a page of the profiles matching a filter - the members of a
+// group profile, when the filter has a groupProfile term
+func readGroupProfileMemberPageQuery() string {
+	return `query ReadGroupProfileMemberPage($filter: [String!], $paging: String) {
+	resourceList(filter: $filter, paging: $paging) {
+		items {
+			title: get(path:"title")
+			email: get(path:"email")
+			profileId: get(path:"profileId")
+			turbot: get(path:"turbot")
+		}
+		paging {
+			next
+		}
+	}
+}`
+}
+
 // ldap directory
 func createLdapDirectoryMutation(properties []interface{}) string {
 	return fmt.Sprintf(`mutation createLdapDirectory($input: CreateLdapDirectoryInput!) {
@@ -762,6 +1030,18 @@ func deleteLdapDirectory() string {
 }`
 }
 
+// readActorQuery reads the profile the caller's access key belongs to
+func readActorQuery() string {
+	return `query ReadActor {
+	actor {
+		identity {
+			email: get(path:"email")
+			turbot: get(path:"turbot")
+		}
+	}
+}`
+}
+
 // get turbot workspace version
 func (client *Client) GetTurbotWorkspaceVersion() (*semver.Version, error) {
 	query := readPolicyValueQuery()
@@ -772,14 +1052,14 @@ func (client *Client) GetTurbotWorkspaceVersion() (*semver.Version, error) {
 	}
 	// execute api call
 	if err := client.doRequest(query, variables, responseData); err != nil {
-		return nil, fmt.Errorf("error reading policy value: %s", err.Error())
+		return nil, fmt.Errorf("error reading policy value: %w", err)
 	}
 	// convert interface {} to string
 	versionValue := fmt.Sprintf("%v", responseData.PolicyValue.Value)
 	// convert version value to semver value
 	version, err := semver.New(versionValue)
 	if err != nil {
-		return nil, fmt.Errorf("error reading guardrails workspace version value: %s", err.Error())
+		return nil, fmt.Errorf("error reading guardrails workspace version value: %w", err)
 	}
 	return version, nil
 }
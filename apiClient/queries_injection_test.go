@@ -1,6 +1,7 @@
 package apiClient
 
 import (
+	"fmt"
 	"os"
 	"regexp"
 	"strings"
@@ -41,6 +42,9 @@ func allIdentifierBuilders() map[string]string {
 		"readGoogleDirectoryQuery":  readGoogleDirectoryQuery(),
 		"readGrantQuery":            readGrantQuery(),
 		"readActiveGrantQuery":      readActiveGrantQuery(),
+		"readPolicyTypeQuery":       readPolicyTypeQuery(),
+		"readResourceTypeQuery":     readResourceTypeQuery(),
+		"readControlByIdQuery":      readControlByIdQuery(),
 	}
 }
 
@@ -127,3 +131,23 @@ func TestResourcePropertyInterpolationDoesNotProduceIdLiteral(t *testing.T) {
 	assert.Contains(t, query, "$id: ID!")
 	_ = strings.TrimSpace
 }
+
+// The batched read aliases many reads into one document; each identifier must still be its own
+// declared $idN variable, and the document must not depend on the identifiers at all.
+func TestBatchReadQueryUsesIdVariables(t *testing.T) {
+	props := []interface{}{map[string]string{"title": "title", "akas": "turbot.akas"}}
+	for name, query := range map[string]string{
+		"resource":      batchReadQuery("resource", readResourceSelection(props), 3),
+		"grant":         batchReadQuery("grant", readGrantSelection(), 3),
+		"policySetting": batchReadQuery("policySetting", readPolicySettingSelection(), 3),
+	} {
+		t.Run(name, func(t *testing.T) {
+			for i := 0; i < 3; i++ {
+				assert.Contains(t, query, fmt.Sprintf("$id%d: ID!", i), "%s must declare $id%d", name, i)
+				assert.Contains(t, query, fmt.Sprintf("r%d: %s(id: $id%d)", i, name, i), "%s must bind r%d to $id%d", name, i, i)
+			}
+			assert.False(t, idArgLiteral.MatchString(query),
+				"%s binds an id to a quoted literal — injection vector:\n%s", name, query)
+		})
+	}
+}
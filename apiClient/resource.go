@@ -1,9 +1,9 @@
 package apiClient
 
 import (
+	"errors"
 	"fmt"
 	"github.com/mitchellh/mapstructure"
-	"github.com/turbot/terraform-provider-turbot/errors"
 	"github.com/turbot/terraform-provider-turbot/helpers"
 	"log"
 )
@@ -27,8 +27,8 @@ func (client *Client) ReadResource(resourceAka string, properties map[string]str
 	query := readResourceQuery(propertiesArray)
 	var responseData = &ReadResourceResponse{}
 
-	// execute api call
-	if err := client.doRequest(query, map[string]interface{}{"id": resourceAka}, responseData); err != nil {
+	// execute api call - coalesced with concurrent reads of the same properties, see Batcher
+	if err := client.readById(query, "resource", readResourceSelection(propertiesArray), resourceAka, &responseData.Resource); err != nil {
 		return nil, client.handleReadError(err, resourceAka, "resource")
 	}
 
@@ -111,12 +111,50 @@ func (client *Client) ReadResourceList(filter string, properties map[string]stri
 
 	// execute api call
 	if err := client.doRequest(query, variables, responseData); err != nil {
-		return nil, fmt.Errorf("error fetching resource list: %s", err.Error())
+		return nil, fmt.Errorf("error fetching resource list: %w", err)
 	}
 
 	return responseData.ResourceList.Items, nil
 }
 
+// ReadAllResources returns every resource matching filter, with its data, reading all the pages of
+// the result
+func (client *Client) ReadAllResources(filter string) ([]Resource, error) {
+	var resources []Resource
+	variables := map[string]interface{}{"filter": []string{filter}}
+	newPage := func() pagedResponse { return &ReadResourcePageResponse{} }
+	collect := func(page pagedResponse) {
+		resources = append(resources, page.(*ReadResourcePageResponse).ResourceList.Items...)
+	}
+
+	// execute api calls
+	if err := client.readAllPages(readResourcePageQuery(), variables, newPage, collect); err != nil {
+		return nil, fmt.Errorf("error fetching resource list: %w", err)
+	}
+	for _, resource := range resources {
+		// the turbot properties are read separately
+		delete(resource.Data, "turbot")
+	}
+	return resources, nil
+}
+
+// ReadAllResourceMetadata returns every resource matching filter, with its type and turbot metadata but
+// not its data, reading all the pages of the result
+func (client *Client) ReadAllResourceMetadata(filter string) ([]Resource, error) {
+	var resources []Resource
+	variables := map[string]interface{}{"filter": []string{filter}}
+	newPage := func() pagedResponse { return &ReadResourcePageResponse{} }
+	collect := func(page pagedResponse) {
+		resources = append(resources, page.(*ReadResourcePageResponse).ResourceList.Items...)
+	}
+
+	// execute api calls
+	if err := client.readAllPages(readResourceMetadataPageQuery(), variables, newPage, collect); err != nil {
+		return nil, fmt.Errorf("error fetching resource list: %w", err)
+	}
+	return resources, nil
+}
+
 func (client *Client) UpdateResource(input map[string]interface{}) (*TurbotResourceMetadata, error) {
 	query := updateResourceMutation(nil)
 	responseData := &UpdateResourceResponse{}
@@ -156,7 +194,7 @@ func (client *Client) DeleteResource(aka string) error {
 
 	// execute api call
 	if err := client.doRequest(query, variables, &responseData); err != nil {
-		return fmt.Errorf("error deleting resource: %s", err.Error())
+		return fmt.Errorf("error deleting resource: %w", err)
 	}
 	return nil
 }
@@ -165,7 +203,7 @@ func (client *Client) ResourceExists(id string) (bool, error) {
 	resource, err := client.ReadResource(id, nil)
 
 	if err != nil {
-		if errors.NotFoundError(err) {
+		if errors.Is(err, ErrNotFound) {
 			return false, nil
 		}
 		return false, err
@@ -174,7 +212,13 @@ func (client *Client) ResourceExists(id string) (bool, error) {
 	return exists, nil
 }
 
+// GetResourceAkas returns the akas of the given resource. Results are cached for the life of the
+// process - see resourceAkaCache.
 func (client *Client) GetResourceAkas(resourceAka string) ([]string, error) {
+	if akas, ok := lookupCachedAkas(resourceAka); ok {
+		return akas, nil
+	}
+	generation := currentAkaCacheGeneration()
 	resource, err := client.ReadResource(resourceAka, nil)
 	if err != nil {
 		log.Printf("[ERROR] Failed to load target resource; %s", err)
@@ -185,6 +229,7 @@ func (client *Client) GetResourceAkas(resourceAka string) ([]string, error) {
 	if resourceAkas == nil {
 		resourceAkas = []string{resourceAka}
 	}
+	storeCachedAkas(resourceAka, resource.Turbot.Id, resource.Turbot.Path, resourceAkas, generation)
 	return resourceAkas, nil
 }
 
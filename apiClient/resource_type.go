@@ -0,0 +1,78 @@
+package apiClient
+
+import (
+	"fmt"
+	"github.com/turbot/terraform-provider-turbot/helpers"
+	"sync"
+)
+
+const resourceTypeResourceType = "tmod:@turbot/turbot#/resource/types/resourceType"
+
+// resourceTypeCache caches resource type definitions for the life of the provider process.
+//
+// turbot_resource validates its data against the type's createSchema or updateSchema in every plan,
+// and a workspace's resources are mostly of a handful of types, so without the cache a plan of 500
+// accounts read the one account type 500 times. An entry is stored under every name the type answers
+// to - the identifier it was read by, its id and each of its akas - as for resourceAkaCache.
+//
+// Staleness: a definition changes only when its mod is installed or upgraded. A plan that upgrades a
+// mod validates against the installed definition, and the server still checks the data on apply.
+var resourceTypeCache sync.Map // identifier -> *ResourceTypeDefinition
+
+// ReadResourceType reads the definition of the resource type with the given uri or id
+func (client *Client) ReadResourceType(resourceTypeUri string) (*ResourceTypeDefinition, error) {
+	responseData := &ReadResourceTypeResponse{}
+
+	// execute api call
+	if err := client.doRequest(readResourceTypeQuery(), map[string]interface{}{"id": resourceTypeUri}, responseData); err != nil {
+		return nil, client.handleReadError(err, resourceTypeUri, "resource type")
+	}
+	if responseData.ResourceType.Type.Uri != resourceTypeResourceType {
+		return nil, fmt.Errorf("error reading resource type: %s is a %s, not a resource type", resourceTypeUri, responseData.ResourceType.Type.Uri)
+	}
+	return &responseData.ResourceType, nil
+}
+
+// ReadCachedResourceType is ReadResourceType, answered from resourceTypeCache after the first read of
+// a type. The definition returned is shared, so must not be modified.
+func (client *Client) ReadCachedResourceType(resourceTypeUri string) (*ResourceTypeDefinition, error) {
+	if cached, ok := resourceTypeCache.Load(resourceTypeUri); ok {
+		return cached.(*ResourceTypeDefinition), nil
+	}
+	resourceType, err := client.ReadResourceType(resourceTypeUri)
+	if err != nil {
+		// a failed read is not cached - the type may be installed later in the run
+		return nil, err
+	}
+	resourceTypeCache.Store(resourceTypeUri, resourceType)
+	if resourceType.Turbot.Id != "" {
+		resourceTypeCache.Store(resourceType.Turbot.Id, resourceType)
+	}
+	for _, aka := range resourceType.Turbot.Akas {
+		resourceTypeCache.Store(aka, resourceType)
+	}
+	return resourceType, nil
+}
+
+// UpdateSchemaExcludedProperties returns the data properties an update schema forbids - those it
+// types as null - which an update must not send
+func UpdateSchemaExcludedProperties(updateSchema interface{}) []interface{} {
+	m, ok := updateSchema.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	var excluded []interface{}
+	if value, ok := m["allOf"]; ok {
+		for _, schema := range value.([]interface{}) {
+			if res, ok := schema.(map[string]interface{}); ok {
+				if res["type"] == "object" {
+					// loop to flatten interface, so we will not get this structure - [[id1,id2],[id3,id4]]
+					for _, element := range helpers.GetNullProperties(res) {
+						excluded = append(excluded, element)
+					}
+				}
+			}
+		}
+	}
+	return excluded
+}
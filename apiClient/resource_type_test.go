@@ -0,0 +1,59 @@
+package apiClient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/machinebox/graphql"
+	"github.com/stretchr/testify/assert"
+)
+
+func resetResourceTypeCache() {
+	resourceTypeCache.Range(func(key, _ interface{}) bool {
+		resourceTypeCache.Delete(key)
+		return true
+	})
+}
+
+// A plan of many resources of one type must read the type's definition once, whichever name each
+// resource gives the type by
+func TestReadCachedResourceType(t *testing.T) {
+	resetResourceTypeCache()
+	defer resetResourceTypeCache()
+	var reads int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&reads, 1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":{"resourceType":{"type":{"uri":"tmod:@turbot/turbot#/resource/types/resourceType"},"createSchema":{"type":"object"},"turbot":{"id":"222","akas":["tmod:@turbot/aws#/resource/types/account"]}}}}`))
+	}))
+	defer server.Close()
+	client := &Client{Graphql: graphql.NewClient(server.URL + "/graphql")}
+
+	for _, name := range []string{"tmod:@turbot/aws#/resource/types/account", "222", "tmod:@turbot/aws#/resource/types/account"} {
+		resourceType, err := client.ReadCachedResourceType(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, map[string]interface{}{"type": "object"}, resourceType.CreateSchema)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&reads), "every name for one type must share one read")
+}
+
+func TestUpdateSchemaExcludedProperties(t *testing.T) {
+	updateSchema := map[string]interface{}{
+		"allOf": []interface{}{
+			map[string]interface{}{"$ref": "#/definitions/create"},
+			map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"Name":  map[string]interface{}{"type": "null"},
+					"Title": map[string]interface{}{"type": "string"},
+				},
+			},
+		},
+	}
+	assert.Equal(t, []interface{}{"Name"}, UpdateSchemaExcludedProperties(updateSchema))
+	assert.Nil(t, UpdateSchemaExcludedProperties(nil))
+}
@@ -0,0 +1,277 @@
+package apiClient
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/machinebox/graphql"
+	errorsHandler "github.com/turbot/terraform-provider-turbot/errors"
+)
+
+// Retry defaults installed by CreateClient. Four retries at a 500ms base gives waits of roughly
+// 0.5s, 1s, 2s and 4s - about 7.5s in total - which rides out a gateway restart or a short burst of
+// throttling without turning a genuinely-down workspace into a minutes-long hang.
+const (
+	DefaultMaxRetries      = 4
+	DefaultRetryBaseDelay  = 500 * time.Millisecond
+	DefaultRetryMaxBackoff = 30 * time.Second
+)
+
+// RetryPolicy controls how doRequest retries a transient failure. The zero value disables retries,
+// so a hand-built Client (e.g. in tests) keeps the single-shot behaviour unless it opts in;
+// CreateClient installs DefaultRetryPolicy.
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the first attempt. Zero means no retries.
+	MaxRetries int
+	// BaseDelay is the wait before the first retry; each further retry doubles it.
+	BaseDelay time.Duration
+	// MaxBackoff caps any single wait, including one requested by a Retry-After header.
+	MaxBackoff time.Duration
+	// Jitter randomises each wait between half and all of its computed value, so a parallel apply
+	// that hit the same blip does not retry in lockstep and trip it again.
+	Jitter bool
+}
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries: DefaultMaxRetries,
+		BaseDelay:  DefaultRetryBaseDelay,
+		MaxBackoff: DefaultRetryMaxBackoff,
+		Jitter:     true,
+	}
+}
+
+// backoff returns the wait before retry number attempt (1-based), ignoring any Retry-After.
+// Attempts below 1 return zero - see verifyBackoff for why that is made structurally impossible
+// rather than left to the call site.
+func (policy RetryPolicy) backoff(attempt int) time.Duration {
+	if attempt < 1 || policy.BaseDelay <= 0 {
+		return 0
+	}
+	delay := policy.BaseDelay
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		// stop doubling once past the cap, so a large attempt count cannot overflow into a negative
+		if policy.MaxBackoff > 0 && delay >= policy.MaxBackoff {
+			break
+		}
+	}
+	if policy.MaxBackoff > 0 && delay > policy.MaxBackoff {
+		delay = policy.MaxBackoff
+	}
+	if policy.Jitter {
+		half := delay / 2
+		delay = half + time.Duration(rand.Int63n(int64(half)+1))
+	}
+	return delay
+}
+
+// retryDelay returns the wait before retry number attempt. A server-supplied Retry-After wins over
+// the computed backoff - the server knows when it will have capacity - but is still capped at
+// MaxBackoff, so a misbehaving proxy answering "Retry-After: 3600" cannot stall an apply for an hour.
+func (policy RetryPolicy) retryDelay(attempt int, retryAfter string) time.Duration {
+	if wait, ok := parseRetryAfter(retryAfter, time.Now()); ok {
+		if policy.MaxBackoff > 0 && wait > policy.MaxBackoff {
+			return policy.MaxBackoff
+		}
+		return wait
+	}
+	return policy.backoff(attempt)
+}
+
+// parseRetryAfter decodes a Retry-After header, which RFC 7231 allows as either delay-seconds or an
+// HTTP-date. An empty or unparseable header reports false so the caller falls back to backoff.
+func parseRetryAfter(header string, now time.Time) (time.Duration, bool) {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(header); err == nil {
+		wait := date.Sub(now)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
+
+// retryableStatusCodes are the HTTP statuses that signal a transient condition: throttling, or a
+// gateway that could not reach (or hear back from) a healthy backend.
+var retryableStatusCodes = map[int]bool{
+	http.StatusTooManyRequests:    true,
+	http.StatusBadGateway:         true,
+	http.StatusServiceUnavailable: true,
+	http.StatusGatewayTimeout:     true,
+}
+
+// shouldRetry decides whether a failed attempt may be retried.
+//
+// Queries are side-effect free, so any transient failure is retried. Mutations are only retried when
+// the trace proves the request never reached the server - a refused connection, a failed dial, a
+// connection dropped before the body was written. Once the request has been written the server may
+// have applied it even though we saw a 502/504 or a reset, and replaying a createPolicySetting or
+// createGrant would then fail with "already exists" or, worse, duplicate the write.
+func shouldRetry(err error, trace *requestTrace, mutation bool) bool {
+	if err == nil {
+		return false
+	}
+	// Our own deadline or cancellation is final: RequestTimeout is the operator's bound on a single
+	// request, and retrying past it would quietly multiply that bound.
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return false
+	}
+	if mutation {
+		return !trace.wroteRequest() && isConnectionError(err)
+	}
+	if isConnectionError(err) {
+		return true
+	}
+	code, _ := errorsHandler.ExtractErrorCode(err)
+	if code == 0 {
+		// machinebox/graphql only reports the status when the body is not JSON. A gateway that
+		// answers 503 with a JSON error body surfaces as a plain graphql error, so fall back to the
+		// status the transport recorded.
+		code = trace.status()
+	}
+	return retryableStatusCodes[code]
+}
+
+// isConnectionError reports a connection-level failure - a failed dial, a reset, a connection closed
+// mid-response - as opposed to a response the server chose to send.
+func isConnectionError(err error) bool {
+	var opError *net.OpError
+	return errors.As(err, &opError) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// isMutation reports whether a GraphQL document is a mutation. Every mutation in queries.go opens
+// with the `mutation` keyword, so the first token is sufficient.
+func isMutation(query string) bool {
+	return strings.HasPrefix(strings.TrimSpace(query), "mutation")
+}
+
+// requestTrace records what happened to one attempt on the wire. doRequest installs it in the
+// request context; httptrace reports whether the request was written, and tracingTransport records
+// the response status, Retry-After and request id headers and the raw body, none of which
+// machinebox/graphql exposes.
+type requestTrace struct {
+	mutex      sync.Mutex
+	wrote      bool
+	statusCode int
+	retryAfter string
+	requestId  string
+	body       []byte
+}
+
+type requestTraceKey struct{}
+
+// withRequestTrace returns a context carrying trace, wired to httptrace so the WroteRequest
+// callback marks the request as sent.
+func withRequestTrace(ctx context.Context, trace *requestTrace) context.Context {
+	ctx = context.WithValue(ctx, requestTraceKey{}, trace)
+	return httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		WroteRequest: func(info httptrace.WroteRequestInfo) {
+			// a failed write may still have put part of the request on the wire, so any write
+			// attempt counts as sent
+			trace.mutex.Lock()
+			trace.wrote = true
+			trace.mutex.Unlock()
+		},
+	})
+}
+
+func (trace *requestTrace) wroteRequest() bool {
+	trace.mutex.Lock()
+	defer trace.mutex.Unlock()
+	return trace.wrote
+}
+
+func (trace *requestTrace) status() int {
+	trace.mutex.Lock()
+	defer trace.mutex.Unlock()
+	return trace.statusCode
+}
+
+func (trace *requestTrace) responseBody() []byte {
+	trace.mutex.Lock()
+	defer trace.mutex.Unlock()
+	return trace.body
+}
+
+func (trace *requestTrace) retryAfterHeader() string {
+	trace.mutex.Lock()
+	defer trace.mutex.Unlock()
+	return trace.retryAfter
+}
+
+func (trace *requestTrace) requestIdHeader() string {
+	trace.mutex.Lock()
+	defer trace.mutex.Unlock()
+	return trace.requestId
+}
+
+// tracingTransport records the response status, headers and body into the requestTrace carried by
+// the request context. Requests without a trace pass straight through.
+type tracingTransport struct {
+	base http.RoundTripper
+}
+
+func (transport *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, err := transport.base.RoundTrip(req)
+	if trace, ok := req.Context().Value(requestTraceKey{}).(*requestTrace); ok && res != nil {
+		trace.mutex.Lock()
+		defer trace.mutex.Unlock()
+		trace.statusCode = res.StatusCode
+		trace.retryAfter = res.Header.Get("Retry-After")
+		trace.requestId = res.Header.Get("X-Request-Id")
+		// Read the body here and hand machinebox/graphql a copy, so both see the same bytes. It is
+		// kept for the errors machinebox/graphql reduces to a message: the batched read (batch.go)
+		// needs their paths, and newAPIError (api_error.go) their extensions.
+		body, readErr := io.ReadAll(res.Body)
+		res.Body.Close()
+		if readErr != nil {
+			return nil, readErr
+		}
+		trace.body = body
+		res.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	return res, err
+}
+
+// Transport, when set, replaces the HTTP transport of every client CreateClient builds. Tests set it
+// to record or replay API traffic - see testing/cassette. CreateClient also turns read batching off
+// for such a client: which reads share a batched document depends on timing, and a replayed request
+// must be exactly the request that was recorded.
+var Transport http.RoundTripper
+
+// newGraphqlClient builds the GraphQL client used by CreateClient, routed through tracingTransport
+// so retries can see the response status and honour Retry-After.
+func newGraphqlClient(endpoint string) *graphql.Client {
+	base := Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	httpClient := &http.Client{Transport: &tracingTransport{base: base}}
+	return graphql.NewClient(endpoint, graphql.WithHTTPClient(httpClient))
+}
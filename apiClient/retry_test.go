@@ -0,0 +1,250 @@
+package apiClient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/machinebox/graphql"
+	"github.com/stretchr/testify/assert"
+)
+
+// flakyServer stands in for a workspace that fails the first `failures` requests with `status`, then
+// answers normally. It counts every request it sees, so tests can assert exactly how many attempts
+// doRequest made.
+func flakyServer(failures int32, status int, body string) (*httptest.Server, *int32) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&hits, 1) <= failures {
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(body))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":{"__typename":"Query"}}`))
+	}))
+	return server, &hits
+}
+
+// testRetryPolicy keeps the backoff tiny so the suite does not sleep through real delays.
+func testRetryPolicy(maxRetries int) RetryPolicy {
+	return RetryPolicy{MaxRetries: maxRetries, BaseDelay: time.Millisecond, MaxBackoff: 5 * time.Millisecond}
+}
+
+// The headline behaviour: a query that hits a transient failure a few times and then succeeds must
+// succeed, rather than failing the whole apply on a blip.
+func TestDoRequestRetriesTransientFailureThenSucceeds(t *testing.T) {
+	for _, status := range []int{429, 502, 503, 504} {
+		server, hits := flakyServer(2, status, "upstream unavailable")
+		client := &Client{Graphql: newGraphqlClient(server.URL + "/graphql"), Retry: testRetryPolicy(3)}
+
+		var resp map[string]interface{}
+		assert.NoError(t, client.doRequest(`{ __typename }`, nil, &resp), "status %d", status)
+		assert.Equal(t, int32(3), atomic.LoadInt32(hits), "status %d: two failures then one success", status)
+		server.Close()
+	}
+}
+
+// A failure that outlasts the retry budget is surfaced, after exactly MaxRetries+1 attempts.
+func TestDoRequestGivesUpAfterMaxRetries(t *testing.T) {
+	server, hits := flakyServer(100, http.StatusBadGateway, "bad gateway")
+	defer server.Close()
+	client := &Client{Graphql: newGraphqlClient(server.URL + "/graphql"), Retry: testRetryPolicy(2)}
+
+	var resp map[string]interface{}
+	err := client.doRequest(`{ __typename }`, nil, &resp)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Bad Gateway", "the final error still goes through BuildErrorMessage")
+	assert.Equal(t, int32(3), atomic.LoadInt32(hits))
+}
+
+// A hand-built Client has a zero RetryPolicy and must keep the old single-shot behaviour.
+func TestDoRequestZeroRetryPolicyDoesNotRetry(t *testing.T) {
+	server, hits := flakyServer(1, http.StatusServiceUnavailable, "unavailable")
+	defer server.Close()
+	client := &Client{Graphql: newGraphqlClient(server.URL + "/graphql")}
+
+	var resp map[string]interface{}
+	assert.Error(t, client.doRequest(`{ __typename }`, nil, &resp))
+	assert.Equal(t, int32(1), atomic.LoadInt32(hits))
+}
+
+// A gateway answering 503 with a JSON error body surfaces from machinebox/graphql as a plain graphql
+// error with no status code in it. The status recorded by the transport must still drive the retry.
+func TestDoRequestRetriesJsonBodied503(t *testing.T) {
+	server, hits := flakyServer(1, http.StatusServiceUnavailable, `{"errors":[{"message":"Service Unavailable"}]}`)
+	defer server.Close()
+	client := &Client{Graphql: newGraphqlClient(server.URL + "/graphql"), Retry: testRetryPolicy(3)}
+
+	var resp map[string]interface{}
+	assert.NoError(t, client.doRequest(`{ __typename }`, nil, &resp))
+	assert.Equal(t, int32(2), atomic.LoadInt32(hits))
+}
+
+// Errors the server chose to return - not found, validation, permissions - are not transient and
+// must not be retried.
+func TestDoRequestDoesNotRetryGraphqlErrors(t *testing.T) {
+	server, hits := flakyServer(100, http.StatusOK, `{"errors":[{"message":"Not Found: Resource not found or not accessible"}]}`)
+	defer server.Close()
+	client := &Client{Graphql: newGraphqlClient(server.URL + "/graphql"), Retry: testRetryPolicy(3)}
+
+	var resp map[string]interface{}
+	assert.Error(t, client.doRequest(`{ __typename }`, nil, &resp))
+	assert.Equal(t, int32(1), atomic.LoadInt32(hits))
+}
+
+// A mutation that reached the server may have been applied even though the gateway reported a 503,
+// so replaying it could duplicate the write. It must be attempted exactly once.
+func TestDoRequestDoesNotRetryMutationThatReachedServer(t *testing.T) {
+	server, hits := flakyServer(100, http.StatusServiceUnavailable, "unavailable")
+	defer server.Close()
+	client := &Client{Graphql: newGraphqlClient(server.URL + "/graphql"), Retry: testRetryPolicy(3)}
+
+	var resp map[string]interface{}
+	err := client.doRequest(`mutation CreateGrant($input: CreateGrantInput!) { createGrant(input: $input) { turbot { id } } }`, nil, &resp)
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(hits), "a mutation the server received must not be replayed")
+}
+
+// A mutation whose connection was refused never left the client, so it is safe to retry.
+func TestShouldRetryMutationThatNeverReachedServer(t *testing.T) {
+	// 127.0.0.1:1 refuses immediately, so the request is never written
+	client := &Client{Graphql: newGraphqlClient("http://127.0.0.1:1/graphql")}
+	trace := &requestTrace{}
+	var resp map[string]interface{}
+	err := client.runRequest(graphql.NewRequest(`mutation { x }`), trace, &resp)
+
+	assert.Error(t, err)
+	assert.False(t, trace.wroteRequest(), "a refused connection must not be recorded as written")
+	assert.True(t, shouldRetry(err, trace, true), "an unsent mutation is safe to retry")
+	assert.True(t, shouldRetry(err, trace, false), "a refused query is retryable")
+}
+
+// Once the trace records the request as written, a mutation is never retried, whatever the error.
+func TestShouldRetryMutationOnceWritten(t *testing.T) {
+	server, _ := flakyServer(100, http.StatusBadGateway, "bad gateway")
+	defer server.Close()
+	client := &Client{Graphql: newGraphqlClient(server.URL + "/graphql")}
+	trace := &requestTrace{}
+	var resp map[string]interface{}
+	err := client.runRequest(graphql.NewRequest(`mutation { x }`), trace, &resp)
+
+	assert.Error(t, err)
+	assert.True(t, trace.wroteRequest())
+	assert.Equal(t, http.StatusBadGateway, trace.status())
+	assert.False(t, shouldRetry(err, trace, true))
+	assert.True(t, shouldRetry(err, trace, false), "the same failure on a query is retryable")
+}
+
+func TestIsMutation(t *testing.T) {
+	assert.True(t, isMutation(createGrantMutation()))
+	assert.True(t, isMutation(deleteResourceMutation()))
+	assert.False(t, isMutation(readResourceQuery(nil)))
+	assert.False(t, isMutation(readGrantQuery()))
+}
+
+// Backoff doubles from the base and is capped at MaxBackoff; jitter keeps it within [half, full].
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxBackoff: time.Second}
+	var tests = []struct {
+		attempt  int
+		expected time.Duration
+	}{
+		{0, 0},
+		{1, 100 * time.Millisecond},
+		{2, 200 * time.Millisecond},
+		{3, 400 * time.Millisecond},
+		{4, 800 * time.Millisecond},
+		{5, time.Second},
+		{100, time.Second},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, policy.backoff(test.attempt), "attempt %d", test.attempt)
+	}
+
+	policy.Jitter = true
+	for i := 0; i < 100; i++ {
+		delay := policy.backoff(3)
+		assert.True(t, delay >= 200*time.Millisecond && delay <= 400*time.Millisecond, "jittered delay %s out of range", delay)
+	}
+}
+
+// Retry-After wins over the computed backoff but never exceeds MaxBackoff.
+func TestRetryPolicyHonoursRetryAfter(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxBackoff: 10 * time.Second}
+	assert.Equal(t, 3*time.Second, policy.retryDelay(1, "3"))
+	assert.Equal(t, 10*time.Second, policy.retryDelay(1, "3600"), "Retry-After is capped at MaxBackoff")
+	assert.Equal(t, 100*time.Millisecond, policy.retryDelay(1, ""), "no header falls back to backoff")
+	assert.Equal(t, 100*time.Millisecond, policy.retryDelay(1, "soon"), "an unparseable header falls back to backoff")
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	var tests = []struct {
+		name     string
+		header   string
+		expected time.Duration
+		ok       bool
+	}{
+		{"seconds", "5", 5 * time.Second, true},
+		{"zero", "0", 0, true},
+		{"padded", " 2 ", 2 * time.Second, true},
+		{"http date", "Thu, 01 Oct 2026 12:00:30 GMT", 30 * time.Second, true},
+		{"date in the past", "Thu, 01 Oct 2026 11:00:00 GMT", 0, true},
+		{"negative", "-1", 0, false},
+		{"empty", "", 0, false},
+		{"garbage", "later", 0, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			wait, ok := parseRetryAfter(test.header, now)
+			assert.Equal(t, test.ok, ok)
+			assert.Equal(t, test.expected, wait)
+		})
+	}
+}
+
+// A 429 carrying Retry-After must be waited out - bounded by MaxBackoff - and then retried.
+func TestDoRequestHonoursRetryAfterHeader(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&hits, 1) == 1 {
+			w.Header().Set("Retry-After", "30")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":{"__typename":"Query"}}`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		Graphql: newGraphqlClient(server.URL + "/graphql"),
+		Retry:   RetryPolicy{MaxRetries: 1, BaseDelay: time.Millisecond, MaxBackoff: 50 * time.Millisecond},
+	}
+	start := time.Now()
+	var resp map[string]interface{}
+	assert.NoError(t, client.doRequest(`{ __typename }`, nil, &resp))
+	elapsed := time.Since(start)
+	assert.True(t, elapsed >= 50*time.Millisecond, "Retry-After must be honoured up to MaxBackoff, waited %s", elapsed)
+	assert.True(t, elapsed < 5*time.Second, "Retry-After must be capped at MaxBackoff, waited %s", elapsed)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&hits))
+}
+
+// CreateClient installs the default policy and applies the config overrides, including an explicit
+// zero, which disables retries.
+func TestCreateClientInstallsRetryPolicy(t *testing.T) {
+	creds := ClientCredentials{AccessKey: "AK", SecretKey: "SK", Workspace: "https://x.example.com"}
+
+	defaulted, err := CreateClient(ClientConfig{Credentials: creds})
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultRetryPolicy(), defaulted.Retry)
+
+	zero := 0
+	disabled, err := CreateClient(ClientConfig{Credentials: creds, MaxRetries: &zero, RetryMaxBackoff: time.Minute})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, disabled.Retry.MaxRetries, "an explicit zero must disable retries")
+	assert.Equal(t, time.Minute, disabled.Retry.MaxBackoff)
+}
@@ -46,7 +46,7 @@ func (client *Client) DeleteSmartFolder(id string) error {
 
 	// execute api call
 	if err := client.doRequest(query, map[string]interface{}{"id": id}, &responseData); err != nil {
-		return fmt.Errorf("error deleting resource: %s", err.Error())
+		return fmt.Errorf("error deleting resource: %w", err)
 	}
 	return nil
 }
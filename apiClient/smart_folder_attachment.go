@@ -1,9 +1,8 @@
 package apiClient
 
 import (
+	"errors"
 	"fmt"
-
-	errorsHandler "github.com/turbot/terraform-provider-turbot/errors"
 	"sync"
 	"time"
 )
@@ -102,6 +101,14 @@ func verifyBackoff(attempt int) time.Duration {
 }
 
 func (client *Client) CreateSmartFolderAttachment(input map[string]interface{}) (*TurbotResourceMetadata, error) {
+	if target := attachmentTarget(input); target != "" {
+		defer lockAttachmentTarget(client.attachmentLockKey(target))()
+	}
+	return client.attachSmartFolders(input)
+}
+
+// attachSmartFolders attaches and verifies the packs in input. The caller holds the target's lock.
+func (client *Client) attachSmartFolders(input map[string]interface{}) (*TurbotResourceMetadata, error) {
 	query := createSmartFolderAttachmentMutation()
 	responseData := &CreateSmartFolderAttachResponse{}
 
@@ -109,16 +116,12 @@ func (client *Client) CreateSmartFolderAttachment(input map[string]interface{})
 		"input": input,
 	}
 
-	if target := attachmentTarget(input); target != "" {
-		defer lockAttachmentTarget(client.attachmentLockKey(target))()
-	}
-
 	// execute api call
 	if err := client.doRequest(query, variables, responseData); err != nil {
 		// handleCreateError's not-found branch reports input["parent"], which an attachment input
 		// does not carry - it holds only `resource` and `smartFolders` - so it renders
 		// "parent resource not found: %!s(<nil>)". Name the things that can actually be missing.
-		if errorsHandler.NotFoundError(err) {
+		if errors.Is(err, ErrNotFound) {
 			return nil, fmt.Errorf("error creating smart folder attachment: resource or policy pack not found (resource: %v, policy packs: %v)", input["resource"], input["smartFolders"])
 		}
 		return nil, client.handleCreateError(err, input, "smart folder attachment")
@@ -135,6 +138,15 @@ func (client *Client) CreateSmartFolderAttachment(input map[string]interface{})
 }
 
 func (client *Client) DeleteSmartFolderAttachment(input map[string]interface{}) error {
+	// Detach is the same read-modify-write on the target's list, so it takes the same lock.
+	if target := attachmentTarget(input); target != "" {
+		defer lockAttachmentTarget(client.attachmentLockKey(target))()
+	}
+	return client.detachSmartFolders(input)
+}
+
+// detachSmartFolders detaches and verifies the packs in input. The caller holds the target's lock.
+func (client *Client) detachSmartFolders(input map[string]interface{}) error {
 	query := detachSmartFolderAttachment()
 	var responseData interface{}
 
@@ -142,14 +154,9 @@ func (client *Client) DeleteSmartFolderAttachment(input map[string]interface{})
 		"input": input,
 	}
 
-	// Detach is the same read-modify-write on the target's list, so it takes the same lock.
-	if target := attachmentTarget(input); target != "" {
-		defer lockAttachmentTarget(client.attachmentLockKey(target))()
-	}
-
 	// execute api call
 	if err := client.doRequest(query, variables, responseData); err != nil {
-		return fmt.Errorf("error deleting smart folder attachment: %s", err.Error())
+		return fmt.Errorf("error deleting smart folder attachment: %w", err)
 	}
 
 	// A lost detach is the more dangerous direction, and the only one with no backstop. A lost
@@ -160,6 +167,110 @@ func (client *Client) DeleteSmartFolderAttachment(input map[string]interface{})
 	return client.verifyDetachment(input)
 }
 
+// SetAttachedPolicyPacks makes the policy packs attached to target exactly packs, in that order.
+// packs are numeric ids, as the attach mutation requires. Attaching appends to the target's list and
+// there is no mutation to reorder it, so a pack out of place is detached, together with every pack
+// after it, and they are attached again in order. The whole read-modify-write holds the target's
+// lock, so it cannot interleave with a single attachment resource writing to the same target.
+func (client *Client) SetAttachedPolicyPacks(target string, packs []string) error {
+	defer lockAttachmentTarget(client.attachmentLockKey(target))()
+
+	attached, truncated, err := client.ReadAttachedPolicyPacks(target)
+	if err != nil {
+		return err
+	}
+	if truncated {
+		// a pack on a page that was not returned could be neither detached nor ordered
+		return fmt.Errorf("error setting attached policy packs: resource %s reports more attachments than a single page returns, so its attachments cannot be managed reliably", target)
+	}
+	var attachedIds []string
+	for _, pack := range attached {
+		attachedIds = append(attachedIds, pack.Id)
+	}
+
+	detach, attach := attachmentChanges(attachedIds, packs)
+	if len(detach) > 0 {
+		if err := client.detachSmartFolders(map[string]interface{}{"resource": target, "smartFolders": detach}); err != nil {
+			return err
+		}
+	}
+	if len(attach) > 0 {
+		if _, err := client.attachSmartFolders(map[string]interface{}{"resource": target, "smartFolders": attach}); err != nil {
+			return err
+		}
+	}
+	return client.verifyAttachmentOrder(target, packs)
+}
+
+// attachmentChanges returns the packs to detach from, then attach to, a target with the attached
+// packs so that it has exactly packs, in order. The packs that are already in order - the longest
+// prefix of packs that the attached list keeps in the same order - are left alone.
+func attachmentChanges(attached, packs []string) (detach, attach []string) {
+	wanted := map[string]bool{}
+	for _, pack := range packs {
+		wanted[pack] = true
+	}
+	var kept []string
+	for _, pack := range attached {
+		if wanted[pack] {
+			kept = append(kept, pack)
+		} else {
+			detach = append(detach, pack)
+		}
+	}
+	inOrder := 0
+	for inOrder < len(kept) && inOrder < len(packs) && kept[inOrder] == packs[inOrder] {
+		inOrder++
+	}
+	detach = append(detach, kept[inOrder:]...)
+	attach = append(attach, packs[inOrder:]...)
+	return detach, attach
+}
+
+// verifyAttachmentOrder polls the target's attachment list until it is exactly packs, in order, with
+// the same budget and the same rule as verifyAttachmentState: a check that could not run at all does
+// not fail the write.
+func (client *Client) verifyAttachmentOrder(target string, packs []string) error {
+	var attachedIds []string
+	compared := false
+	for attempt := 0; attempt < verifyAttachmentAttempts; attempt++ {
+		if attempt > 0 {
+			time.Sleep(verifyBackoff(attempt))
+		}
+		attached, truncated, err := client.ReadAttachedPolicyPacks(target)
+		if truncated || IsTargetNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			continue
+		}
+		compared = true
+		attachedIds = nil
+		for _, pack := range attached {
+			attachedIds = append(attachedIds, pack.Id)
+		}
+		if equalStrings(attachedIds, packs) {
+			return nil
+		}
+	}
+	if !compared {
+		return nil
+	}
+	return fmt.Errorf("error setting attached policy packs: the API reported success but %s has %v attached, not %v. This usually means a concurrent attachment write to the same resource changed the list; retrying the apply should converge", target, attachedIds, packs)
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
 // verifyAttachment confirms every pack named in input IS attached to the target.
 func (client *Client) verifyAttachment(input map[string]interface{}) error {
 	return client.verifyAttachmentState(input, true)
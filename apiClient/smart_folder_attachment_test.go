@@ -54,6 +54,34 @@ func TestAttachmentPacks(t *testing.T) {
 	}
 }
 
+// Attaching only appends, so ordering is done by detaching from the first pack out of place and
+// attaching the rest again - the packs already in order must not be touched.
+func TestAttachmentChanges(t *testing.T) {
+	var tests = []struct {
+		name     string
+		attached []string
+		packs    []string
+		detach   []string
+		attach   []string
+	}{
+		{"nothing attached", nil, []string{"a", "b"}, nil, []string{"a", "b"}},
+		{"already in order", []string{"a", "b"}, []string{"a", "b"}, nil, nil},
+		{"append", []string{"a"}, []string{"a", "b"}, nil, []string{"b"}},
+		{"detach unlisted", []string{"a", "x", "b"}, []string{"a", "b"}, []string{"x"}, nil},
+		{"reorder", []string{"a", "b", "c"}, []string{"a", "c", "b"}, []string{"b", "c"}, []string{"c", "b"}},
+		{"insert before attached", []string{"a", "b"}, []string{"c", "a", "b"}, []string{"a", "b"}, []string{"c", "a", "b"}},
+		{"detach all", []string{"a", "b"}, nil, []string{"a", "b"}, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			detach, attach := attachmentChanges(test.attached, test.packs)
+			assert.Equal(t, test.detach, detach)
+			assert.Equal(t, test.attach, attach)
+		})
+	}
+}
+
 // Two writers for the SAME target must not overlap - that overlap is what loses attachments
 // server-side. Asserted by counting concurrent holders rather than by timing.
 func TestLockAttachmentTargetSerialisesSameTarget(t *testing.T) {
@@ -0,0 +1,84 @@
+package apiClient
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Throttle limits how hard the provider drives a workspace. Terraform's parallelism applies per
+// resource, not per request - every create also fans out reads through storeAkas - so an apply of a
+// few hundred policy settings or grants can issue thousands of requests in a burst, and a shared
+// workspace throttles every other tenant while it does. Throttle combines:
+//
+//   - a token bucket, bounding the sustained request rate while allowing a short burst, and
+//   - a semaphore, bounding how many requests are in flight at once, which is what actually loads
+//     the workspace when individual requests are slow.
+//
+// Either limit may be disabled by leaving it zero. A nil *Throttle imposes no limits at all, which is
+// what a hand-built Client gets; CreateClient only installs one when the config asks for it.
+type Throttle struct {
+	// token bucket; rate is in tokens per second, and zero disables it
+	mutex  sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+
+	// semaphore; nil disables it
+	inFlight chan struct{}
+}
+
+// NewThrottle returns a Throttle allowing requestsPerSecond sustained (bursting up to one second's
+// worth) and at most maxInFlight concurrent requests. A zero or negative value disables that limit.
+func NewThrottle(requestsPerSecond float64, maxInFlight int) *Throttle {
+	throttle := &Throttle{}
+	if requestsPerSecond > 0 {
+		throttle.rate = requestsPerSecond
+		// a burst of at least one token, or a rate below 1/s could never admit a request
+		throttle.burst = math.Max(1, math.Ceil(requestsPerSecond))
+		throttle.tokens = throttle.burst
+		throttle.last = time.Now()
+	}
+	if maxInFlight > 0 {
+		throttle.inFlight = make(chan struct{}, maxInFlight)
+	}
+	return throttle
+}
+
+// acquire blocks until a request may be sent and returns the func that releases its in-flight slot.
+// The slot is taken BEFORE the token, so a request queued behind the semaphore does not sit on a
+// token it cannot use yet.
+func (throttle *Throttle) acquire() func() {
+	if throttle == nil {
+		return func() {}
+	}
+	release := func() {}
+	if throttle.inFlight != nil {
+		throttle.inFlight <- struct{}{}
+		release = func() { <-throttle.inFlight }
+	}
+	throttle.waitForToken()
+	return release
+}
+
+// waitForToken takes one token from the bucket, sleeping until one is available.
+func (throttle *Throttle) waitForToken() {
+	if throttle.rate <= 0 {
+		return
+	}
+	for {
+		throttle.mutex.Lock()
+		now := time.Now()
+		throttle.tokens = math.Min(throttle.burst, throttle.tokens+now.Sub(throttle.last).Seconds()*throttle.rate)
+		throttle.last = now
+		if throttle.tokens >= 1 {
+			throttle.tokens--
+			throttle.mutex.Unlock()
+			return
+		}
+		wait := time.Duration((1 - throttle.tokens) / throttle.rate * float64(time.Second))
+		throttle.mutex.Unlock()
+		time.Sleep(wait)
+	}
+}
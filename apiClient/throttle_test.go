@@ -0,0 +1,125 @@
+package apiClient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// The semaphore must bound how many requests reach the workspace at once, however many goroutines
+// Terraform drives at it. Asserted by counting concurrent requests server-side rather than by timing.
+func TestThrottleBoundsRequestsInFlight(t *testing.T) {
+	var (
+		mutex   sync.Mutex
+		inside  int
+		maxSeen int
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		inside++
+		if inside > maxSeen {
+			maxSeen = inside
+		}
+		mutex.Unlock()
+
+		time.Sleep(20 * time.Millisecond)
+
+		mutex.Lock()
+		inside--
+		mutex.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":{"__typename":"Query"}}`))
+	}))
+	defer server.Close()
+
+	client := &Client{Graphql: newGraphqlClient(server.URL + "/graphql"), Throttle: NewThrottle(0, 3)}
+
+	var wait sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			var resp map[string]interface{}
+			assert.NoError(t, client.doRequest(`{ __typename }`, nil, &resp))
+		}()
+	}
+	wait.Wait()
+
+	assert.True(t, maxSeen <= 3, "at most 3 requests may be in flight, saw %d", maxSeen)
+	assert.True(t, maxSeen > 0)
+}
+
+// The token bucket admits a burst of one second's worth, then paces the rest at the configured rate.
+func TestThrottleLimitsRate(t *testing.T) {
+	throttle := NewThrottle(20, 0)
+
+	start := time.Now()
+	for i := 0; i < 25; i++ {
+		throttle.acquire()()
+	}
+	elapsed := time.Since(start)
+
+	// 20 tokens are available immediately; the remaining 5 arrive at 20/s, so ~250ms
+	assert.True(t, elapsed >= 200*time.Millisecond, "25 requests at 20/s with a burst of 20 must take ~250ms, took %s", elapsed)
+	assert.True(t, elapsed < 2*time.Second, "throttle waited far longer than the rate requires: %s", elapsed)
+}
+
+// A rate below one request per second still admits requests - the burst never drops below a token.
+func TestThrottleFractionalRateAdmitsFirstRequest(t *testing.T) {
+	throttle := NewThrottle(0.5, 0)
+
+	done := make(chan struct{})
+	go func() {
+		throttle.acquire()()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("the first request under a fractional rate must not wait")
+	}
+}
+
+// A released slot must be reusable, or a long apply would eventually deadlock on its own requests.
+func TestThrottleReleasesSlots(t *testing.T) {
+	throttle := NewThrottle(0, 1)
+	for i := 0; i < 10; i++ {
+		done := make(chan struct{})
+		go func() {
+			throttle.acquire()()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatalf("slot was not released after request %d", i)
+		}
+	}
+}
+
+// A nil Throttle - what a hand-built Client has - imposes no limits.
+func TestNilThrottleIsUnlimited(t *testing.T) {
+	var throttle *Throttle
+	assert.NotPanics(t, func() { throttle.acquire()() })
+}
+
+// CreateClient only installs a Throttle when the config asks for a limit, so existing configs keep
+// Terraform's full parallelism.
+func TestCreateClientInstallsThrottle(t *testing.T) {
+	creds := ClientCredentials{AccessKey: "AK", SecretKey: "SK", Workspace: "https://x.example.com"}
+
+	unlimited, err := CreateClient(ClientConfig{Credentials: creds})
+	assert.NoError(t, err)
+	assert.Nil(t, unlimited.Throttle)
+
+	limited, err := CreateClient(ClientConfig{Credentials: creds, MaxRequestsPerSecond: 10, MaxConcurrentRequests: 4})
+	assert.NoError(t, err)
+	if assert.NotNil(t, limited.Throttle) {
+		assert.Equal(t, float64(10), limited.Throttle.rate)
+		assert.Equal(t, 4, cap(limited.Throttle.inFlight))
+	}
+}
@@ -31,6 +31,17 @@ type ReadResourceListResponse struct {
 	}
 }
 
+type ReadResourcePageResponse struct {
+	ResourceList struct {
+		Items  []Resource
+		Paging Paging
+	}
+}
+
+func (response *ReadResourcePageResponse) paging() Paging {
+	return response.ResourceList.Paging
+}
+
 type ResourceResponse struct {
 	Resource Resource
 }
@@ -99,6 +110,31 @@ type Control struct {
 	Mute   interface{}
 }
 
+type ReadControlPageResponse struct {
+	ControlList struct {
+		Items  []ControlListItem
+		Paging Paging
+	}
+}
+
+func (response *ReadControlPageResponse) paging() Paging {
+	return response.ControlList.Paging
+}
+
+// ControlListItem is a control as controlList returns it. Details is kept as the JSON the API returns.
+type ControlListItem struct {
+	State   string
+	Reason  string
+	Details interface{}
+	Type    struct {
+		Uri string
+	}
+	Turbot struct {
+		Id         string
+		ResourceId string
+	}
+}
+
 // Control mute
 
 type MuteControlResponse struct {
@@ -140,6 +176,17 @@ type FindPolicySettingResponse struct {
 	}
 }
 
+type ReadPolicySettingPageResponse struct {
+	PolicySettingList struct {
+		Items  []PolicySetting
+		Paging Paging
+	}
+}
+
+func (response *ReadPolicySettingPageResponse) paging() Paging {
+	return response.PolicySettingList.Paging
+}
+
 type PolicySetting struct {
 	Type struct {
 		Uri string
@@ -184,9 +231,51 @@ type FindPolicyTypeResponse struct {
 }
 type PolicyType struct {
 	ModUri string
+	// Schema is the JSON schema a setting's value must satisfy
+	Schema interface{}
 	Turbot TurbotPolicyMetadata
 }
 
+type ReadPolicyTypeResponse struct {
+	PolicyType PolicyTypeDefinition
+}
+
+// PolicyTypeDefinition is a policy type as its definition resource describes it
+type PolicyTypeDefinition struct {
+	Type struct {
+		Uri string
+	}
+	Title                string
+	Description          string
+	Schema               interface{}
+	Default              interface{}
+	DefaultTemplate      string
+	DefaultTemplateInput interface{}
+	// Targets is the uri of a resource type, or a list of them
+	Targets interface{}
+	Secret  bool
+	Turbot  TurbotResourceMetadata
+}
+
+type ReadResourceTypeResponse struct {
+	ResourceType ResourceTypeDefinition
+}
+
+// ResourceTypeDefinition is a resource type as its definition resource describes it
+type ResourceTypeDefinition struct {
+	Type struct {
+		Uri string
+	}
+	Title       string
+	Description string
+	Category    string
+	// Parent is the uri of the resource type a resource of this type is created in, or a list of them
+	Parent       interface{}
+	CreateSchema interface{}
+	UpdateSchema interface{}
+	Turbot       TurbotResourceMetadata
+}
+
 // Mod
 type InstallModResponse struct {
 	Mod InstallModData
@@ -243,6 +332,17 @@ type Grant struct {
 	PermissionLevelId string
 }
 
+type ReadGrantPageResponse struct {
+	Grants struct {
+		Items  []Grant
+		Paging Paging
+	}
+}
+
+func (response *ReadGrantPageResponse) paging() Paging {
+	return response.Grants.Paging
+}
+
 // Active Grant
 type ActivateGrantResponse struct {
 	GrantActivate struct {
@@ -258,6 +358,47 @@ type ActiveGrant struct {
 	Turbot TurbotActiveGrantMetadata
 }
 
+type ReadActiveGrantPageResponse struct {
+	ActiveGrants struct {
+		Items  []ActiveGrantListItem
+		Paging Paging
+	}
+}
+
+func (response *ReadActiveGrantPageResponse) paging() Paging {
+	return response.ActiveGrants.Paging
+}
+
+// ActiveGrantListItem is an active grant as activeGrants returns it, with the permission it grants
+type ActiveGrantListItem struct {
+	Grant struct {
+		Type struct {
+			Uri string
+		}
+		Level struct {
+			Uri string
+		}
+		// the identity the grant is made to
+		Turbot struct {
+			ProfileId string
+		}
+	}
+	Turbot TurbotActiveGrantMetadata
+}
+
+// Actor
+type ReadActorResponse struct {
+	Actor Actor
+}
+
+// Actor is the caller of the API. Identity is its profile, whose parent is the profile's directory.
+type Actor struct {
+	Identity struct {
+		Email  string
+		Turbot TurbotResourceMetadata
+	}
+}
+
 // Folder
 type FolderResponse struct {
 	Resource Folder
@@ -472,7 +613,7 @@ type LdapDirectoryResponse struct {
 // Group profile
 type GroupProfile struct {
 	Turbot         TurbotResourceMetadata
-	Directory      string
+	Parent         string
 	Title          string
 	Status         string
 	GroupProfileId string
@@ -482,6 +623,30 @@ type GroupProfileResponse struct {
 	Resource GroupProfile
 }
 
+// Group membership
+type AddGroupProfileMemberResponse struct {
+	GroupMembership GroupMembership
+}
+
+// GroupMembership is the link between a group profile and a profile that is a member of it
+type GroupMembership struct {
+	Turbot struct {
+		GroupProfileId string
+		ProfileId      string
+	}
+}
+
+type ReadGroupProfileMemberPageResponse struct {
+	ResourceList struct {
+		Items  []Profile
+		Paging Paging
+	}
+}
+
+func (response *ReadGroupProfileMemberPageResponse) paging() Paging {
+	return response.ResourceList.Paging
+}
+
 // Metadata
 type TurbotResourceMetadata struct {
 	Id                string
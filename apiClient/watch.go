@@ -4,7 +4,7 @@ import (
 	"fmt"
 	"log"
 
-	"github.com/turbot/terraform-provider-turbot/errors"
+	"errors"
 )
 
 func (client *Client) CreateWatch(input map[string]interface{}) (*Watch, error) {
@@ -27,7 +27,7 @@ func (client *Client) WatchExists(id string) (bool, error) {
 	resource, err := client.ReadWatch(id)
 
 	if err != nil {
-		if errors.NotFoundError(err) {
+		if errors.Is(err, ErrNotFound) {
 			return false, nil
 		}
 		return false, err
@@ -68,7 +68,7 @@ func (client *Client) DeleteWatch(id string) error {
 
 	// execute api call
 	if err := client.doRequest(query, map[string]interface{}{"id": id}, &responseData); err != nil {
-		return fmt.Errorf("error deleting grant: %s", err.Error())
+		return fmt.Errorf("error deleting grant: %w", err)
 	}
 	log.Printf("Watch deleted: %s", id)
 	return nil
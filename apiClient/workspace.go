@@ -0,0 +1,27 @@
+package apiClient
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// workspaceUrl is the url of the workspace console, for the url of its GraphQL endpoint
+func workspaceUrl(apiUrl string) string {
+	u, err := url.Parse(apiUrl)
+	if err != nil {
+		return apiUrl
+	}
+	u.Path = ""
+	return u.String()
+}
+
+// ReadActor reads the profile the client's access key belongs to
+func (client *Client) ReadActor() (*Actor, error) {
+	responseData := &ReadActorResponse{}
+
+	// execute api call
+	if err := client.doRequest(readActorQuery(), nil, responseData); err != nil {
+		return nil, fmt.Errorf("error reading actor: %w", err)
+	}
+	return &responseData.Actor, nil
+}
@@ -38,9 +38,9 @@ import (
 // which for a policy setting or grant is destructive. A missed shape is surfaced by the DEBUG log in
 // NotFoundError below, so it is greppable rather than invisible.
 //
-// The complete fix for state membership is to decide from response shape rather than error text
-// (see apiClient.ErrTargetNotFound and the `notFound: RETURN_NULL` reads). This narrows the
-// text-based fallback that the many callers still using it depend on.
+// Callers should not use this directly: test errors.Is(err, apiClient.ErrNotFound), which decides
+// from the code and status in the response's `errors[].extensions`. apiClient falls back to this
+// matcher only for a workspace whose errors carry neither.
 var notFoundRegex = regexp.MustCompile(`(?i)not found:|resource not found`)
 
 func NotFoundError(err error) bool {
@@ -59,6 +59,7 @@ func NotFoundError(err error) bool {
 	return false
 }
 
+// FailedValidationError is, like NotFoundError, the text fallback behind apiClient.ErrSchemaValidation.
 func FailedValidationError(err error) bool {
 	dataValidationError := "(?i)data validation failed"
 	expectedErr := regexp.MustCompile(dataValidationError)
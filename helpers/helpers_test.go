@@ -184,3 +184,82 @@ func TestGetNullProperties(t *testing.T) {
 		assert.ObjectsAreEqual(test.expected, excluded)
 	}
 }
+
+func TestObjectFromStringMap(t *testing.T) {
+	type test struct {
+		name     string
+		data     map[string]interface{}
+		expected map[string]interface{}
+	}
+	tests := []test{
+		test{
+			"Strings are kept",
+			map[string]interface{}{"Title": "my folder", "Description": ""},
+			map[string]interface{}{"Title": "my folder", "Description": ""},
+		},
+		test{
+			"Numbers and bools are decoded",
+			map[string]interface{}{"Port": "8080", "Enabled": "true", "Missing": "null"},
+			map[string]interface{}{"Port": float64(8080), "Enabled": true, "Missing": nil},
+		},
+		test{
+			"Nested values are decoded",
+			map[string]interface{}{"Tags": `{"env":"prod"}`, "Regions": `["us-east-1"]`},
+			map[string]interface{}{"Tags": map[string]interface{}{"env": "prod"}, "Regions": []interface{}{"us-east-1"}},
+		},
+		test{
+			"Encoded strings are decoded to strings",
+			map[string]interface{}{"Version": `"8080"`},
+			map[string]interface{}{"Version": "8080"},
+		},
+	}
+	for _, test := range tests {
+		log.Println(test.name)
+		assert.Equal(t, test.expected, ObjectFromStringMap(test.data))
+	}
+}
+
+// the map forms must be interchangeable with the JSON string forms: the object read back from the
+// API converts to a map which decodes to the same object, and to the same JSON
+func TestStringMapFromObjectRoundTrip(t *testing.T) {
+	body := `{"Title":"t","Port":8080,"Enabled":false,"Version":"8080","Quoted":"\"q\"","Tags":{"env":"prod"},"Regions":["a","b"],"Empty":null}`
+	object, err := JsonStringToMap(body)
+	assert.NoError(t, err)
+
+	data, err := StringMapFromObject(object)
+	assert.NoError(t, err)
+	assert.Equal(t, "t", data["Title"], "a plain string is written as itself")
+	assert.Equal(t, `"8080"`, data["Version"], "a string that looks like a number is kept a string")
+
+	roundTripped := ObjectFromStringMap(data)
+	assert.Equal(t, object, roundTripped)
+	jsonString, err := MapToJsonString(roundTripped)
+	assert.NoError(t, err)
+	assert.Equal(t, FormatJson(body), jsonString)
+}
+
+func TestObjectValuesAreEqual(t *testing.T) {
+	assert.True(t, ObjectValuesAreEqual(`{"b":1,"a":2}`, "{\n \"a\": 2,\n \"b\": 1\n}"))
+	assert.True(t, ObjectValuesAreEqual("8080", "8080.0"))
+	assert.True(t, ObjectValuesAreEqual("my folder", "my folder"))
+	assert.False(t, ObjectValuesAreEqual("8080", `"8080"`))
+	assert.False(t, ObjectValuesAreEqual(`["a","b"]`, `["b","a"]`))
+}
+
+func TestInterfaceToStringOrJson(t *testing.T) {
+	for _, test := range []struct {
+		value    interface{}
+		expected string
+	}{
+		{nil, ""},
+		{"Skip", "Skip"},
+		{json.Number("1"), "1"},
+		{true, "true"},
+		{[]interface{}{"a", "b"}, `["a","b"]`},
+		{map[string]interface{}{"b": 1, "a": []interface{}{}}, `{"a":[],"b":1}`},
+	} {
+		result, err := InterfaceToStringOrJson(test.value)
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, result)
+	}
+}
@@ -0,0 +1,384 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// SchemaViolation is a part of a value that its JSON schema rejects. Path is the JSON pointer to the
+// part, "" for the value itself.
+type SchemaViolation struct {
+	Path    string
+	Message string
+}
+
+func (v SchemaViolation) Error() string {
+	path := v.Path
+	if path == "" {
+		path = "/"
+	}
+	return fmt.Sprintf("%s %s", path, v.Message)
+}
+
+// ValidateJsonSchema checks value against the parts of JSON schema that Guardrails definitions use -
+// type, enum, const, the string, numeric and array bounds, pattern, items, properties, required,
+// additionalProperties, allOf, anyOf and oneOf - and returns every violation. Keywords it does not
+// know are ignored, so a value it accepts may still be rejected by the server, but never the reverse.
+// value may be decoded from JSON or from YAML.
+func ValidateJsonSchema(value interface{}, schema map[string]interface{}) []SchemaViolation {
+	return validateJsonSchema(normaliseSchemaValue(value), schema, "")
+}
+
+func validateJsonSchema(value interface{}, schema map[string]interface{}, path string) []SchemaViolation {
+	violation := func(format string, args ...interface{}) []SchemaViolation {
+		return []SchemaViolation{{Path: path, Message: fmt.Sprintf(format, args...)}}
+	}
+	if schemaType, ok := schema["type"]; ok && !matchesSchemaType(value, schemaType) {
+		// nothing else can be checked against a value of the wrong type
+		return violation("must be %s, got %s", describeSchemaType(schemaType), schemaTypeOf(value))
+	}
+	if allowed, ok := schema["const"]; ok && !schemaValuesAreEqual(allowed, value) {
+		return violation("must be %s", formatSchemaValue(allowed))
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok && !enumContains(enum, value) {
+		return violation("%s", enumMessage(enum, value))
+	}
+
+	var violations []SchemaViolation
+	for _, subSchema := range schemaList(schema["allOf"]) {
+		violations = append(violations, validateJsonSchema(value, subSchema, path)...)
+	}
+	if anyOf := schemaList(schema["anyOf"]); len(anyOf) > 0 && countMatchingSchemas(value, anyOf, path) == 0 {
+		violations = append(violations, violation("must match at least one of the schemas in anyOf")...)
+	}
+	if oneOf := schemaList(schema["oneOf"]); len(oneOf) > 0 && countMatchingSchemas(value, oneOf, path) != 1 {
+		violations = append(violations, violation("must match exactly one of the schemas in oneOf")...)
+	}
+
+	switch v := value.(type) {
+	case string:
+		length := float64(len([]rune(v)))
+		if minimum, ok := schemaNumber(schema["minLength"]); ok && length < minimum {
+			violations = append(violations, violation("must be at least %v characters long", minimum)...)
+		}
+		if maximum, ok := schemaNumber(schema["maxLength"]); ok && length > maximum {
+			violations = append(violations, violation("must be at most %v characters long", maximum)...)
+		}
+		if pattern, ok := schema["pattern"].(string); ok {
+			// a pattern Go cannot compile is left to the server
+			if re, err := regexp.Compile(pattern); err == nil && !re.MatchString(v) {
+				violations = append(violations, violation("must match the pattern %q", pattern)...)
+			}
+		}
+	case []interface{}:
+		length := float64(len(v))
+		if minimum, ok := schemaNumber(schema["minItems"]); ok && length < minimum {
+			violations = append(violations, violation("must have at least %v items", minimum)...)
+		}
+		if maximum, ok := schemaNumber(schema["maxItems"]); ok && length > maximum {
+			violations = append(violations, violation("must have at most %v items", maximum)...)
+		}
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				violations = append(violations, validateJsonSchema(item, items, fmt.Sprintf("%s/%d", path, i))...)
+			}
+		}
+	case map[string]interface{}:
+		if required, ok := schema["required"].([]interface{}); ok {
+			for _, name := range required {
+				if _, ok := v[fmt.Sprintf("%v", name)]; !ok {
+					violations = append(violations, violation("must have the property %q", fmt.Sprintf("%v", name))...)
+				}
+			}
+		}
+		properties, _ := schema["properties"].(map[string]interface{})
+		for _, name := range sortedKeys(v) {
+			propertyPath := path + "/" + escapeJsonPointer(name)
+			if property, ok := properties[name].(map[string]interface{}); ok {
+				violations = append(violations, validateJsonSchema(v[name], property, propertyPath)...)
+				continue
+			}
+			if _, ok := properties[name]; ok {
+				continue
+			}
+			switch additional := schema["additionalProperties"].(type) {
+			case bool:
+				if !additional {
+					message := "is not an allowed property"
+					if suggestions := closeMatches(name, sortedKeys(properties)); len(suggestions) > 0 {
+						message += fmt.Sprintf(" - did you mean %s?", quoteAll(suggestions))
+					}
+					violations = append(violations, SchemaViolation{Path: propertyPath, Message: message})
+				}
+			case map[string]interface{}:
+				violations = append(violations, validateJsonSchema(v[name], additional, propertyPath)...)
+			}
+		}
+	default:
+		if number, ok := schemaNumber(value); ok {
+			if minimum, ok := schemaNumber(schema["minimum"]); ok && number < minimum {
+				violations = append(violations, violation("must be >= %v", minimum)...)
+			}
+			if maximum, ok := schemaNumber(schema["maximum"]); ok && number > maximum {
+				violations = append(violations, violation("must be <= %v", maximum)...)
+			}
+			if minimum, ok := schemaNumber(schema["exclusiveMinimum"]); ok && number <= minimum {
+				violations = append(violations, violation("must be > %v", minimum)...)
+			}
+			if maximum, ok := schemaNumber(schema["exclusiveMaximum"]); ok && number >= maximum {
+				violations = append(violations, violation("must be < %v", maximum)...)
+			}
+		}
+	}
+	return violations
+}
+
+func countMatchingSchemas(value interface{}, schemas []map[string]interface{}, path string) int {
+	count := 0
+	for _, subSchema := range schemas {
+		if len(validateJsonSchema(value, subSchema, path)) == 0 {
+			count++
+		}
+	}
+	return count
+}
+
+func schemaList(value interface{}) []map[string]interface{} {
+	list, _ := value.([]interface{})
+	var schemas []map[string]interface{}
+	for _, item := range list {
+		if subSchema, ok := item.(map[string]interface{}); ok {
+			schemas = append(schemas, subSchema)
+		}
+	}
+	return schemas
+}
+
+func matchesSchemaType(value interface{}, schemaType interface{}) bool {
+	if types, ok := schemaType.([]interface{}); ok {
+		for _, t := range types {
+			if matchesSchemaType(value, t) {
+				return true
+			}
+		}
+		return false
+	}
+	switch schemaType {
+	case "string", "boolean", "array", "object", "null":
+		return schemaTypeOf(value) == schemaType
+	case "number":
+		_, ok := schemaNumber(value)
+		return ok
+	case "integer":
+		number, ok := schemaNumber(value)
+		return ok && number == float64(int64(number))
+	}
+	// an unknown type is left to the server
+	return true
+}
+
+// schemaTypeOf returns the JSON schema type of a decoded value
+func schemaTypeOf(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	if _, ok := schemaNumber(value); ok {
+		return "number"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func describeSchemaType(schemaType interface{}) string {
+	if types, ok := schemaType.([]interface{}); ok {
+		var names []string
+		for _, t := range types {
+			names = append(names, fmt.Sprintf("%v", t))
+		}
+		return "one of " + strings.Join(names, ", ")
+	}
+	return fmt.Sprintf("%v", schemaType)
+}
+
+// schemaNumber reads a number from decoded JSON or YAML, whichever decoder produced it
+func schemaNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case json.Number:
+		number, err := v.Float64()
+		return number, err == nil
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	}
+	return 0, false
+}
+
+func schemaValuesAreEqual(a, b interface{}) bool {
+	if numberA, ok := schemaNumber(a); ok {
+		numberB, ok := schemaNumber(b)
+		return ok && numberA == numberB
+	}
+	return reflect.DeepEqual(normaliseSchemaValue(a), normaliseSchemaValue(b))
+}
+
+func enumContains(enum []interface{}, value interface{}) bool {
+	for _, allowed := range enum {
+		if schemaValuesAreEqual(allowed, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// enumMessage lists the allowed values, suggesting the closest of them to a string value
+func enumMessage(enum []interface{}, value interface{}) string {
+	var allowed []string
+	var candidates []string
+	for _, item := range enum {
+		allowed = append(allowed, formatSchemaValue(item))
+		if s, ok := item.(string); ok {
+			candidates = append(candidates, s)
+		}
+	}
+	message := fmt.Sprintf("must be one of %s", strings.Join(allowed, ", "))
+	if s, ok := value.(string); ok {
+		if suggestions := closeMatches(s, candidates); len(suggestions) > 0 {
+			message += fmt.Sprintf(" - did you mean %s?", quoteAll(suggestions))
+		}
+	}
+	return message
+}
+
+// closeMatches returns the candidates within a few edits of value, closest first. A candidate that
+// differs only in case is always a match.
+func closeMatches(value string, candidates []string) []string {
+	type match struct {
+		candidate string
+		distance  int
+	}
+	var matches []match
+	for _, candidate := range candidates {
+		distance := editDistance(strings.ToLower(value), strings.ToLower(candidate))
+		// allow roughly one edit in three characters, and at least two
+		limit := len([]rune(candidate)) / 3
+		if limit < 2 {
+			limit = 2
+		}
+		if distance <= limit {
+			matches = append(matches, match{candidate, distance})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].distance < matches[j].distance })
+	var result []string
+	for _, m := range matches {
+		result = append(result, m.candidate)
+	}
+	return result
+}
+
+// editDistance is the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	runesA, runesB := []rune(a), []rune(b)
+	previous := make([]int, len(runesB)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(runesA); i++ {
+		current := make([]int, len(runesB)+1)
+		current[0] = i
+		for j := 1; j <= len(runesB); j++ {
+			cost := 1
+			if runesA[i-1] == runesB[j-1] {
+				cost = 0
+			}
+			current[j] = minInt(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous = current
+	}
+	return previous[len(runesB)]
+}
+
+func minInt(values ...int) int {
+	result := values[0]
+	for _, v := range values[1:] {
+		if v < result {
+			result = v
+		}
+	}
+	return result
+}
+
+func formatSchemaValue(value interface{}) string {
+	data, err := json.Marshal(normaliseSchemaValue(value))
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
+
+func quoteAll(values []string) string {
+	var quoted []string
+	for _, value := range values {
+		quoted = append(quoted, fmt.Sprintf("%q", value))
+	}
+	return strings.Join(quoted, " or ")
+}
+
+// escapeJsonPointer escapes a property name for use as a JSON pointer segment, as RFC 6901 requires
+func escapeJsonPointer(name string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(name, "~", "~0"), "/", "~1")
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	var keys []string
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// normaliseSchemaValue converts the map[interface{}]interface{} values the YAML decoder produces into
+// JSON shaped data
+func normaliseSchemaValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			result[fmt.Sprintf("%v", key)] = normaliseSchemaValue(item)
+		}
+		return result
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			result[key] = normaliseSchemaValue(item)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			result[i] = normaliseSchemaValue(item)
+		}
+		return result
+	}
+	return value
+}
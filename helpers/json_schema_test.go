@@ -0,0 +1,74 @@
+package helpers
+
+import (
+	"testing"
+
+	"github.com/go-yaml/yaml"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateJsonSchema(t *testing.T) {
+	approvedSchema := map[string]interface{}{
+		"type": "string",
+		"enum": []interface{}{"Skip", "Check: Enabled", "Enforce: Enabled"},
+	}
+	objectSchema := map[string]interface{}{
+		"type":     "object",
+		"required": []interface{}{"Name"},
+		"properties": map[string]interface{}{
+			"Name":    map[string]interface{}{"type": "string", "minLength": float64(3)},
+			"Port":    map[string]interface{}{"type": "integer", "minimum": float64(1), "maximum": float64(65535)},
+			"Regions": map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string", "pattern": "^[a-z]+-[a-z]+-[0-9]$"}},
+		},
+		"additionalProperties": false,
+	}
+	for _, test := range []struct {
+		name     string
+		value    interface{}
+		schema   map[string]interface{}
+		expected []string
+	}{
+		{"enum value", "Check: Enabled", approvedSchema, nil},
+		{"enum typo suggests the close match", "Check: Enable", approvedSchema, []string{`/ must be one of "Skip", "Check: Enabled", "Enforce: Enabled" - did you mean "Check: Enabled"?`}},
+		{"enum miss without a close match", "Disabled", approvedSchema, []string{`/ must be one of "Skip", "Check: Enabled", "Enforce: Enabled"`}},
+		{"wrong type", float64(5), approvedSchema, []string{"/ must be string, got number"}},
+		{"type list", nil, map[string]interface{}{"type": []interface{}{"string", "null"}}, nil},
+		{"integer", 5, map[string]interface{}{"type": "integer"}, nil},
+		{"not an integer", 5.5, map[string]interface{}{"type": "integer"}, []string{"/ must be integer, got number"}},
+		{"valid object", map[string]interface{}{"Name": "web", "Port": 8080, "Regions": []interface{}{"us-east-1"}}, objectSchema, nil},
+		{
+			"every violation in an object is reported by its path",
+			map[string]interface{}{"Name": "ab", "Port": float64(0), "Regions": []interface{}{"us-east-1", "nowhere"}, "name": "x"},
+			objectSchema,
+			[]string{
+				"/Name must be at least 3 characters long",
+				"/Port must be >= 1",
+				`/Regions/1 must match the pattern "^[a-z]+-[a-z]+-[0-9]$"`,
+				`/name is not an allowed property - did you mean "Name"?`,
+			},
+		},
+		{"missing required property", map[string]interface{}{}, objectSchema, []string{`/ must have the property "Name"`}},
+		{"property names are escaped", map[string]interface{}{"a/b": 1}, map[string]interface{}{"additionalProperties": map[string]interface{}{"type": "string"}}, []string{"/a~1b must be string, got number"}},
+		{"anyOf", "x", map[string]interface{}{"anyOf": []interface{}{map[string]interface{}{"type": "integer"}, map[string]interface{}{"type": "boolean"}}}, []string{"/ must match at least one of the schemas in anyOf"}},
+		{"unknown keywords are ignored", "x", map[string]interface{}{"format": "email", "type": "string"}, nil},
+	} {
+		var messages []string
+		for _, violation := range ValidateJsonSchema(test.value, test.schema) {
+			messages = append(messages, violation.Error())
+		}
+		assert.Equal(t, test.expected, messages, test.name)
+	}
+}
+
+// a value parsed from YAML validates as the same value decoded from JSON
+func TestValidateJsonSchemaYaml(t *testing.T) {
+	var value interface{}
+	if err := yaml.Unmarshal([]byte("Name: web\nPort: 0\n"), &value); err != nil {
+		t.Fatal(err)
+	}
+	violations := ValidateJsonSchema(value, map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{"Port": map[string]interface{}{"minimum": float64(1)}},
+	})
+	assert.Equal(t, []SchemaViolation{{Path: "/Port", Message: "must be >= 1"}}, violations)
+}
@@ -2,6 +2,7 @@ package helpers
 
 import (
 	"encoding/json"
+	"fmt"
 	"github.com/hashicorp/terraform/helper/encryption"
 	"reflect"
 )
@@ -154,3 +155,50 @@ func GetOldMapProperties(old, new map[string]interface{}) []interface{} {
 	}
 	return result
 }
+
+// ObjectFromStringMap converts the value of a Terraform map attribute into an object. A Terraform map
+// holds only strings, so each value is decoded as JSON where it can be - "8080", "true" and
+// jsonencode({...}) become a number, a bool and an object - and is otherwise kept as the string. A
+// string that would itself decode as JSON is written jsonencode("8080").
+func ObjectFromStringMap(data map[string]interface{}) map[string]interface{} {
+	var object = map[string]interface{}{}
+	for k, v := range data {
+		object[k] = ObjectValueFromString(fmt.Sprintf("%v", v))
+	}
+	return object
+}
+
+// ObjectValueFromString decodes a single map element - see ObjectFromStringMap.
+func ObjectValueFromString(value string) interface{} {
+	var decoded interface{}
+	if err := json.Unmarshal([]byte(value), &decoded); err != nil {
+		return value
+	}
+	return decoded
+}
+
+// StringMapFromObject is the inverse of ObjectFromStringMap, converting an object read from the API
+// into a value for a Terraform map attribute, such that ObjectFromStringMap(StringMapFromObject(o))
+// is o.
+func StringMapFromObject(object map[string]interface{}) (map[string]interface{}, error) {
+	var data = map[string]interface{}{}
+	for k, v := range object {
+		// a string is kept as it is unless decoding would change its type
+		if s, ok := v.(string); ok && !json.Valid([]byte(s)) {
+			data[k] = s
+			continue
+		}
+		jsonBytes, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		data[k] = string(jsonBytes)
+	}
+	return data, nil
+}
+
+// ObjectValuesAreEqual reports whether two map elements decode to the same value, ignoring JSON
+// formatting and key order.
+func ObjectValuesAreEqual(value1, value2 string) bool {
+	return reflect.DeepEqual(ObjectValueFromString(value1), ObjectValueFromString(value2))
+}
@@ -1,6 +1,7 @@
 package helpers
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/go-yaml/yaml"
 )
@@ -30,6 +31,22 @@ func InterfaceToString(value interface{}) string {
 	return fmt.Sprintf("%v", value)
 }
 
+// if the value is already a string return it, otherwise convert to the JSON representation
+func InterfaceToStringOrJson(value interface{}) (string, error) {
+	if value == nil {
+		return "", nil
+	}
+	if res, ok := value.(string); ok {
+		return res, nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
 // if the value is already a string return it, otherwise convert to the YAML representation
 func InterfaceToStringOrYaml(value interface{}) (string, error) {
 	if value == nil {
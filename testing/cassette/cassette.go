@@ -0,0 +1,362 @@
+// Package cassette records the provider's Guardrails API traffic to a file, and replays it later
+// without a workspace.
+//
+// A Recorder is an http.RoundTripper, installed as apiClient.Transport. In Record mode it sends each
+// request on to the workspace and keeps the request and response; Save writes them out. In Replay
+// mode it answers each request from the file and never touches the network.
+//
+// A request is matched on its GraphQL query and variables - not on headers, and not on its place in
+// the run - so reads that Terraform happens to issue in a different order still find their answers.
+// Identical requests are answered in the order they were recorded, the last answer being repeated
+// once they run out. apiClient builds each query in a fixed order, so the same call always makes the
+// same request.
+//
+// Credentials and secrets never reach the file. The Authorization header is redacted, and the
+// workspace host is not kept. In the variables and response bodies, the values of secret fields -
+// passwords, client secrets, secret and private keys - are redacted wherever they appear, as is any
+// value passed to Redact. A request is redacted before it is matched, so a replay finds it all the
+// same.
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+type Mode int
+
+const (
+	// Replay answers requests from a recorded cassette
+	Replay Mode = iota
+	// Record sends requests to the workspace and records them
+	Record
+)
+
+// a redacted header or value as a cassette keeps it
+const redacted = "REDACTED"
+
+// the fields whose values are secret wherever they appear in variables or a response, lower case
+var secretFields = map[string]bool{
+	"password":     true,
+	"clientsecret": true,
+	"secretkey":    true,
+	"privatekey":   true,
+}
+
+// Cassette is the file format: every request made, with the response it got, in order
+type Cassette struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+type Request struct {
+	Method    string                 `json:"method"`
+	Path      string                 `json:"path"`
+	Headers   map[string]string      `json:"headers,omitempty"`
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables,omitempty"`
+}
+
+type Response struct {
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers,omitempty"`
+	// a JSON response body is kept as JSON, so a cassette diffs readably; anything else - a
+	// gateway's HTML error page, say - as text
+	Body     json.RawMessage `json:"body,omitempty"`
+	BodyText string          `json:"bodyText,omitempty"`
+}
+
+// the response headers worth keeping - the ones apiClient reads
+var responseHeaders = []string{"Content-Type", "Retry-After", "X-Request-Id"}
+
+// Recorder records or replays API traffic. It is safe for concurrent use.
+type Recorder struct {
+	mode Mode
+	path string
+	// Base is the transport requests are recorded from. Nil means http.DefaultTransport.
+	Base http.RoundTripper
+
+	mutex    sync.Mutex
+	cassette *Cassette
+	// replay state: the recorded interactions for each request key, and how many have been used
+	recorded map[string][]*Interaction
+	used     map[string]int
+	misses   []string
+	// the values to redact wherever they appear, see Redact
+	secrets []string
+}
+
+// New returns a Recorder for the cassette at path. In Replay mode the cassette must already exist.
+func New(path string, mode Mode) (*Recorder, error) {
+	r := &Recorder{
+		mode:     mode,
+		path:     path,
+		cassette: &Cassette{},
+		recorded: map[string][]*Interaction{},
+		used:     map[string]int{},
+	}
+	if mode == Replay {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read cassette %s: %s", path, err.Error())
+		}
+		// decode numbers as json.Number, as a recorded request's variables were, so they key alike
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		decoder.UseNumber()
+		if err := decoder.Decode(r.cassette); err != nil {
+			return nil, fmt.Errorf("failed to parse cassette %s: %s", path, err.Error())
+		}
+		for _, interaction := range r.cassette.Interactions {
+			key := requestKey(interaction.Request.Query, interaction.Request.Variables)
+			r.recorded[key] = append(r.recorded[key], interaction)
+		}
+	}
+	return r, nil
+}
+
+// Redact adds values to redact wherever they appear in the requests and responses, for secrets the
+// traffic does not mark as such - the value of a secret policy, say. A replay must redact the same
+// values as its recording, so its requests match.
+func (r *Recorder) Redact(values ...string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, value := range values {
+		if value != "" {
+			r.secrets = append(r.secrets, value)
+		}
+	}
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	request, err := r.readRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	if r.mode == Replay {
+		return r.replay(req, request), nil
+	}
+
+	base := r.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	res, err := base.RoundTrip(req)
+	if err != nil {
+		// a failed round trip has no response to replay, so it is not recorded
+		return nil, err
+	}
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(body))
+
+	response := Response{Status: res.StatusCode, Headers: map[string]string{}}
+	for _, name := range responseHeaders {
+		if value := res.Header.Get(name); value != "" {
+			response.Headers[name] = value
+		}
+	}
+	r.mutex.Lock()
+	if redactedBody, ok := r.redactBody(body); ok {
+		response.Body = json.RawMessage(redactedBody)
+	} else {
+		response.BodyText = r.redactString(string(body))
+	}
+	r.cassette.Interactions = append(r.cassette.Interactions, &Interaction{Request: *request, Response: response})
+	r.mutex.Unlock()
+	return res, nil
+}
+
+// replay answers req from the cassette. A request that was never recorded gets a 400 with a GraphQL
+// error naming it, rather than a transport error - apiClient would retry a transport error, and the
+// run should fail fast and say what is missing.
+func (r *Recorder) replay(req *http.Request, request *Request) *http.Response {
+	key := requestKey(request.Query, request.Variables)
+	r.mutex.Lock()
+	interactions := r.recorded[key]
+	var interaction *Interaction
+	if len(interactions) > 0 {
+		index := r.used[key]
+		if index >= len(interactions) {
+			index = len(interactions) - 1
+		}
+		interaction = interactions[index]
+		r.used[key]++
+	} else {
+		r.misses = append(r.misses, key)
+	}
+	r.mutex.Unlock()
+
+	if interaction == nil {
+		message, _ := json.Marshal(fmt.Sprintf("cassette %s has no recorded interaction for this request - re-record it: %s", r.path, key))
+		return newResponse(req, http.StatusBadRequest, map[string]string{"Content-Type": "application/json"},
+			[]byte(fmt.Sprintf(`{"errors":[{"message":%s,"extensions":{"code":"CASSETTE_MISS"}}]}`, message)))
+	}
+	body := []byte(interaction.Response.Body)
+	if len(body) == 0 {
+		body = []byte(interaction.Response.BodyText)
+	}
+	return newResponse(req, interaction.Response.Status, interaction.Response.Headers, body)
+}
+
+func newResponse(req *http.Request, status int, headers map[string]string, body []byte) *http.Response {
+	header := http.Header{}
+	for name, value := range headers {
+		header.Set(name, value)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// Misses returns the requests a replay could not answer, so a test can report them all at once
+func (r *Recorder) Misses() []string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]string(nil), r.misses...)
+}
+
+// Save writes the recorded interactions to the cassette file. It does nothing in Replay mode.
+func (r *Recorder) Save() error {
+	if r.mode != Record {
+		return nil
+	}
+	r.mutex.Lock()
+	content, err := json.MarshalIndent(r.cassette, "", "  ")
+	r.mutex.Unlock()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, append(content, '\n'), 0644)
+}
+
+// readRequest captures a GraphQL request, redacted, leaving req's body readable for the real round trip
+func (r *Recorder) readRequest(req *http.Request) (*Request, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	var graphqlRequest struct {
+		Query     string                 `json:"query"`
+		Variables map[string]interface{} `json:"variables"`
+	}
+	if len(body) > 0 {
+		decoder := json.NewDecoder(bytes.NewReader(body))
+		decoder.UseNumber()
+		if err := decoder.Decode(&graphqlRequest); err != nil {
+			return nil, fmt.Errorf("cassette: request body is not a GraphQL request: %s", err.Error())
+		}
+	}
+	r.mutex.Lock()
+	variables, _ := r.redact(graphqlRequest.Variables).(map[string]interface{})
+	r.mutex.Unlock()
+	request := &Request{
+		Method:    req.Method,
+		Path:      req.URL.Path,
+		Headers:   map[string]string{},
+		Query:     graphqlRequest.Query,
+		Variables: variables,
+	}
+	for name := range req.Header {
+		if name == "Authorization" {
+			request.Headers[name] = redacted
+			continue
+		}
+		request.Headers[name] = req.Header.Get(name)
+	}
+	return request, nil
+}
+
+// redactBody returns a JSON response body with its secrets redacted, or false if it is not JSON.
+// r.mutex must be held.
+func (r *Recorder) redactBody(body []byte) ([]byte, bool) {
+	if !json.Valid(body) {
+		return nil, false
+	}
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return nil, false
+	}
+	redactedBody, err := json.Marshal(r.redact(value))
+	if err != nil {
+		return nil, false
+	}
+	return redactedBody, true
+}
+
+// redact returns a copy of a decoded JSON value with the values of secret fields, and the secrets
+// passed to Redact, replaced. r.mutex must be held.
+func (r *Recorder) redact(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		if value == nil {
+			return value
+		}
+		result := make(map[string]interface{}, len(value))
+		for key, element := range value {
+			if secretFields[strings.ToLower(key)] && element != nil {
+				result[key] = redacted
+				continue
+			}
+			result[key] = r.redact(element)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(value))
+		for i, element := range value {
+			result[i] = r.redact(element)
+		}
+		return result
+	case string:
+		return r.redactString(value)
+	default:
+		return value
+	}
+}
+
+// redactString replaces the secrets passed to Redact in s. r.mutex must be held.
+func (r *Recorder) redactString(s string) string {
+	for _, secret := range r.secrets {
+		s = strings.ReplaceAll(s, secret, redacted)
+	}
+	return s
+}
+
+// requestKey identifies a request for replay: its query with insignificant whitespace removed, and
+// its variables as canonical JSON
+func requestKey(query string, variables map[string]interface{}) string {
+	encoded, _ := json.Marshal(variables)
+	return strings.Join(strings.Fields(query), " ") + " " + string(encoded)
+}
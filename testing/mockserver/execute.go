@@ -0,0 +1,136 @@
+package mockserver
+
+import (
+	"fmt"
+)
+
+// object is a node of the response graph. Its fields are resolved on demand, so a selection only
+// pays for - and only fails on - the fields it asks for.
+type object interface {
+	resolve(name string, arguments map[string]interface{}) (interface{}, error)
+}
+
+// fields is an object whose fields are all known up front. A field that is resolved lazily, or that
+// takes arguments, is stored as a resolver.
+type fields map[string]interface{}
+
+type resolver func(arguments map[string]interface{}) (interface{}, error)
+
+func (f fields) resolve(name string, arguments map[string]interface{}) (interface{}, error) {
+	value, ok := f[name]
+	if !ok {
+		return nil, validationFailed("Cannot query field %q", name)
+	}
+	if resolve, ok := value.(resolver); ok {
+		return resolve(arguments)
+	}
+	return value, nil
+}
+
+// graphqlError is one entry of the `errors` array of a response, shaped as the Guardrails API
+// shapes it - see apiClient/api_error.go for the reading side.
+type graphqlError struct {
+	Message    string                 `json:"message"`
+	Path       []interface{}          `json:"path,omitempty"`
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+}
+
+// apiError is an error a resolver raises to the client, carrying the extensions code the provider
+// classifies it by.
+type apiError struct {
+	code    string
+	message string
+}
+
+func (e *apiError) Error() string {
+	return e.message
+}
+
+func notFound(format string, args ...interface{}) error {
+	return &apiError{code: "NOT_FOUND", message: "Not Found: " + fmt.Sprintf(format, args...)}
+}
+
+func validationFailed(format string, args ...interface{}) error {
+	return &apiError{code: "DATA_VALIDATION_FAILED", message: "Data Validation Failed: " + fmt.Sprintf(format, args...)}
+}
+
+func permissionDenied(format string, args ...interface{}) error {
+	return &apiError{code: "FORBIDDEN", message: "Permission denied: " + fmt.Sprintf(format, args...)}
+}
+
+func conflict(format string, args ...interface{}) error {
+	return &apiError{code: "CONFLICT", message: fmt.Sprintf(format, args...)}
+}
+
+func newGraphqlError(err error, path []interface{}) *graphqlError {
+	code := "INTERNAL_SERVER_ERROR"
+	if e, ok := err.(*apiError); ok {
+		code = e.code
+	}
+	return &graphqlError{
+		Message:    err.Error(),
+		Path:       path,
+		Extensions: map[string]interface{}{"code": code},
+	}
+}
+
+// executor answers one operation. A field that fails resolves to null and records an error at its
+// path, so - as with the real API - one failing alias of a batched read does not fail the others.
+type executor struct {
+	variables map[string]interface{}
+	errors    []*graphqlError
+}
+
+func (e *executor) executeSelection(value object, selection []*field, path []interface{}) map[string]interface{} {
+	result := map[string]interface{}{}
+	for _, f := range selection {
+		fieldPath := appendPath(path, f.responseKey())
+		arguments := map[string]interface{}{}
+		for name, argument := range f.arguments {
+			arguments[name] = resolveArguments(argument, e.variables)
+		}
+		resolved, err := value.resolve(f.name, arguments)
+		if err != nil {
+			e.errors = append(e.errors, newGraphqlError(err, fieldPath))
+			result[f.responseKey()] = nil
+			continue
+		}
+		result[f.responseKey()] = e.complete(resolved, f, fieldPath)
+	}
+	return result
+}
+
+// complete shapes a resolved value by the field's selection
+func (e *executor) complete(value interface{}, f *field, path []interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	switch v := value.(type) {
+	case []object:
+		list := make([]interface{}, len(v))
+		for i, item := range v {
+			list[i] = e.complete(item, f, appendPath(path, i))
+		}
+		return list
+	case object:
+		if f.selection == nil {
+			e.errors = append(e.errors, newGraphqlError(validationFailed("Field %q must have a selection of subfields", f.name), path))
+			return nil
+		}
+		return e.executeSelection(v, f.selection, path)
+	case map[string]interface{}:
+		// JSON data: a selection picks from it, otherwise it is returned whole
+		if f.selection != nil {
+			return e.executeSelection(fields(v), f.selection, path)
+		}
+	}
+	return value
+}
+
+// appendPath returns a copy of path with element appended, so sibling fields never share a backing
+// array.
+func appendPath(path []interface{}, element interface{}) []interface{} {
+	result := make([]interface{}, len(path), len(path)+1)
+	copy(result, path)
+	return append(result, element)
+}
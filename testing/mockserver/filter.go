@@ -0,0 +1,186 @@
+package mockserver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// filter is a parsed Guardrails filter, as passed to the list queries: `policyType:x resource:y
+// level:self`. Only the keys the provider uses are understood; any other key is rejected, so a query
+// that starts relying on one fails here rather than being silently answered unfiltered.
+type filter struct {
+	terms map[string][]string
+	// free text terms, matched against the title
+	text []string
+	// `$.path:value` terms, matched against the resource document
+	paths map[string]string
+}
+
+var filterKeys = map[string]string{
+	"resource":        "resource",
+	"resourceId":      "resource",
+	"resourceType":    "resourceType",
+	"resourceTypeId":  "resourceType",
+	"resourceTypeUri": "resourceType",
+	"policyType":      "policyType",
+	"policyTypeId":    "policyType",
+	"controlType":     "controlType",
+	"controlTypeId":   "controlType",
+	"groupProfile":    "groupProfile",
+	"groupProfileId":  "groupProfile",
+	"identity":        "identity",
+	"identityId":      "identity",
+	"level":           "level",
+	"limit":           "limit",
+	"state":           "state",
+}
+
+// parseFilter parses the `filter` argument, a list of filter strings that must all match
+func parseFilter(argument interface{}) (*filter, error) {
+	f := &filter{terms: map[string][]string{}, paths: map[string]string{}}
+	var sources []string
+	switch v := argument.(type) {
+	case nil:
+	case string:
+		sources = []string{v}
+	case []interface{}:
+		for _, item := range v {
+			if source, ok := item.(string); ok {
+				sources = append(sources, source)
+			}
+		}
+	default:
+		return nil, validationFailed("filter must be a list of strings")
+	}
+	for _, source := range sources {
+		for _, term := range splitFilter(source) {
+			separator := strings.Index(term, ":")
+			if separator <= 0 {
+				f.text = append(f.text, strings.ToLower(unquote(term)))
+				continue
+			}
+			if strings.HasPrefix(term, "$.") {
+				f.paths[term[2:separator]] = unquote(term[separator+1:])
+				continue
+			}
+			key, ok := filterKeys[term[:separator]]
+			if !ok {
+				return nil, validationFailed("unsupported filter key %q", term[:separator])
+			}
+			f.terms[key] = append(f.terms[key], unquote(term[separator+1:]))
+		}
+	}
+	return f, nil
+}
+
+// splitFilter splits a filter string on whitespace outside quotes
+func splitFilter(source string) []string {
+	var terms []string
+	var term strings.Builder
+	var quote rune
+	for _, c := range source {
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+			term.WriteRune(c)
+		case c == '\'' || c == '"':
+			quote = c
+			term.WriteRune(c)
+		case c == ' ' || c == '\t' || c == '\n':
+			if term.Len() > 0 {
+				terms = append(terms, term.String())
+				term.Reset()
+			}
+		default:
+			term.WriteRune(c)
+		}
+	}
+	if term.Len() > 0 {
+		terms = append(terms, term.String())
+	}
+	return terms
+}
+
+func unquote(value string) string {
+	if len(value) >= 2 && (value[0] == '\'' || value[0] == '"') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
+
+func (f *filter) value(key string) (string, bool) {
+	values := f.terms[key]
+	if len(values) == 0 {
+		return "", false
+	}
+	return values[len(values)-1], true
+}
+
+// limit returns the limit the filter sets, or -1 when it sets none
+func (f *filter) limit() (int, error) {
+	value, ok := f.value("limit")
+	if !ok {
+		return -1, nil
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 0 {
+		return 0, validationFailed("invalid limit %q", value)
+	}
+	return limit, nil
+}
+
+// level returns the hierarchy level the filter scopes its resource to, defaulting to defaultLevel
+func (f *filter) level(defaultLevel string) (string, error) {
+	level, ok := f.value("level")
+	if !ok {
+		return defaultLevel, nil
+	}
+	switch level {
+	case "self", "child", "descendant", "sub", "ancestor", "super":
+		return level, nil
+	}
+	return "", validationFailed("invalid level %q", level)
+}
+
+// matchesText reports whether title contains every free text term
+func (f *filter) matchesText(title string) bool {
+	title = strings.ToLower(title)
+	for _, text := range f.text {
+		if !strings.Contains(title, text) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesPaths reports whether every `$.path:value` term matches the document
+func (f *filter) matchesPaths(document map[string]interface{}) bool {
+	for path, value := range f.paths {
+		if found := getPath(document, path); found == nil || fmt.Sprint(found) != value {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesLevel reports whether candidate stands in the given relation to the scope resource
+func (s *store) matchesLevel(candidate *resource, scope *resource, level string) bool {
+	switch level {
+	case "self":
+		return candidate.id == scope.id
+	case "child":
+		return candidate.parentId == scope.id
+	case "descendant":
+		return candidate.id != scope.id && s.isWithin(candidate, scope.id)
+	case "sub":
+		return s.isWithin(candidate, scope.id)
+	case "ancestor":
+		return candidate.id != scope.id && s.isWithin(scope, candidate.id)
+	case "super":
+		return s.isWithin(scope, candidate.id)
+	}
+	return false
+}
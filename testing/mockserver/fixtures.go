@@ -0,0 +1,242 @@
+package mockserver
+
+// The workspace a new Server starts with: the Turbot root, the mods and type definitions the
+// provider's acceptance tests use, and the handful of pre-existing resources and controls those tests
+// refer to by id - stand-ins for what the tests expect to find in a real test workspace.
+
+const fixtureRootId = "162167737977850"
+
+// the profile the default identity's access key belongs to, which holds Turbot/Owner on the root
+const fixtureProfileId = "184227597900001"
+
+// resource types installed in the fixture workspace
+var fixtureResourceTypes = []string{
+	turbotType,
+	folderType,
+	smartFolderType,
+	modType,
+	policyTypeType,
+	controlTypeType,
+	"tmod:@turbot/turbot#/resource/types/file",
+	permissionTypeType,
+	permissionLevelType,
+	profileType,
+	"tmod:@turbot/turbot-iam#/resource/types/localDirectory",
+	"tmod:@turbot/turbot-iam#/resource/types/localDirectoryUser",
+	"tmod:@turbot/turbot-iam#/resource/types/turbotDirectory",
+	"tmod:@turbot/turbot-iam#/resource/types/googleDirectory",
+	"tmod:@turbot/turbot-iam#/resource/types/samlDirectory",
+	"tmod:@turbot/turbot-iam#/resource/types/ldapDirectory",
+	groupProfileType,
+	"tmod:@turbot/aws#/resource/types/account",
+	"tmod:@turbot/aws#/resource/types/region",
+	"tmod:@turbot/aws-logs#/resource/types/logGroup",
+}
+
+// the rest of the definition of the fixture resource types that tests read it from: the category,
+// the types a resource can be created in, and the schemas its data is checked against
+var fixtureResourceTypeData = map[string]map[string]interface{}{
+	folderType: {
+		"category": "tmod:@turbot/turbot#/resource/categories/folder",
+		"parent":   []interface{}{turbotType, folderType},
+		"createSchema": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"title":       map[string]interface{}{"type": "string", "minLength": 1},
+				"description": map[string]interface{}{"type": "string"},
+			},
+			"required": []interface{}{"title"},
+		},
+		"updateSchema": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"title":       map[string]interface{}{"type": "string", "minLength": 1},
+				"description": map[string]interface{}{"type": "string"},
+			},
+		},
+	},
+}
+
+// policy types installed in the fixture workspace, with the schema a setting value is checked against
+var fixturePolicyTypes = []struct {
+	uri          string
+	schema       map[string]interface{}
+	defaultValue interface{}
+	targets      []interface{}
+	secret       bool
+}{
+	{uri: "tmod:@turbot/turbot#/policy/types/workspaceVersion", schema: stringSchema(), defaultValue: "5.45.0"},
+	{uri: "tmod:@turbot/aws#/policy/types/turbotIamRoleExternalId", schema: stringSchema(), defaultValue: "turbot"},
+	{uri: "tmod:@turbot/aws#/policy/types/regionStackSource", schema: stringSchema(), defaultValue: ""},
+	{uri: "tmod:@turbot/aws-s3#/policy/types/bucketApprovedUsage", schema: map[string]interface{}{
+		"type": "string",
+		"enum": []interface{}{"Skip", "Check: Approved", "Delete unapproved if new", "Testing", "Check: Configured"},
+	}, defaultValue: "Skip", targets: []interface{}{"tmod:@turbot/aws-s3#/resource/types/bucket"}},
+	{uri: "tmod:@turbot/aws-s3#/policy/types/bucketTagsTemplate", schema: map[string]interface{}{"type": "object"}, defaultValue: map[string]interface{}{}},
+	{uri: "tmod:@turbot/provider-policy-test#/policy/types/stringPolicy", schema: stringSchema()},
+	{uri: "tmod:@turbot/provider-policy-test#/policy/types/secretPolicy", schema: stringSchema(), secret: true},
+	{uri: "tmod:@turbot/provider-policy-test#/policy/types/integerPolicy", schema: map[string]interface{}{"type": "integer"}},
+	{uri: "tmod:@turbot/provider-policy-test#/policy/types/stringArrayPolicy", schema: map[string]interface{}{
+		"type":  "array",
+		"items": stringSchema(),
+	}},
+	{uri: "tmod:@turbot/provider-policy-test#/policy/types/objectOrArrayPolicy", schema: map[string]interface{}{
+		"type": []interface{}{"object", "array"},
+	}},
+}
+
+func stringSchema() map[string]interface{} {
+	return map[string]interface{}{"type": "string"}
+}
+
+// mods in the fixture registry, with the versions available to install. The aws mod has one which is
+// not semver, as the registry holds for some old mods - turbot_mod fails on it, so no test installs aws.
+var fixtureModVersions = map[string][]string{
+	"turbot/turbot-terraform-provider-test": {"5.0.0", "5.0.1", "5.0.2", "5.1.0"},
+	"turbot/aws":                            {"legacy", "5.0.0", "5.1.0"},
+}
+
+// seed populates an empty store with the fixture workspace
+func seed(s *store) {
+	root := &resource{
+		id:      fixtureRootId,
+		typeUri: turbotType,
+		data:    map[string]interface{}{"title": "Turbot"},
+		akas:    []string{rootAka},
+	}
+	root.versionId, root.createTimestamp, root.updateTimestamp = fixtureRootId, timestamp(), timestamp()
+	s.resources[root.id] = root
+	s.rootId = root.id
+
+	// every definition belongs to its mod, installed at the root
+	installed := map[string]*resource{}
+	mod := func(uri string) *resource {
+		uri = modUri(uri)
+		if m, ok := installed[uri]; ok {
+			return m
+		}
+		m := s.insertResource(root, modType, map[string]interface{}{"version": "5.0.0"}, nil, nil, []string{uri})
+		m.public = true
+		installed[uri] = m
+		return m
+	}
+	define := func(typeUri, uri string, data map[string]interface{}) *resource {
+		definition := s.insertResource(mod(uri), typeUri, data, nil, nil, []string{uri})
+		definition.public = true
+		return definition
+	}
+
+	define(resourceTypeType, resourceTypeType, map[string]interface{}{"title": "Resource Type"})
+	for _, uri := range fixtureResourceTypes {
+		data := map[string]interface{}{"title": definitionTitle(uri)}
+		for key, value := range fixtureResourceTypeData[uri] {
+			data[key] = copyValue(value)
+		}
+		define(resourceTypeType, uri, data)
+	}
+	for _, policyType := range fixturePolicyTypes {
+		data := map[string]interface{}{"title": definitionTitle(policyType.uri), "schema": policyType.schema}
+		if policyType.defaultValue != nil {
+			data["default"] = policyType.defaultValue
+		}
+		if policyType.targets != nil {
+			data["targets"] = policyType.targets
+		}
+		if policyType.secret {
+			data["secret"] = true
+		}
+		define(policyTypeType, policyType.uri, data)
+	}
+	define(controlTypeType, "tmod:@turbot/turbot#/control/types/controlInstalled", map[string]interface{}{"title": "Control Installed"})
+	define(controlTypeType, "tmod:@turbot/aws#/control/types/accountCmdb", map[string]interface{}{"title": "Account CMDB"})
+	turbotPermission := define(permissionTypeType, "tmod:@turbot/turbot-iam#/permission/types/turbot", map[string]interface{}{"title": "Turbot"})
+	define(permissionTypeType, "tmod:@turbot/turbot-iam#/permission/types/aws", map[string]interface{}{"title": "AWS"})
+	levels := map[string]*resource{}
+	for _, level := range []string{"user", "metadata", "readOnly", "operator", "admin", "owner"} {
+		levels[level] = define(permissionLevelType, "tmod:@turbot/turbot-iam#/permission/levels/"+level, map[string]interface{}{"title": level})
+	}
+	define(permissionTypeType, "tmod:@turbot/firehose-aws-sns#/action/types/router", map[string]interface{}{"title": "Router"})
+
+	for name, versions := range fixtureModVersions {
+		for i, version := range versions {
+			status := "AVAILABLE"
+			if i == len(versions)-1 {
+				status = "RECOMMENDED"
+			}
+			s.modVersions[name] = append(s.modVersions[name], modVersion{version: version, status: status})
+		}
+	}
+
+	// the pre-existing resources the acceptance tests refer to by id
+	fixture := func(id string, parent *resource, typeUri string, data map[string]interface{}, akas ...string) *resource {
+		r := s.insertResource(parent, typeUri, data, nil, nil, akas)
+		delete(s.resources, r.id)
+		r.id = id
+		s.resources[id] = r
+		return r
+	}
+	directory := fixture("184227597889872", root, "tmod:@turbot/turbot-iam#/resource/types/localDirectory", map[string]interface{}{
+		"title":             "Test Directory",
+		"profileIdTemplate": "{{profile.email}}",
+		"status":            "ACTIVE",
+	})
+	fixture(fixtureProfileId, directory, profileType, map[string]interface{}{
+		"title":     "Provider Test",
+		"email":     "provider-test@turbot.com",
+		"profileId": "provider-test@turbot.com",
+		"status":    "Active",
+	})
+	s.grants["184227597900002"] = &grant{
+		id:                "184227597900002",
+		profileId:         fixtureProfileId,
+		resourceId:        root.id,
+		permissionTypeId:  turbotPermission.id,
+		permissionLevelId: levels["owner"].id,
+	}
+	s.activeGrants["184227597900003"] = &activeGrant{
+		id:         "184227597900003",
+		grantId:    "184227597900002",
+		resourceId: root.id,
+	}
+	fixture("184298093985240", root, "tmod:@turbot/turbot-iam#/resource/types/localDirectory", map[string]interface{}{
+		"title":             "Provider Test Directory",
+		"profileIdTemplate": "{{profile.email}}",
+		"status":            "ACTIVE",
+	})
+	accounts := fixture("178806515404441", root, folderType, map[string]interface{}{"title": "AWS Accounts"})
+	account := fixture("178806515411691", accounts, "tmod:@turbot/aws#/resource/types/account", map[string]interface{}{
+		"Id":    "713469427990",
+		"title": "provider-test",
+	}, "arn:aws:::713469427990")
+	region := fixture("178806515500000", account, "tmod:@turbot/aws#/resource/types/region", map[string]interface{}{
+		"RegionName": "us-east-2",
+	}, "arn:aws::us-east-2:713469427990")
+	fixture("178806515600000", region, "tmod:@turbot/aws-logs#/resource/types/logGroup", map[string]interface{}{
+		"logGroupName": "provider-test-hashicorp",
+	}, "arn:aws:logs:us-east-2:713469427990:log-group:provider-test-hashicorp")
+
+	s.controls["178806515688264"] = &control{
+		id:         "178806515688264",
+		resourceId: account.id,
+		typeUri:    "tmod:@turbot/turbot#/control/types/controlInstalled",
+		state:      "ok",
+		reason:     "Installed",
+	}
+	s.controls["330102006163524"] = &control{
+		id:         "330102006163524",
+		resourceId: account.id,
+		typeUri:    "tmod:@turbot/aws#/control/types/accountCmdb",
+		state:      "ok",
+		reason:     "Recorded",
+	}
+}
+
+// definitionTitle derives a title from the last segment of a definition uri
+func definitionTitle(uri string) string {
+	for i := len(uri) - 1; i >= 0; i-- {
+		if uri[i] == '/' {
+			return uri[i+1:]
+		}
+	}
+	return uri
+}
@@ -0,0 +1,410 @@
+package mockserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// operation is a parsed GraphQL request document.
+//
+// The parser understands exactly what the provider sends - see apiClient/queries.go: a single
+// query or mutation, optionally named, with aliases, arguments and nested selections. Variable
+// definitions are skipped (variables are taken as sent, untyped), and fragments and directives are
+// rejected rather than silently ignored, so a query builder that starts using them fails loudly here
+// instead of passing against a mock that does not understand it.
+type operation struct {
+	kind      string
+	name      string
+	selection []*field
+}
+
+type field struct {
+	alias     string
+	name      string
+	arguments map[string]interface{}
+	selection []*field
+}
+
+// responseKey is the key the field is answered under
+func (f *field) responseKey() string {
+	if f.alias != "" {
+		return f.alias
+	}
+	return f.name
+}
+
+// variable is an unresolved `$name` reference in an argument value
+type variable string
+
+type parser struct {
+	source string
+	pos    int
+}
+
+func parseOperation(source string) (*operation, error) {
+	p := &parser{source: source}
+	op := &operation{kind: "query"}
+
+	p.skipIgnored()
+	if !p.peek('{') {
+		keyword := p.name()
+		if keyword != "query" && keyword != "mutation" {
+			return nil, p.errorf("expected query or mutation, got %q", keyword)
+		}
+		op.kind = keyword
+		p.skipIgnored()
+		if !p.peek('(') && !p.peek('{') {
+			op.name = p.name()
+			p.skipIgnored()
+		}
+		if p.peek('(') {
+			if err := p.skipVariableDefinitions(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	selection, err := p.selectionSet()
+	if err != nil {
+		return nil, err
+	}
+	op.selection = selection
+	p.skipIgnored()
+	if p.pos < len(p.source) {
+		return nil, p.errorf("only a single operation is supported")
+	}
+	return op, nil
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("Syntax Error: %s (at offset %d)", fmt.Sprintf(format, args...), p.pos)
+}
+
+// skipIgnored skips whitespace, commas and comments, which are insignificant in GraphQL
+func (p *parser) skipIgnored() {
+	for p.pos < len(p.source) {
+		switch c := p.source[p.pos]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',':
+			p.pos++
+		case c == '#':
+			for p.pos < len(p.source) && p.source[p.pos] != '\n' {
+				p.pos++
+			}
+		case strings.HasPrefix(p.source[p.pos:], "\ufeff"):
+			p.pos += len("\ufeff")
+		default:
+			return
+		}
+	}
+}
+
+func (p *parser) peek(c byte) bool {
+	return p.pos < len(p.source) && p.source[p.pos] == c
+}
+
+func (p *parser) expect(c byte) error {
+	p.skipIgnored()
+	if !p.peek(c) {
+		return p.errorf("expected %q", c)
+	}
+	p.pos++
+	return nil
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isNameContinue(c byte) bool {
+	return isNameStart(c) || (c >= '0' && c <= '9')
+}
+
+// name reads a name at the current position, returning "" if there is none
+func (p *parser) name() string {
+	start := p.pos
+	if p.pos < len(p.source) && isNameStart(p.source[p.pos]) {
+		p.pos++
+		for p.pos < len(p.source) && isNameContinue(p.source[p.pos]) {
+			p.pos++
+		}
+	}
+	return p.source[start:p.pos]
+}
+
+// skipVariableDefinitions skips `($a: ID!, $b: [String!] = ["x"])`. The declared types are not
+// checked: the provider's variables are trusted to match, as the apiClient tests already pin the
+// documents.
+func (p *parser) skipVariableDefinitions() error {
+	depth := 0
+	for p.pos < len(p.source) {
+		switch p.source[p.pos] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				p.pos++
+				p.skipIgnored()
+				return nil
+			}
+		case '"':
+			if _, err := p.stringValue(); err != nil {
+				return err
+			}
+			continue
+		}
+		p.pos++
+	}
+	return p.errorf("unterminated variable definitions")
+}
+
+func (p *parser) selectionSet() ([]*field, error) {
+	if err := p.expect('{'); err != nil {
+		return nil, err
+	}
+	var fields []*field
+	for {
+		p.skipIgnored()
+		if p.peek('}') {
+			p.pos++
+			break
+		}
+		if p.pos >= len(p.source) {
+			return nil, p.errorf("unterminated selection set")
+		}
+		if strings.HasPrefix(p.source[p.pos:], "...") {
+			return nil, p.errorf("fragments are not supported")
+		}
+		f, err := p.field()
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, f)
+	}
+	if len(fields) == 0 {
+		return nil, p.errorf("empty selection set")
+	}
+	return fields, nil
+}
+
+func (p *parser) field() (*field, error) {
+	f := &field{name: p.name()}
+	if f.name == "" {
+		return nil, p.errorf("expected a field name")
+	}
+	p.skipIgnored()
+	if p.peek(':') {
+		p.pos++
+		p.skipIgnored()
+		f.alias = f.name
+		if f.name = p.name(); f.name == "" {
+			return nil, p.errorf("expected a field name after alias %q", f.alias)
+		}
+		p.skipIgnored()
+	}
+	if p.peek('(') {
+		arguments, err := p.arguments()
+		if err != nil {
+			return nil, err
+		}
+		f.arguments = arguments
+		p.skipIgnored()
+	}
+	if p.peek('@') {
+		return nil, p.errorf("directives are not supported")
+	}
+	if p.peek('{') {
+		selection, err := p.selectionSet()
+		if err != nil {
+			return nil, err
+		}
+		f.selection = selection
+	}
+	return f, nil
+}
+
+func (p *parser) arguments() (map[string]interface{}, error) {
+	if err := p.expect('('); err != nil {
+		return nil, err
+	}
+	arguments := map[string]interface{}{}
+	for {
+		p.skipIgnored()
+		if p.peek(')') {
+			p.pos++
+			return arguments, nil
+		}
+		name := p.name()
+		if name == "" {
+			return nil, p.errorf("expected an argument name")
+		}
+		if err := p.expect(':'); err != nil {
+			return nil, err
+		}
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		arguments[name] = value
+	}
+}
+
+func (p *parser) value() (interface{}, error) {
+	p.skipIgnored()
+	if p.pos >= len(p.source) {
+		return nil, p.errorf("expected a value")
+	}
+	switch c := p.source[p.pos]; {
+	case c == '$':
+		p.pos++
+		name := p.name()
+		if name == "" {
+			return nil, p.errorf("expected a variable name")
+		}
+		return variable(name), nil
+	case c == '"':
+		return p.stringValue()
+	case c == '[':
+		p.pos++
+		list := []interface{}{}
+		for {
+			p.skipIgnored()
+			if p.peek(']') {
+				p.pos++
+				return list, nil
+			}
+			item, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, item)
+		}
+	case c == '{':
+		p.pos++
+		object := map[string]interface{}{}
+		for {
+			p.skipIgnored()
+			if p.peek('}') {
+				p.pos++
+				return object, nil
+			}
+			name := p.name()
+			if name == "" {
+				return nil, p.errorf("expected an object field name")
+			}
+			if err := p.expect(':'); err != nil {
+				return nil, err
+			}
+			item, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			object[name] = item
+		}
+	case c == '-' || (c >= '0' && c <= '9'):
+		start := p.pos
+		p.pos++
+		for p.pos < len(p.source) && strings.IndexByte("0123456789.eE+-", p.source[p.pos]) >= 0 {
+			p.pos++
+		}
+		number := json.Number(p.source[start:p.pos])
+		if _, err := number.Float64(); err != nil {
+			return nil, p.errorf("invalid number %q", number)
+		}
+		return number, nil
+	case isNameStart(c):
+		switch name := p.name(); name {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		default:
+			// an enum value - the mock has no schema to check it against, so it is passed on as its name
+			return name, nil
+		}
+	}
+	return nil, p.errorf("unexpected character %q", p.source[p.pos])
+}
+
+// stringValue reads a quoted string, decoding the GraphQL escape sequences. Block strings are not
+// supported - the provider never sends one.
+func (p *parser) stringValue() (string, error) {
+	if strings.HasPrefix(p.source[p.pos:], `"""`) {
+		return "", p.errorf("block strings are not supported")
+	}
+	p.pos++
+	var value strings.Builder
+	for p.pos < len(p.source) {
+		c := p.source[p.pos]
+		switch c {
+		case '"':
+			p.pos++
+			return value.String(), nil
+		case '\n':
+			return "", p.errorf("unterminated string")
+		case '\\':
+			if p.pos+1 >= len(p.source) {
+				return "", p.errorf("unterminated string")
+			}
+			escape := p.source[p.pos+1]
+			p.pos += 2
+			switch escape {
+			case '"', '\\', '/':
+				value.WriteByte(escape)
+			case 'b':
+				value.WriteByte('\b')
+			case 'f':
+				value.WriteByte('\f')
+			case 'n':
+				value.WriteByte('\n')
+			case 'r':
+				value.WriteByte('\r')
+			case 't':
+				value.WriteByte('\t')
+			case 'u':
+				if p.pos+4 > len(p.source) {
+					return "", p.errorf("invalid unicode escape")
+				}
+				code, err := strconv.ParseUint(p.source[p.pos:p.pos+4], 16, 32)
+				if err != nil {
+					return "", p.errorf("invalid unicode escape")
+				}
+				value.WriteRune(rune(code))
+				p.pos += 4
+			default:
+				return "", p.errorf("invalid escape \\%c", escape)
+			}
+		default:
+			r, size := utf8.DecodeRuneInString(p.source[p.pos:])
+			value.WriteRune(r)
+			p.pos += size
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
+
+// resolveArguments substitutes the request variables into parsed argument values. An undefined
+// variable resolves to null, as it does in GraphQL for a nullable argument.
+func resolveArguments(value interface{}, variables map[string]interface{}) interface{} {
+	switch v := value.(type) {
+	case variable:
+		return variables[string(v)]
+	case []interface{}:
+		resolved := make([]interface{}, len(v))
+		for i, item := range v {
+			resolved[i] = resolveArguments(item, variables)
+		}
+		return resolved
+	case map[string]interface{}:
+		resolved := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			resolved[key] = resolveArguments(item, variables)
+		}
+		return resolved
+	}
+	return value
+}
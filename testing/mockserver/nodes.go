@@ -0,0 +1,272 @@
+package mockserver
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-yaml/yaml"
+)
+
+// resourceNode is a resource as the Resource type of the API presents it
+type resourceNode struct {
+	store *store
+	r     *resource
+}
+
+func (n *resourceNode) resolve(name string, arguments map[string]interface{}) (interface{}, error) {
+	switch name {
+	case "get":
+		path, _ := arguments["path"].(string)
+		return getPath(n.store.document(n.r), path), nil
+	case "turbot":
+		return fields(n.store.metadata(n.r)), nil
+	case "data":
+		return copyValue(n.r.data), nil
+	case "type":
+		return n.store.definition(n.r.typeUri), nil
+	case "attachedSmartFolders":
+		// like the API, this takes no paging arguments and returns every attachment in one page
+		var items []object
+		for _, id := range n.r.attached {
+			if pack, ok := n.store.resources[id]; ok {
+				items = append(items, &resourceNode{store: n.store, r: pack})
+			}
+		}
+		return fields{
+			"items":  items,
+			"paging": fields{"next": nil},
+		}, nil
+	}
+	return nil, validationFailed("Cannot query field %q on type \"Resource\"", name)
+}
+
+// definition is the node for a type definition - resource, policy, control or permission type - by
+// uri. A definition no mod installed resolves to a bare uri, as the API answers `type { uri }` for any
+// resource whatever its type.
+func (s *store) definition(uri string) object {
+	definition := s.find(uri)
+	if definition == nil {
+		return fields{"uri": uri}
+	}
+	return &definitionNode{store: s, r: definition}
+}
+
+type definitionNode struct {
+	store *store
+	r     *resource
+}
+
+func (n *definitionNode) resolve(name string, arguments map[string]interface{}) (interface{}, error) {
+	switch name {
+	case "uri":
+		return n.uri(), nil
+	case "modUri":
+		return modUri(n.uri()), nil
+	case "title":
+		return n.r.title(), nil
+	case "turbot":
+		return fields(n.store.metadata(n.r)), nil
+	case "get":
+		path, _ := arguments["path"].(string)
+		return getPath(n.store.document(n.r), path), nil
+	}
+	// the remaining fields of a definition - schema, defaultTemplate, createSchema... - are its data
+	if value, ok := n.r.data[name]; ok {
+		return copyValue(value), nil
+	}
+	return nil, nil
+}
+
+func (n *definitionNode) uri() string {
+	if len(n.r.akas) == 0 {
+		return ""
+	}
+	return n.r.akas[0]
+}
+
+// modUri is the uri of the mod that defines the given type uri: tmod:@turbot/aws-s3#/policy/types/x
+// is defined by tmod:@turbot/aws-s3
+func modUri(uri string) string {
+	for i := 0; i < len(uri); i++ {
+		if uri[i] == '#' {
+			return uri[:i]
+		}
+	}
+	return uri
+}
+
+func (s *store) policySettingNode(setting *policySetting) object {
+	var valueSource interface{}
+	if setting.valueSource != "" {
+		valueSource = setting.valueSource
+	}
+	return fields{
+		"type":               s.definition(setting.typeUri),
+		"value":              copyValue(setting.value),
+		"secretValue":        copyValue(setting.value),
+		"valueSource":        valueSource,
+		"secretValueSource":  valueSource,
+		"template":           setting.template,
+		"templateInput":      copyValue(setting.templateInput),
+		"input":              templateInputSource(setting.templateInput),
+		"default":            false,
+		"precedence":         setting.precedence,
+		"note":               setting.note,
+		"validFromTimestamp": setting.validFrom,
+		"validToTimestamp":   setting.validTo,
+		"turbot": fields{
+			"id":         setting.id,
+			"parentId":   setting.resourceId,
+			"resourceId": setting.resourceId,
+			"akas":       nil,
+			"tags":       nil,
+		},
+	}
+}
+
+// templateInputSource is a setting's template input as the `input` field reports it: the source
+// text, whether the input was given as a single query or as a list of them
+func templateInputSource(templateInput interface{}) interface{} {
+	if templateInput == nil {
+		return nil
+	}
+	if source, ok := templateInput.(string); ok {
+		return source
+	}
+	source, err := yaml.Marshal(templateInput)
+	if err != nil {
+		return nil
+	}
+	return string(source)
+}
+
+func (s *store) grantNode(g *grant) object {
+	return fields{
+		"permissionTypeId":  g.permissionTypeId,
+		"permissionLevelId": g.permissionLevelId,
+		"type":              s.definition(g.permissionTypeId),
+		"level":             s.definition(g.permissionLevelId),
+		"turbot": fields{
+			"id":         g.id,
+			"profileId":  g.profileId,
+			"resourceId": g.resourceId,
+		},
+	}
+}
+
+func (s *store) activeGrantNode(active *activeGrant) object {
+	var g interface{}
+	if granted, ok := s.grants[active.grantId]; ok {
+		g = s.grantNode(granted)
+	}
+	return fields{
+		"grant": g,
+		"turbot": fields{
+			"id":         active.id,
+			"grantId":    active.grantId,
+			"resourceId": active.resourceId,
+		},
+	}
+}
+
+func groupMembershipNode(group, profile *resource) object {
+	return fields{
+		"turbot": fields{
+			"groupProfileId": group.id,
+			"profileId":      profile.id,
+		},
+	}
+}
+
+func watchNode(w *watch) object {
+	return fields{
+		"description": w.description,
+		"filters":     copyValue(w.filters),
+		"handler":     map[string]interface{}{"action": w.action},
+		"turbot": fields{
+			"id":         w.id,
+			"resourceId": w.resourceId,
+			"favoriteId": w.favoriteId,
+		},
+	}
+}
+
+func (s *store) controlNode(c *control) object {
+	mute := map[string]interface{}{}
+	for key, value := range c.mute {
+		mute[key] = copyValue(value)
+	}
+	return fields{
+		"type":    s.definition(c.typeUri),
+		"state":   c.state,
+		"mute":    mute,
+		"reason":  c.reason,
+		"details": copyValue(c.details),
+		"turbot": fields{
+			"id":         c.id,
+			"resourceId": c.resourceId,
+		},
+	}
+}
+
+// listNode is a complete list of results, as a single page
+func listNode(items []object) object {
+	return fields{
+		"items":  items,
+		"paging": fields{"next": nil},
+		"metadata": fields{
+			"stats": fields{"total": len(items)},
+		},
+	}
+}
+
+// defaultPageSize is the page size of a list whose filter sets no limit
+const defaultPageSize = 100
+
+// pageNode is the page of items that the `paging` argument asks for - the first page when it is
+// null - sized by the filter's limit, as the API pages. Its paging.next is the cursor for the page
+// after it, or null on the last page.
+func pageNode(items []object, arguments map[string]interface{}, f *filter) (object, error) {
+	size, err := f.limit()
+	if err != nil {
+		return nil, err
+	}
+	if size < 0 {
+		size = defaultPageSize
+	}
+	start := 0
+	if cursor, ok := arguments["paging"].(string); ok {
+		if start, err = decodeCursor(cursor); err != nil || start < 0 || start > len(items) {
+			return nil, validationFailed("invalid paging cursor %q", cursor)
+		}
+	}
+	end := start + size
+	var next interface{}
+	if end < len(items) {
+		next = encodeCursor(end)
+	} else {
+		end = len(items)
+	}
+	return fields{
+		"items":  items[start:end],
+		"paging": fields{"next": next},
+		"metadata": fields{
+			"stats": fields{"total": len(items)},
+		},
+	}, nil
+}
+
+// a paging cursor is opaque to the client; the mock's is the offset of the page
+func encodeCursor(offset int) string {
+	return base64.StdEncoding.EncodeToString([]byte("offset:" + strconv.Itoa(offset)))
+}
+
+func decodeCursor(cursor string) (int, error) {
+	decoded, err := base64.StdEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(decoded), "offset:") {
+		return 0, fmt.Errorf("invalid cursor")
+	}
+	return strconv.Atoi(strings.TrimPrefix(string(decoded), "offset:"))
+}
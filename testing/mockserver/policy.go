@@ -0,0 +1,234 @@
+package mockserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/go-yaml/yaml"
+)
+
+// Policy setting precedences, strongest first
+const (
+	precedenceRequired    = "REQUIRED"
+	precedenceRecommended = "RECOMMENDED"
+)
+
+// settingValue works out a setting's value and value source from a create or update input.
+//
+// As with the API, `value` must already have the type the policy schema asks for - a string is not
+// coerced to a number - while `valueSource` is YAML, parsed before it is checked. That distinction is
+// what the provider's value/valueSource retry relies on.
+func settingValue(policyType *resource, input map[string]interface{}) (value interface{}, valueSource string, err error) {
+	if source, ok := input["valueSource"]; ok && source != nil {
+		valueSource, ok = source.(string)
+		if !ok {
+			return nil, "", validationFailed("valueSource must be a string")
+		}
+		if err := yaml.Unmarshal([]byte(valueSource), &value); err != nil {
+			return nil, "", validationFailed("valueSource is not valid YAML: %s", err)
+		}
+		value = normaliseYaml(value)
+	} else {
+		value = input["value"]
+		if value != nil {
+			source, err := yaml.Marshal(value)
+			if err != nil {
+				return nil, "", validationFailed("value cannot be represented as YAML: %s", err)
+			}
+			valueSource = string(source)
+		}
+	}
+	if value == nil {
+		return nil, "", nil
+	}
+	if schema, ok := policyType.data["schema"].(map[string]interface{}); ok {
+		if err := validateSchema(value, schema, ""); err != nil {
+			return nil, "", err
+		}
+	}
+	return value, valueSource, nil
+}
+
+// normaliseYaml converts the map[interface{}]interface{} values the YAML decoder produces into JSON
+// shaped data
+func normaliseYaml(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			result[fmt.Sprintf("%v", key)] = normaliseYaml(item)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			result[i] = normaliseYaml(item)
+		}
+		return result
+	}
+	return value
+}
+
+// validateSchema checks value against the parts of JSON schema policy types use - type, enum, items,
+// properties, required and the numeric and length bounds. path is the JSON pointer to value, for the
+// error message.
+func validateSchema(value interface{}, schema map[string]interface{}, path string) error {
+	location := path
+	if location == "" {
+		location = "/"
+	}
+	if schemaType, ok := schema["type"]; ok && !matchesSchemaType(value, schemaType) {
+		return validationFailed("%s should be %v", location, schemaType)
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		found := false
+		for _, allowed := range enum {
+			if jsonEqual(allowed, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return validationFailed("%s should be equal to one of the allowed values %v", location, enum)
+		}
+	}
+	switch v := value.(type) {
+	case string:
+		if minimum, ok := schemaNumber(schema["minLength"]); ok && float64(len(v)) < minimum {
+			return validationFailed("%s should NOT be shorter than %v characters", location, minimum)
+		}
+		if maximum, ok := schemaNumber(schema["maxLength"]); ok && float64(len(v)) > maximum {
+			return validationFailed("%s should NOT be longer than %v characters", location, maximum)
+		}
+	case []interface{}:
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				if err := validateSchema(item, items, fmt.Sprintf("%s/%d", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+	case map[string]interface{}:
+		if required, ok := schema["required"].([]interface{}); ok {
+			for _, name := range required {
+				if _, ok := v[fmt.Sprintf("%v", name)]; !ok {
+					return validationFailed("%s should have required property '%v'", location, name)
+				}
+			}
+		}
+		if properties, ok := schema["properties"].(map[string]interface{}); ok {
+			for name, property := range properties {
+				propertySchema, ok := property.(map[string]interface{})
+				if item, present := v[name]; ok && present {
+					if err := validateSchema(item, propertySchema, path+"/"+name); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	default:
+		if number, ok := schemaNumber(value); ok {
+			if minimum, ok := schemaNumber(schema["minimum"]); ok && number < minimum {
+				return validationFailed("%s should be >= %v", location, minimum)
+			}
+			if maximum, ok := schemaNumber(schema["maximum"]); ok && number > maximum {
+				return validationFailed("%s should be <= %v", location, maximum)
+			}
+		}
+	}
+	return nil
+}
+
+func matchesSchemaType(value interface{}, schemaType interface{}) bool {
+	if types, ok := schemaType.([]interface{}); ok {
+		for _, t := range types {
+			if matchesSchemaType(value, t) {
+				return true
+			}
+		}
+		return false
+	}
+	switch schemaType {
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "null":
+		return value == nil
+	case "number":
+		_, ok := schemaNumber(value)
+		return ok
+	case "integer":
+		number, ok := schemaNumber(value)
+		return ok && number == float64(int64(number))
+	}
+	return true
+}
+
+// schemaNumber reads a number from decoded JSON or YAML, whichever decoder produced it
+func schemaNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case json.Number:
+		number, err := v.Float64()
+		return number, err == nil
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	}
+	return 0, false
+}
+
+func jsonEqual(a, b interface{}) bool {
+	if numberA, ok := schemaNumber(a); ok {
+		numberB, ok := schemaNumber(b)
+		return ok && numberA == numberB
+	}
+	encodedA, errA := json.Marshal(a)
+	encodedB, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(encodedA) == string(encodedB)
+}
+
+// effectiveSetting finds the setting that decides the value of a policy type on a resource: the
+// REQUIRED setting nearest the root, or failing that the RECOMMENDED setting nearest the resource.
+func (s *store) effectiveSetting(typeUri string, r *resource) *policySetting {
+	path := s.ancestors(r)
+	byResource := map[string]*policySetting{}
+	for _, setting := range s.settings {
+		if setting.typeUri == typeUri {
+			byResource[setting.resourceId] = setting
+		}
+	}
+	for _, id := range path {
+		if setting, ok := byResource[id]; ok && setting.precedence == precedenceRequired {
+			return setting
+		}
+	}
+	for i := len(path) - 1; i >= 0; i-- {
+		if setting, ok := byResource[path[i]]; ok {
+			return setting
+		}
+	}
+	return nil
+}
+
+func validPrecedence(precedence string) bool {
+	switch strings.ToUpper(precedence) {
+	case precedenceRequired, precedenceRecommended:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,1468 @@
+package mockserver
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver"
+)
+
+// root is the Query or Mutation type, for one caller
+type root struct {
+	store    *store
+	caller   *identity
+	mutation bool
+}
+
+type rootResolver func(r *root, arguments map[string]interface{}) (interface{}, error)
+
+// the root fields the mock answers - the operations in apiClient/queries.go
+var queries = map[string]rootResolver{
+	"__schema":          (*root).schema,
+	"resource":          (*root).resource,
+	"resourceList":      (*root).resourceList,
+	"policyPack":        (*root).policyPack,
+	"policySetting":     (*root).policySetting,
+	"policySettingList": (*root).policySettingList,
+	"policyValue":       (*root).policyValue,
+	"policyTypes":       (*root).policyTypes,
+	"grant":             (*root).grant,
+	"grants":            (*root).grants,
+	"activeGrant":       (*root).activeGrant,
+	"activeGrants":      (*root).activeGrants,
+	"actor":             (*root).actor,
+	"watch":             (*root).watch,
+	"control":           (*root).control,
+	"controlList":       (*root).controlList,
+	"modVersionList":    (*root).modVersionList,
+}
+
+var mutations = map[string]rootResolver{
+	"createResource":           (*root).createResource,
+	"updateResource":           (*root).updateResource,
+	"putResource":              (*root).putResource,
+	"deleteResource":           (*root).deleteResource,
+	"createSmartFolder":        typedCreate(smartFolderType),
+	"updateSmartFolder":        (*root).typedUpdate,
+	"deleteSmartFolder":        (*root).deleteResource,
+	"attachSmartFolders":       (*root).attachSmartFolders,
+	"detachSmartFolders":       (*root).detachSmartFolders,
+	"createTurbotDirectory":    typedCreate("tmod:@turbot/turbot-iam#/resource/types/turbotDirectory"),
+	"updateTurbotDirectory":    (*root).typedUpdate,
+	"createLocalDirectory":     typedCreate("tmod:@turbot/turbot-iam#/resource/types/localDirectory"),
+	"updateLocalDirectory":     (*root).typedUpdate,
+	"createGoogleDirectory":    typedCreate("tmod:@turbot/turbot-iam#/resource/types/googleDirectory"),
+	"updateGoogleDirectory":    (*root).typedUpdate,
+	"createSamlDirectory":      typedCreate("tmod:@turbot/turbot-iam#/resource/types/samlDirectory"),
+	"updateSamlDirectory":      (*root).typedUpdate,
+	"createLdapDirectory":      typedCreate("tmod:@turbot/turbot-iam#/resource/types/ldapDirectory"),
+	"updateLdapDirectory":      (*root).typedUpdate,
+	"createGroupProfile":       (*root).createGroupProfile,
+	"updateGroupProfile":       (*root).typedUpdate,
+	"deleteGroupProfile":       (*root).deleteResource,
+	"addGroupProfileMember":    (*root).addGroupProfileMember,
+	"removeGroupProfileMember": (*root).removeGroupProfileMember,
+	"installMod":               (*root).installMod,
+	"uninstallMod":             (*root).uninstallMod,
+	"createPolicySetting":      (*root).createPolicySetting,
+	"updatePolicySetting":      (*root).updatePolicySetting,
+	"deletePolicySetting":      (*root).deletePolicySetting,
+	"createGrant":              (*root).createGrant,
+	"deleteGrant":              (*root).deleteGrant,
+	"activateGrant":            (*root).activateGrant,
+	"deactivateGrant":          (*root).deactivateGrant,
+	"createWatch":              (*root).createWatch,
+	"updateWatch":              (*root).updateWatch,
+	"deleteWatch":              (*root).deleteWatch,
+	"muteControl":              (*root).muteControl,
+	"unmuteControl":            (*root).unmuteControl,
+}
+
+func (r *root) resolve(name string, arguments map[string]interface{}) (interface{}, error) {
+	fields, typeName := queries, "Query"
+	if r.mutation {
+		fields, typeName = mutations, "Mutation"
+	}
+	resolve, ok := fields[name]
+	if !ok {
+		return nil, validationFailed("Cannot query field %q on type %q", name, typeName)
+	}
+	return resolve(r, arguments)
+}
+
+// argument helpers
+
+func stringArgument(arguments map[string]interface{}, name string) string {
+	value, _ := arguments[name].(string)
+	return value
+}
+
+func requiredString(arguments map[string]interface{}, name string) (string, error) {
+	value, ok := arguments[name].(string)
+	if !ok || value == "" {
+		return "", validationFailed("%s is required", name)
+	}
+	return value, nil
+}
+
+func inputArgument(arguments map[string]interface{}) (map[string]interface{}, error) {
+	input, ok := arguments["input"].(map[string]interface{})
+	if !ok {
+		return nil, validationFailed("input is required")
+	}
+	return input, nil
+}
+
+func objectArgument(arguments map[string]interface{}, name string) (map[string]interface{}, error) {
+	value, ok := arguments[name]
+	if !ok || value == nil {
+		return nil, nil
+	}
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, validationFailed("%s must be an object", name)
+	}
+	return object, nil
+}
+
+func stringList(arguments map[string]interface{}, name string) ([]string, error) {
+	switch value := arguments[name].(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []string{value}, nil
+	case []interface{}:
+		var list []string
+		for _, item := range value {
+			s, ok := item.(string)
+			if !ok {
+				return nil, validationFailed("%s must be a list of strings", name)
+			}
+			list = append(list, s)
+		}
+		return list, nil
+	}
+	return nil, validationFailed("%s must be a list of strings", name)
+}
+
+// validation
+
+func (r *root) schema(map[string]interface{}) (interface{}, error) {
+	return fields{
+		"queryType":    fields{"name": "Query"},
+		"mutationType": fields{"name": "Mutation"},
+	}, nil
+}
+
+// actor is the caller: the profile its access key belongs to, if it has one
+func (r *root) actor(map[string]interface{}) (interface{}, error) {
+	var identity interface{}
+	if profile, ok := r.store.resources[r.caller.profileId]; ok {
+		identity = r.node(profile)
+	}
+	return fields{"identity": identity}, nil
+}
+
+// resources
+
+func (r *root) node(res *resource) object {
+	return &resourceNode{store: r.store, r: res}
+}
+
+func (r *root) resource(arguments map[string]interface{}) (interface{}, error) {
+	id, err := requiredString(arguments, "id")
+	if err != nil {
+		return nil, err
+	}
+	res, err := r.store.lookup(r.caller, id)
+	if err != nil {
+		// options: {notFound: RETURN_NULL} answers a missing resource with null rather than an error
+		options, _ := arguments["options"].(map[string]interface{})
+		if options["notFound"] == "RETURN_NULL" {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return r.node(res), nil
+}
+
+func (r *root) resourceList(arguments map[string]interface{}) (interface{}, error) {
+	f, err := parseFilter(arguments["filter"])
+	if err != nil {
+		return nil, err
+	}
+	var candidates []*resource
+	for _, res := range r.store.resources {
+		if r.store.canAccess(r.caller, res) {
+			candidates = append(candidates, res)
+		}
+	}
+	sortResources(candidates)
+
+	if id, ok := f.value("resource"); ok {
+		scope, err := r.store.lookup(r.caller, id)
+		if err != nil {
+			return nil, err
+		}
+		level, err := f.level("self")
+		if err != nil {
+			return nil, err
+		}
+		candidates = filterResources(candidates, func(res *resource) bool {
+			return r.store.matchesLevel(res, scope, level)
+		})
+	}
+	if id, ok := f.value("groupProfile"); ok {
+		group, err := r.store.lookup(r.caller, id)
+		if err != nil {
+			return nil, err
+		}
+		members := r.store.members[group.id]
+		candidates = filterResources(candidates, func(res *resource) bool {
+			return members[res.id]
+		})
+	}
+	if value, ok := f.value("resourceType"); ok {
+		// `resourceType:a,b` matches either type, each given by uri or id
+		typeUris := map[string]bool{}
+		for _, typeUri := range strings.Split(value, ",") {
+			typeUris[typeUri] = true
+			if resourceType := r.store.find(typeUri); resourceType != nil && len(resourceType.akas) > 0 {
+				typeUris[resourceType.akas[0]] = true
+			}
+		}
+		candidates = filterResources(candidates, func(res *resource) bool {
+			return typeUris[res.typeUri]
+		})
+	}
+	candidates = filterResources(candidates, func(res *resource) bool {
+		return f.matchesText(res.title()) && f.matchesPaths(r.store.document(res))
+	})
+	var items []object
+	for _, res := range candidates {
+		items = append(items, r.node(res))
+	}
+	return pageNode(items, arguments, f)
+}
+
+func filterResources(resources []*resource, keep func(*resource) bool) []*resource {
+	var result []*resource
+	for _, res := range resources {
+		if keep(res) {
+			result = append(result, res)
+		}
+	}
+	return result
+}
+
+func (r *root) createResource(arguments map[string]interface{}) (interface{}, error) {
+	input, err := inputArgument(arguments)
+	if err != nil {
+		return nil, err
+	}
+	typeUri, err := requiredString(input, "type")
+	if err != nil {
+		return nil, err
+	}
+	data, err := objectArgument(input, "data")
+	if err != nil {
+		return nil, err
+	}
+	return r.create(typeUri, input, data)
+}
+
+// typedCreate answers the type-specific create mutations - createSmartFolder, createLocalDirectory
+// and the like - whose input is the resource data itself, alongside parent, akas and tags.
+func typedCreate(typeUri string) rootResolver {
+	return func(r *root, arguments map[string]interface{}) (interface{}, error) {
+		input, err := inputArgument(arguments)
+		if err != nil {
+			return nil, err
+		}
+		return r.create(typeUri, input, typedData(input))
+	}
+}
+
+// typedData is the resource data of a typed create or update input: everything but the properties
+// that belong to the resource itself
+func typedData(input map[string]interface{}) map[string]interface{} {
+	data := map[string]interface{}{}
+	for key, value := range input {
+		switch key {
+		case "id", "parent", "akas", "tags", "metadata":
+			continue
+		case "clientId":
+			// a google directory takes clientId, but stores it - and the provider reads it - as clientID
+			data["clientID"] = value
+			continue
+		case "filter":
+			// a smart folder keeps its filter as a list
+			if filter, ok := value.(string); ok {
+				data["filters"] = []interface{}{filter}
+				continue
+			}
+		}
+		data[key] = value
+	}
+	return data
+}
+
+// createGroupProfile creates a group profile in the directory its input names, rather than in a parent
+func (r *root) createGroupProfile(arguments map[string]interface{}) (interface{}, error) {
+	input, err := inputArgument(arguments)
+	if err != nil {
+		return nil, err
+	}
+	directoryAka, err := requiredString(input, "directory")
+	if err != nil {
+		return nil, err
+	}
+	directory, err := r.store.lookup(r.caller, directoryAka)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(directory.typeUri, "Directory") {
+		return nil, validationFailed("%s is not a directory", directoryAka)
+	}
+	data := typedData(input)
+	delete(data, "directory")
+	input["parent"] = directoryAka
+	return r.create(groupProfileType, input, data)
+}
+
+// group membership
+
+func (r *root) addGroupProfileMember(arguments map[string]interface{}) (interface{}, error) {
+	group, profile, err := r.lookupGroupMembership(arguments)
+	if err != nil {
+		return nil, err
+	}
+	if r.store.members[group.id] == nil {
+		r.store.members[group.id] = map[string]bool{}
+	}
+	r.store.members[group.id][profile.id] = true
+	return groupMembershipNode(group, profile), nil
+}
+
+func (r *root) removeGroupProfileMember(arguments map[string]interface{}) (interface{}, error) {
+	group, profile, err := r.lookupGroupMembership(arguments)
+	if err != nil {
+		return nil, err
+	}
+	if !r.store.members[group.id][profile.id] {
+		return nil, notFound("Profile %s is not a member of group profile %s", profile.id, group.id)
+	}
+	delete(r.store.members[group.id], profile.id)
+	return groupMembershipNode(group, profile), nil
+}
+
+// lookupGroupMembership resolves the group profile and profile of a membership input. A profile can
+// only be a member of a group profile of its own directory.
+func (r *root) lookupGroupMembership(arguments map[string]interface{}) (*resource, *resource, error) {
+	input, err := inputArgument(arguments)
+	if err != nil {
+		return nil, nil, err
+	}
+	resolved := map[string]*resource{}
+	for _, property := range []string{"groupProfile", "profile"} {
+		aka, err := requiredString(input, property)
+		if err != nil {
+			return nil, nil, err
+		}
+		if resolved[property], err = r.store.lookupForUpdate(r.caller, aka); err != nil {
+			return nil, nil, err
+		}
+	}
+	group, profile := resolved["groupProfile"], resolved["profile"]
+	if group.typeUri != groupProfileType {
+		return nil, nil, validationFailed("%s is not a group profile", input["groupProfile"])
+	}
+	if profile.typeUri != profileType {
+		return nil, nil, validationFailed("%s is not a profile", input["profile"])
+	}
+	if group.parentId != profile.parentId {
+		return nil, nil, validationFailed("profile %s is not in the directory of group profile %s", profile.id, group.id)
+	}
+	return group, profile, nil
+}
+
+func (r *root) create(typeUri string, input, data map[string]interface{}) (interface{}, error) {
+	parentAka, err := requiredString(input, "parent")
+	if err != nil {
+		return nil, err
+	}
+	parent, err := r.store.lookupForUpdate(r.caller, parentAka)
+	if err != nil {
+		return nil, err
+	}
+	if resourceType := r.store.find(typeUri); resourceType == nil || resourceType.typeUri != resourceTypeType {
+		return nil, validationFailed("resource type %s is not installed", typeUri)
+	}
+	custom, err := objectArgument(input, "metadata")
+	if err != nil {
+		return nil, err
+	}
+	tags, err := objectArgument(input, "tags")
+	if err != nil {
+		return nil, err
+	}
+	akas, err := stringList(input, "akas")
+	if err != nil {
+		return nil, err
+	}
+	if aka, ok := r.store.akaAvailable(akas, ""); !ok {
+		return nil, conflict("a resource with aka %s already exists", aka)
+	}
+	res := r.store.insertResource(parent, typeUri, copyValue(data).(map[string]interface{}), custom, tags, akas)
+	return r.node(res), nil
+}
+
+func (r *root) updateResource(arguments map[string]interface{}) (interface{}, error) {
+	input, err := inputArgument(arguments)
+	if err != nil {
+		return nil, err
+	}
+	data, err := objectArgument(input, "data")
+	if err != nil {
+		return nil, err
+	}
+	return r.update(input, data, false)
+}
+
+// putResource replaces the data, metadata and tags of a resource outright rather than merging
+func (r *root) putResource(arguments map[string]interface{}) (interface{}, error) {
+	input, err := inputArgument(arguments)
+	if err != nil {
+		return nil, err
+	}
+	data, err := objectArgument(input, "data")
+	if err != nil {
+		return nil, err
+	}
+	return r.update(input, data, true)
+}
+
+func (r *root) typedUpdate(arguments map[string]interface{}) (interface{}, error) {
+	input, err := inputArgument(arguments)
+	if err != nil {
+		return nil, err
+	}
+	return r.update(input, typedData(input), false)
+}
+
+func (r *root) update(input, data map[string]interface{}, replace bool) (interface{}, error) {
+	id, err := requiredString(input, "id")
+	if err != nil {
+		return nil, err
+	}
+	res, err := r.store.lookupForUpdate(r.caller, id)
+	if err != nil {
+		return nil, err
+	}
+	custom, err := objectArgument(input, "metadata")
+	if err != nil {
+		return nil, err
+	}
+	tags, err := objectArgument(input, "tags")
+	if err != nil {
+		return nil, err
+	}
+	var parent *resource
+	if parentAka := stringArgument(input, "parent"); parentAka != "" {
+		if parent, err = r.store.lookupForUpdate(r.caller, parentAka); err != nil {
+			return nil, err
+		}
+		if r.store.isWithin(parent, res.id) {
+			return nil, validationFailed("a resource cannot be moved below itself")
+		}
+	}
+	_, akasSet := input["akas"]
+	akas, err := stringList(input, "akas")
+	if err != nil {
+		return nil, err
+	}
+	if aka, ok := r.store.akaAvailable(akas, res.id); !ok {
+		return nil, conflict("a resource with aka %s already exists", aka)
+	}
+
+	if replace {
+		res.data = copyValue(data).(map[string]interface{})
+		if res.data == nil {
+			res.data = map[string]interface{}{}
+		}
+		res.custom = custom
+		res.tags = tags
+	} else {
+		if data != nil {
+			res.data = mergeData(res.data, data)
+		}
+		if custom != nil {
+			res.custom = mergeData(res.custom, custom)
+		}
+		if tags != nil {
+			res.tags = mergeData(res.tags, tags)
+		}
+	}
+	if akasSet {
+		res.akas = akas
+	}
+	if parent != nil {
+		res.parentId = parent.id
+	}
+	res.touch(r.store)
+	return r.node(res), nil
+}
+
+func (r *root) deleteResource(arguments map[string]interface{}) (interface{}, error) {
+	id := stringArgument(arguments, "id")
+	if input, ok := arguments["input"].(map[string]interface{}); ok {
+		id = stringArgument(input, "id")
+	}
+	if id == "" {
+		return nil, validationFailed("id is required")
+	}
+	res, err := r.store.lookupForUpdate(r.caller, id)
+	if err != nil {
+		return nil, err
+	}
+	if res.id == r.store.rootId {
+		return nil, validationFailed("the Turbot root cannot be deleted")
+	}
+	// answer with the resource as it was
+	document := r.store.document(res)
+	r.store.deleteResource(res)
+	return deletedNode{document: document}, nil
+}
+
+// deletedNode answers the selection of a delete mutation from a snapshot of the deleted resource
+type deletedNode struct {
+	document map[string]interface{}
+}
+
+func (n deletedNode) resolve(name string, arguments map[string]interface{}) (interface{}, error) {
+	switch name {
+	case "get":
+		path, _ := arguments["path"].(string)
+		return getPath(n.document, path), nil
+	case "turbot":
+		return fields(n.document["turbot"].(map[string]interface{})), nil
+	}
+	return nil, validationFailed("Cannot query field %q on a deleted resource", name)
+}
+
+// policy packs
+
+func (r *root) policyPack(arguments map[string]interface{}) (interface{}, error) {
+	id, err := requiredString(arguments, "id")
+	if err != nil {
+		return nil, err
+	}
+	// a policy pack is readable by any identity, whatever its scope - see readPolicyPackIdentityQuery
+	pack := r.store.find(id)
+	if pack == nil || pack.typeUri != smartFolderType {
+		return nil, notFound("Policy pack not found: %s", id)
+	}
+	return r.node(pack), nil
+}
+
+func (r *root) attachmentInput(arguments map[string]interface{}) (*resource, []*resource, error) {
+	input, err := inputArgument(arguments)
+	if err != nil {
+		return nil, nil, err
+	}
+	targetAka, err := requiredString(input, "resource")
+	if err != nil {
+		return nil, nil, err
+	}
+	target, err := r.store.lookupForUpdate(r.caller, targetAka)
+	if err != nil {
+		return nil, nil, err
+	}
+	packAkas, err := stringList(input, "smartFolders")
+	if err != nil {
+		return nil, nil, err
+	}
+	var packs []*resource
+	for _, aka := range packAkas {
+		pack := r.store.find(aka)
+		if pack == nil || pack.typeUri != smartFolderType {
+			return nil, nil, notFound("Policy pack not found: %s", aka)
+		}
+		packs = append(packs, pack)
+	}
+	return target, packs, nil
+}
+
+// attachSmartFolders and detachSmartFolders answer with the target resource, as it stands afterwards
+func (r *root) attachSmartFolders(arguments map[string]interface{}) (interface{}, error) {
+	target, packs, err := r.attachmentInput(arguments)
+	if err != nil {
+		return nil, err
+	}
+	for _, pack := range packs {
+		if !containsString(target.attached, pack.id) {
+			target.attached = append(target.attached, pack.id)
+		}
+	}
+	return r.node(target), nil
+}
+
+func (r *root) detachSmartFolders(arguments map[string]interface{}) (interface{}, error) {
+	target, packs, err := r.attachmentInput(arguments)
+	if err != nil {
+		return nil, err
+	}
+	for _, pack := range packs {
+		target.attached = removeString(target.attached, pack.id)
+	}
+	return r.node(target), nil
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// mods
+
+func (r *root) installMod(arguments map[string]interface{}) (interface{}, error) {
+	input, err := inputArgument(arguments)
+	if err != nil {
+		return nil, err
+	}
+	org, err := requiredString(input, "org")
+	if err != nil {
+		return nil, err
+	}
+	mod, err := requiredString(input, "mod")
+	if err != nil {
+		return nil, err
+	}
+	version, err := requiredString(input, "version")
+	if err != nil {
+		return nil, err
+	}
+	version, err = r.store.resolveModVersion(org+"/"+mod, version)
+	if err != nil {
+		return nil, err
+	}
+	parent, err := r.store.lookupForUpdate(r.caller, stringArgument(input, "parent"))
+	if err != nil {
+		return nil, err
+	}
+	uri := fmt.Sprintf("tmod:@%s/%s", org, mod)
+	installed := r.store.find(uri)
+	if installed == nil {
+		installed = r.store.insertResource(parent, modType, map[string]interface{}{}, nil, nil, []string{uri})
+		installed.public = true
+	}
+	// installing an installed mod upgrades - or downgrades - it in place. Installation completes
+	// immediately: the build the provider polls for is already the mod's build.
+	installed.touch(r.store)
+	installed.data["version"] = version
+	installed.data["build"] = installed.versionId
+	return fields{
+		"turbot": fields(r.store.metadata(installed)),
+		"build":  installed.versionId,
+	}, nil
+}
+
+// resolveModVersion picks the latest registry version of a mod that satisfies constraint - an exact
+// version, or a range such as ">=5.0.0" or "5.0.*", as installMod accepts
+func (s *store) resolveModVersion(name, constraint string) (string, error) {
+	c, err := semver.NewConstraint(constraint)
+	if err != nil {
+		return "", validationFailed("invalid mod version %q: %s", constraint, err)
+	}
+	var latest *semver.Version
+	for _, available := range s.modVersions[name] {
+		v, err := semver.NewVersion(available.version)
+		if err == nil && c.Check(v) && (latest == nil || v.GreaterThan(latest)) {
+			latest = v
+		}
+	}
+	if latest == nil {
+		return "", notFound("no version of mod %s satisfying %s is in the registry", name, constraint)
+	}
+	return latest.Original(), nil
+}
+
+func (r *root) uninstallMod(arguments map[string]interface{}) (interface{}, error) {
+	input, err := inputArgument(arguments)
+	if err != nil {
+		return nil, err
+	}
+	id, err := requiredString(input, "id")
+	if err != nil {
+		return nil, err
+	}
+	mod, err := r.store.lookupForUpdate(r.caller, id)
+	if err != nil {
+		return nil, err
+	}
+	if mod.typeUri != modType {
+		return nil, validationFailed("resource %s is not a mod", id)
+	}
+	r.store.deleteResource(mod)
+	return fields{"success": true}, nil
+}
+
+func (r *root) modVersionList(arguments map[string]interface{}) (interface{}, error) {
+	org, err := requiredString(arguments, "orgName")
+	if err != nil {
+		return nil, err
+	}
+	mod, err := requiredString(arguments, "modName")
+	if err != nil {
+		return nil, err
+	}
+	var items []object
+	for _, version := range r.store.modVersions[org+"/"+mod] {
+		items = append(items, fields{"version": version.version, "status": version.status})
+	}
+	return listNode(items), nil
+}
+
+// policies
+
+func (r *root) policySetting(arguments map[string]interface{}) (interface{}, error) {
+	id, err := requiredString(arguments, "id")
+	if err != nil {
+		return nil, err
+	}
+	setting, err := r.lookupSetting(id)
+	if err != nil {
+		return nil, err
+	}
+	return r.store.policySettingNode(setting), nil
+}
+
+func (r *root) lookupSetting(id string) (*policySetting, error) {
+	setting, ok := r.store.settings[id]
+	if !ok || !r.store.isWithin(r.store.resources[setting.resourceId], r.caller.scope) {
+		return nil, notFound("Policy setting not found: %s", id)
+	}
+	return setting, nil
+}
+
+func (r *root) policySettingList(arguments map[string]interface{}) (interface{}, error) {
+	f, err := parseFilter(arguments["filter"])
+	if err != nil {
+		return nil, err
+	}
+	var scope *resource
+	level := "sub"
+	if id, ok := f.value("resource"); ok {
+		if scope, err = r.store.lookup(r.caller, id); err != nil {
+			return nil, err
+		}
+		if level, err = f.level("self"); err != nil {
+			return nil, err
+		}
+	}
+	typeUri, filterType := f.value("policyType")
+	if filterType {
+		if policyType := r.store.find(typeUri); policyType != nil && len(policyType.akas) > 0 {
+			typeUri = policyType.akas[0]
+		}
+	}
+	var settings []*policySetting
+	for _, setting := range r.store.settings {
+		res := r.store.resources[setting.resourceId]
+		if !r.store.isWithin(res, r.caller.scope) {
+			continue
+		}
+		if scope != nil && !r.store.matchesLevel(res, scope, level) {
+			continue
+		}
+		if filterType && setting.typeUri != typeUri {
+			continue
+		}
+		settings = append(settings, setting)
+	}
+	sort.Slice(settings, func(i, j int) bool { return idLess(settings[i].id, settings[j].id) })
+	var items []object
+	for _, setting := range settings {
+		items = append(items, r.store.policySettingNode(setting))
+	}
+	return pageNode(items, arguments, f)
+}
+
+// lookupPolicyType resolves a policy type by uri or id
+func (r *root) lookupPolicyType(uri string) (*resource, error) {
+	policyType := r.store.find(uri)
+	if policyType == nil || policyType.typeUri != policyTypeType {
+		return nil, notFound("policy type %s not found", uri)
+	}
+	return policyType, nil
+}
+
+func (r *root) createPolicySetting(arguments map[string]interface{}) (interface{}, error) {
+	input, err := inputArgument(arguments)
+	if err != nil {
+		return nil, err
+	}
+	typeUri, err := requiredString(input, "type")
+	if err != nil {
+		return nil, err
+	}
+	resourceAka, err := requiredString(input, "resource")
+	if err != nil {
+		return nil, err
+	}
+	res, err := r.store.lookupForUpdate(r.caller, resourceAka)
+	if err != nil {
+		return nil, err
+	}
+	policyType, err := r.lookupPolicyType(typeUri)
+	if err != nil {
+		return nil, err
+	}
+	for _, existing := range r.store.settings {
+		if existing.resourceId == res.id && existing.typeUri == policyType.akas[0] {
+			return nil, validationFailed("a setting for policy type %s already exists on resource %s", policyType.akas[0], res.id)
+		}
+	}
+	setting := &policySetting{
+		resourceId: res.id,
+		typeUri:    policyType.akas[0],
+		precedence: precedenceRequired,
+	}
+	if err := r.applySettingInput(setting, policyType, input); err != nil {
+		return nil, err
+	}
+	setting.id = r.store.newId()
+	r.store.settings[setting.id] = setting
+	return r.store.policySettingNode(setting), nil
+}
+
+func (r *root) updatePolicySetting(arguments map[string]interface{}) (interface{}, error) {
+	input, err := inputArgument(arguments)
+	if err != nil {
+		return nil, err
+	}
+	id, err := requiredString(input, "id")
+	if err != nil {
+		return nil, err
+	}
+	setting, err := r.lookupSetting(id)
+	if err != nil {
+		return nil, err
+	}
+	policyType, err := r.lookupPolicyType(setting.typeUri)
+	if err != nil {
+		return nil, err
+	}
+	// validate into a copy, so a rejected update leaves the setting as it was
+	updated := *setting
+	if err := r.applySettingInput(&updated, policyType, input); err != nil {
+		return nil, err
+	}
+	*setting = updated
+	return r.store.policySettingNode(setting), nil
+}
+
+// applySettingInput applies the value, template, precedence, note and validity of a create or update
+// input to setting
+func (r *root) applySettingInput(setting *policySetting, policyType *resource, input map[string]interface{}) error {
+	_, hasValue := input["value"]
+	_, hasValueSource := input["valueSource"]
+	template, hasTemplate := input["template"]
+	if hasTemplate && template != nil {
+		if hasValue && input["value"] != nil || hasValueSource && input["valueSource"] != nil {
+			return validationFailed("a setting has either a value or a template, not both")
+		}
+		setting.template = template
+		setting.templateInput = input["templateInput"]
+		setting.value, setting.valueSource = nil, ""
+	} else if hasValue || hasValueSource {
+		value, valueSource, err := settingValue(policyType, input)
+		if err != nil {
+			return err
+		}
+		if value == nil {
+			return validationFailed("a setting must have a value or a template")
+		}
+		setting.value, setting.valueSource = value, valueSource
+		setting.template, setting.templateInput = nil, nil
+	} else if setting.value == nil && setting.template == nil {
+		return validationFailed("a setting must have a value or a template")
+	}
+	if precedence, ok := input["precedence"].(string); ok && precedence != "" {
+		if !validPrecedence(precedence) {
+			return validationFailed("precedence should be one of REQUIRED, RECOMMENDED")
+		}
+		setting.precedence = strings.ToUpper(precedence)
+	}
+	if note, ok := input["note"]; ok {
+		setting.note = note
+	}
+	if validFrom, ok := input["validFromTimestamp"]; ok {
+		setting.validFrom = validFrom
+	}
+	if validTo, ok := input["validToTimestamp"]; ok {
+		setting.validTo = validTo
+	}
+	return nil
+}
+
+func (r *root) deletePolicySetting(arguments map[string]interface{}) (interface{}, error) {
+	input, err := inputArgument(arguments)
+	if err != nil {
+		return nil, err
+	}
+	id, err := requiredString(input, "id")
+	if err != nil {
+		return nil, err
+	}
+	setting, err := r.lookupSetting(id)
+	if err != nil {
+		return nil, err
+	}
+	delete(r.store.settings, setting.id)
+	return r.store.policySettingNode(setting), nil
+}
+
+func (r *root) policyValue(arguments map[string]interface{}) (interface{}, error) {
+	uri, err := requiredString(arguments, "uri")
+	if err != nil {
+		return nil, err
+	}
+	resourceId, err := requiredString(arguments, "resourceId")
+	if err != nil {
+		return nil, err
+	}
+	res, err := r.store.lookup(r.caller, resourceId)
+	if err != nil {
+		return nil, err
+	}
+	policyType, err := r.lookupPolicyType(uri)
+	if err != nil {
+		return nil, err
+	}
+	value := copyValue(policyType.data["default"])
+	precedence, reason := precedenceRequired, "default value"
+	var setting interface{}
+	if effective := r.store.effectiveSetting(policyType.akas[0], res); effective != nil {
+		value, precedence, reason = copyValue(effective.value), effective.precedence, "set by policy setting "+effective.id
+		setting = fields{
+			"valueSource": effective.valueSource,
+			"turbot":      fields{"id": effective.id},
+		}
+	}
+	return fields{
+		"value":       value,
+		"secretValue": value,
+		"precedence":  valuePrecedence(precedence),
+		"state":       "ok",
+		"reason":      reason,
+		"details":     nil,
+		"setting":     setting,
+		"turbot": fields{
+			// a policy value is identified by its type and resource
+			"id": policyType.id + ":" + res.id,
+		},
+	}, nil
+}
+
+// valuePrecedence is the precedence of a setting as a policy value reports it - in the older
+// must/should vocabulary rather than REQUIRED/RECOMMENDED
+func valuePrecedence(precedence string) string {
+	if precedence == precedenceRecommended {
+		return "should"
+	}
+	return "must"
+}
+
+func (r *root) policyTypes(arguments map[string]interface{}) (interface{}, error) {
+	f, err := parseFilter(arguments["filter"])
+	if err != nil {
+		return nil, err
+	}
+	var types []*resource
+	for _, res := range r.store.resources {
+		if res.typeUri == policyTypeType {
+			types = append(types, res)
+		}
+	}
+	sortResources(types)
+	if uri, ok := f.value("policyType"); ok {
+		// level:self is the type itself; otherwise the filter also matches the types below it
+		level, err := f.level("sub")
+		if err != nil {
+			return nil, err
+		}
+		policyType := r.store.find(uri)
+		types = filterResources(types, func(res *resource) bool {
+			return policyType != nil && r.store.matchesLevel(res, policyType, level)
+		})
+	}
+	types = filterResources(types, func(res *resource) bool {
+		return f.matchesText(res.title())
+	})
+	var items []object
+	for _, policyType := range types {
+		items = append(items, &definitionNode{store: r.store, r: policyType})
+	}
+	return listNode(items), nil
+}
+
+// grants
+
+func (r *root) grant(arguments map[string]interface{}) (interface{}, error) {
+	id, err := requiredString(arguments, "id")
+	if err != nil {
+		return nil, err
+	}
+	g, err := r.lookupGrant(id)
+	if err != nil {
+		return nil, err
+	}
+	return r.store.grantNode(g), nil
+}
+
+func (r *root) lookupGrant(id string) (*grant, error) {
+	g, ok := r.store.grants[id]
+	if !ok || !r.store.isWithin(r.store.resources[g.resourceId], r.caller.scope) {
+		return nil, notFound("Grant not found: %s", id)
+	}
+	return g, nil
+}
+
+// grants lists the grants on the resources a `resource` filter selects - every grant the caller can
+// see when there is none - optionally only those of an `identity`
+func (r *root) grants(arguments map[string]interface{}) (interface{}, error) {
+	f, err := parseFilter(arguments["filter"])
+	if err != nil {
+		return nil, err
+	}
+	var scope *resource
+	level := "sub"
+	if id, ok := f.value("resource"); ok {
+		if scope, err = r.store.lookup(r.caller, id); err != nil {
+			return nil, err
+		}
+		if level, err = f.level("self"); err != nil {
+			return nil, err
+		}
+	}
+	profileId, filterIdentity := f.value("identity")
+	if filterIdentity {
+		if profile := r.store.find(profileId); profile != nil {
+			profileId = profile.id
+		}
+	}
+	var grants []*grant
+	for _, g := range r.store.grants {
+		res := r.store.resources[g.resourceId]
+		if !r.store.isWithin(res, r.caller.scope) {
+			continue
+		}
+		if scope != nil && !r.store.matchesLevel(res, scope, level) {
+			continue
+		}
+		if filterIdentity && g.profileId != profileId {
+			continue
+		}
+		grants = append(grants, g)
+	}
+	sort.Slice(grants, func(i, j int) bool { return idLess(grants[i].id, grants[j].id) })
+	var items []object
+	for _, g := range grants {
+		items = append(items, r.store.grantNode(g))
+	}
+	return pageNode(items, arguments, f)
+}
+
+func (r *root) createGrant(arguments map[string]interface{}) (interface{}, error) {
+	input, err := inputArgument(arguments)
+	if err != nil {
+		return nil, err
+	}
+	resolved := map[string]*resource{}
+	for _, property := range []string{"resource", "identity", "type", "level"} {
+		aka, err := requiredString(input, property)
+		if err != nil {
+			return nil, err
+		}
+		if resolved[property], err = r.store.lookup(r.caller, aka); err != nil {
+			return nil, err
+		}
+	}
+	if !r.store.isWithin(resolved["resource"], r.caller.scope) {
+		return nil, permissionDenied("identity cannot grant on resource %s", resolved["resource"].id)
+	}
+	if resolved["type"].typeUri != permissionTypeType {
+		return nil, validationFailed("%s is not a permission type", input["type"])
+	}
+	if resolved["level"].typeUri != permissionLevelType {
+		return nil, validationFailed("%s is not a permission level", input["level"])
+	}
+	g := &grant{
+		id:                r.store.newId(),
+		profileId:         resolved["identity"].id,
+		resourceId:        resolved["resource"].id,
+		permissionTypeId:  resolved["type"].id,
+		permissionLevelId: resolved["level"].id,
+	}
+	r.store.grants[g.id] = g
+	return r.store.grantNode(g), nil
+}
+
+func (r *root) deleteGrant(arguments map[string]interface{}) (interface{}, error) {
+	input, err := inputArgument(arguments)
+	if err != nil {
+		return nil, err
+	}
+	id, err := requiredString(input, "id")
+	if err != nil {
+		return nil, err
+	}
+	g, err := r.lookupGrant(id)
+	if err != nil {
+		return nil, err
+	}
+	r.store.deleteGrant(g)
+	return r.store.grantNode(g), nil
+}
+
+func (r *root) activeGrant(arguments map[string]interface{}) (interface{}, error) {
+	id, err := requiredString(arguments, "id")
+	if err != nil {
+		return nil, err
+	}
+	active, err := r.lookupActiveGrant(id)
+	if err != nil {
+		return nil, err
+	}
+	return r.store.activeGrantNode(active), nil
+}
+
+func (r *root) activeGrants(arguments map[string]interface{}) (interface{}, error) {
+	f, err := parseFilter(arguments["filter"])
+	if err != nil {
+		return nil, err
+	}
+	var scope *resource
+	level := "sub"
+	if id, ok := f.value("resource"); ok {
+		if scope, err = r.store.lookup(r.caller, id); err != nil {
+			return nil, err
+		}
+		if level, err = f.level("self"); err != nil {
+			return nil, err
+		}
+	}
+	profileId, filterIdentity := f.value("identity")
+	if filterIdentity {
+		if profile := r.store.find(profileId); profile != nil {
+			profileId = profile.id
+		}
+	}
+	var activeGrants []*activeGrant
+	for _, active := range r.store.activeGrants {
+		res := r.store.resources[active.resourceId]
+		if !r.store.isWithin(res, r.caller.scope) {
+			continue
+		}
+		if scope != nil && !r.store.matchesLevel(res, scope, level) {
+			continue
+		}
+		if filterIdentity && r.store.grants[active.grantId].profileId != profileId {
+			continue
+		}
+		activeGrants = append(activeGrants, active)
+	}
+	sort.Slice(activeGrants, func(i, j int) bool { return idLess(activeGrants[i].id, activeGrants[j].id) })
+	var items []object
+	for _, active := range activeGrants {
+		items = append(items, r.store.activeGrantNode(active))
+	}
+	return pageNode(items, arguments, f)
+}
+
+func (r *root) lookupActiveGrant(id string) (*activeGrant, error) {
+	active, ok := r.store.activeGrants[id]
+	if !ok || !r.store.isWithin(r.store.resources[active.resourceId], r.caller.scope) {
+		return nil, notFound("Active grant not found: %s", id)
+	}
+	return active, nil
+}
+
+func (r *root) activateGrant(arguments map[string]interface{}) (interface{}, error) {
+	input, err := inputArgument(arguments)
+	if err != nil {
+		return nil, err
+	}
+	grantId, err := requiredString(input, "grant")
+	if err != nil {
+		return nil, err
+	}
+	g, err := r.lookupGrant(grantId)
+	if err != nil {
+		return nil, err
+	}
+	resourceAka, err := requiredString(input, "resource")
+	if err != nil {
+		return nil, err
+	}
+	res, err := r.store.lookupForUpdate(r.caller, resourceAka)
+	if err != nil {
+		return nil, err
+	}
+	// a grant can only be activated where it applies: on its resource or below
+	if !r.store.isWithin(res, g.resourceId) {
+		return nil, validationFailed("grant %s does not apply to resource %s", g.id, res.id)
+	}
+	active := &activeGrant{id: r.store.newId(), grantId: g.id, resourceId: res.id}
+	r.store.activeGrants[active.id] = active
+	return r.store.activeGrantNode(active), nil
+}
+
+func (r *root) deactivateGrant(arguments map[string]interface{}) (interface{}, error) {
+	input, err := inputArgument(arguments)
+	if err != nil {
+		return nil, err
+	}
+	id := stringArgument(input, "activation")
+	if id == "" {
+		id = stringArgument(input, "id")
+	}
+	active, err := r.lookupActiveGrant(id)
+	if err != nil {
+		return nil, err
+	}
+	delete(r.store.activeGrants, active.id)
+	return r.store.activeGrantNode(active), nil
+}
+
+// watches
+
+func (r *root) watch(arguments map[string]interface{}) (interface{}, error) {
+	id, err := requiredString(arguments, "id")
+	if err != nil {
+		return nil, err
+	}
+	w, err := r.lookupWatch(id)
+	if err != nil {
+		return nil, err
+	}
+	return watchNode(w), nil
+}
+
+func (r *root) lookupWatch(id string) (*watch, error) {
+	w, ok := r.store.watches[id]
+	if !ok || !r.store.isWithin(r.store.resources[w.resourceId], r.caller.scope) {
+		return nil, notFound("Watch not found: %s", id)
+	}
+	return w, nil
+}
+
+func (r *root) createWatch(arguments map[string]interface{}) (interface{}, error) {
+	input, err := inputArgument(arguments)
+	if err != nil {
+		return nil, err
+	}
+	resourceAka, err := requiredString(input, "resource")
+	if err != nil {
+		return nil, err
+	}
+	res, err := r.store.lookupForUpdate(r.caller, resourceAka)
+	if err != nil {
+		return nil, err
+	}
+	w := &watch{id: r.store.newId(), resourceId: res.id}
+	if err := r.applyWatchInput(w, input); err != nil {
+		return nil, err
+	}
+	r.store.watches[w.id] = w
+	return watchNode(w), nil
+}
+
+func (r *root) updateWatch(arguments map[string]interface{}) (interface{}, error) {
+	input, err := inputArgument(arguments)
+	if err != nil {
+		return nil, err
+	}
+	id, err := requiredString(input, "id")
+	if err != nil {
+		return nil, err
+	}
+	w, err := r.lookupWatch(id)
+	if err != nil {
+		return nil, err
+	}
+	updated := *w
+	if err := r.applyWatchInput(&updated, input); err != nil {
+		return nil, err
+	}
+	*w = updated
+	return watchNode(w), nil
+}
+
+func (r *root) applyWatchInput(w *watch, input map[string]interface{}) error {
+	if action, ok := input["action"].(string); ok {
+		w.action = action
+	}
+	if w.action == "" {
+		return validationFailed("action is required")
+	}
+	if filters, ok := input["filters"].([]interface{}); ok {
+		w.filters = filters
+	}
+	if favorite, ok := input["favorite"]; ok {
+		w.favoriteId = favorite
+	}
+	w.description = fmt.Sprintf("Watch %s on resource %s", w.action, w.resourceId)
+	return nil
+}
+
+func (r *root) deleteWatch(arguments map[string]interface{}) (interface{}, error) {
+	input, err := inputArgument(arguments)
+	if err != nil {
+		return nil, err
+	}
+	id, err := requiredString(input, "id")
+	if err != nil {
+		return nil, err
+	}
+	w, err := r.lookupWatch(id)
+	if err != nil {
+		return nil, err
+	}
+	delete(r.store.watches, w.id)
+	return watchNode(w), nil
+}
+
+// controls
+
+func (r *root) control(arguments map[string]interface{}) (interface{}, error) {
+	c, err := r.lookupControl(arguments)
+	if err != nil {
+		return nil, err
+	}
+	return r.store.controlNode(c), nil
+}
+
+func (r *root) controlList(arguments map[string]interface{}) (interface{}, error) {
+	f, err := parseFilter(arguments["filter"])
+	if err != nil {
+		return nil, err
+	}
+	var scope *resource
+	level := "sub"
+	if id, ok := f.value("resource"); ok {
+		if scope, err = r.store.lookup(r.caller, id); err != nil {
+			return nil, err
+		}
+		if level, err = f.level("self"); err != nil {
+			return nil, err
+		}
+	}
+	typeUri, filterType := f.value("controlType")
+	if filterType {
+		if controlType := r.store.find(typeUri); controlType != nil && len(controlType.akas) > 0 {
+			typeUri = controlType.akas[0]
+		}
+	}
+	// `state:alarm,error` matches either state
+	states := map[string]bool{}
+	for _, value := range f.terms["state"] {
+		for _, state := range strings.Split(value, ",") {
+			states[state] = true
+		}
+	}
+	var controls []*control
+	for _, c := range r.store.controls {
+		res := r.store.resources[c.resourceId]
+		if !r.store.isWithin(res, r.caller.scope) {
+			continue
+		}
+		if scope != nil && !r.store.matchesLevel(res, scope, level) {
+			continue
+		}
+		if filterType && c.typeUri != typeUri {
+			continue
+		}
+		if len(states) > 0 && !states[c.state] {
+			continue
+		}
+		controls = append(controls, c)
+	}
+	sort.Slice(controls, func(i, j int) bool { return idLess(controls[i].id, controls[j].id) })
+	var items []object
+	for _, c := range controls {
+		items = append(items, r.store.controlNode(c))
+	}
+	return pageNode(items, arguments, f)
+}
+
+// lookupControl resolves a control by id, or by its control type and resource
+func (r *root) lookupControl(arguments map[string]interface{}) (*control, error) {
+	if id := stringArgument(arguments, "id"); id != "" {
+		c, ok := r.store.controls[id]
+		if !ok || !r.store.isWithin(r.store.resources[c.resourceId], r.caller.scope) {
+			return nil, notFound("Control not found: %s", id)
+		}
+		return c, nil
+	}
+	uri := stringArgument(arguments, "uri")
+	if uri == "" {
+		uri = stringArgument(arguments, "controlType")
+	}
+	resourceAka := stringArgument(arguments, "resourceId")
+	if resourceAka == "" {
+		resourceAka = stringArgument(arguments, "resource")
+	}
+	if uri == "" || resourceAka == "" {
+		return nil, validationFailed("either id, or a control type and resource, is required")
+	}
+	res, err := r.store.lookup(r.caller, resourceAka)
+	if err != nil {
+		return nil, err
+	}
+	controlType := r.store.find(uri)
+	for _, c := range r.store.controls {
+		if c.resourceId == res.id && (c.typeUri == uri || controlType != nil && c.typeUri == controlType.akas[0]) {
+			return c, nil
+		}
+	}
+	return nil, notFound("Control not found: %s on %s", uri, resourceAka)
+}
+
+func (r *root) muteControl(arguments map[string]interface{}) (interface{}, error) {
+	input, err := inputArgument(arguments)
+	if err != nil {
+		return nil, err
+	}
+	c, err := r.lookupControl(input)
+	if err != nil {
+		return nil, err
+	}
+	mute := map[string]interface{}{}
+	for _, key := range []string{"note", "toTimestamp", "untilStates"} {
+		if value, ok := input[key]; ok && value != nil {
+			mute[key] = copyValue(value)
+		}
+	}
+	c.mute = mute
+	return r.store.controlNode(c), nil
+}
+
+func (r *root) unmuteControl(arguments map[string]interface{}) (interface{}, error) {
+	input, err := inputArgument(arguments)
+	if err != nil {
+		return nil, err
+	}
+	c, err := r.lookupControl(input)
+	if err != nil {
+		return nil, err
+	}
+	c.mute = nil
+	return r.store.controlNode(c), nil
+}
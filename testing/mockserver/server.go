@@ -0,0 +1,153 @@
+// Package mockserver is an in-memory stand-in for the Guardrails GraphQL API, so the provider's
+// acceptance tests can run without a live workspace.
+//
+// It answers the operations in apiClient/queries.go against a small seeded workspace - see
+// fixtures.go - and enforces the API behaviour the provider relies on: resources addressed by id or
+// aka, merge semantics for updates, policy values checked against the policy type schema, typed errors
+// carrying an `extensions.code`, and identities that can only see and change what is within their
+// scope. It is not a general GraphQL server: anything outside that surface is rejected, so a provider
+// change that depends on more of the API fails against the mock rather than passing unnoticed.
+package mockserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"sync/atomic"
+)
+
+// Server is a running mock workspace. AccessKey and SecretKey are the credentials of an identity
+// scoped to the Turbot root, which can see and change everything - those of the fixture profile,
+// which holds Turbot/Owner on the root.
+type Server struct {
+	AccessKey string
+	SecretKey string
+
+	store      *store
+	server     *httptest.Server
+	requestIds int64
+}
+
+// New starts a mock workspace seeded with the fixture workspace. Close it when done.
+func New() *Server {
+	s := &Server{
+		AccessKey: "mock-access-key",
+		SecretKey: "mock-secret-key",
+		store:     newStore(),
+	}
+	seed(s.store)
+	s.store.identities = append(s.store.identities, &identity{accessKey: s.AccessKey, secretKey: s.SecretKey, scope: s.store.rootId, profileId: fixtureProfileId})
+	s.server = httptest.NewServer(s)
+	return s
+}
+
+// Workspace is the workspace url to configure the provider with
+func (s *Server) Workspace() string {
+	return s.server.URL
+}
+
+// URL is the url of the GraphQL endpoint itself
+func (s *Server) URL() string {
+	return s.server.URL + "/api/latest/graphql"
+}
+
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// AddIdentity registers another access key pair, which can only see and change the resource scope -
+// an id or aka - and what is below it.
+func (s *Server) AddIdentity(accessKey, secretKey, scope string) {
+	s.store.mutex.Lock()
+	defer s.store.mutex.Unlock()
+	scopeId := scope
+	if r := s.store.find(scope); r != nil {
+		scopeId = r.id
+	}
+	s.store.identities = append(s.store.identities, &identity{accessKey: accessKey, secretKey: secretKey, scope: scopeId})
+}
+
+// AddResourceType installs a resource type definition, so resources of that type can be created
+func (s *Server) AddResourceType(uri string) {
+	s.store.mutex.Lock()
+	defer s.store.mutex.Unlock()
+	if s.store.find(uri) != nil {
+		return
+	}
+	mod := s.store.find(modUri(uri))
+	if mod == nil {
+		mod = s.store.insertResource(s.store.resources[s.store.rootId], modType, map[string]interface{}{"version": "5.0.0"}, nil, nil, []string{modUri(uri)})
+		mod.public = true
+	}
+	definition := s.store.insertResource(mod, resourceTypeType, map[string]interface{}{"title": definitionTitle(uri)}, nil, nil, []string{uri})
+	definition.public = true
+}
+
+var endpointPath = regexp.MustCompile(`^/api/(latest|v[0-9]+)/graphql$`)
+
+type request struct {
+	Query         string                 `json:"query"`
+	Variables     map[string]interface{} `json:"variables"`
+	OperationName string                 `json:"operationName"`
+}
+
+type response struct {
+	Data   interface{}     `json:"data"`
+	Errors []*graphqlError `json:"errors,omitempty"`
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("X-Request-Id", "mock-"+strconv.FormatInt(atomic.AddInt64(&s.requestIds, 1), 10))
+	if !endpointPath.MatchString(r.URL.Path) {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	accessKey, secretKey, _ := r.BasicAuth()
+	s.store.mutex.Lock()
+	defer s.store.mutex.Unlock()
+	caller := s.store.authenticate(accessKey, secretKey)
+	if caller == nil {
+		writeResponse(w, http.StatusUnauthorized, &response{Errors: []*graphqlError{{
+			Message:    "Unauthorized: invalid access key or secret key",
+			Extensions: map[string]interface{}{"code": "UNAUTHENTICATED"},
+		}}})
+		return
+	}
+
+	var body request
+	decoder := json.NewDecoder(r.Body)
+	decoder.UseNumber()
+	if err := decoder.Decode(&body); err != nil {
+		writeResponse(w, http.StatusBadRequest, &response{Errors: []*graphqlError{{
+			Message:    "invalid request body: " + err.Error(),
+			Extensions: map[string]interface{}{"code": "BAD_REQUEST"},
+		}}})
+		return
+	}
+	op, err := parseOperation(body.Query)
+	if err != nil {
+		writeResponse(w, http.StatusBadRequest, &response{Errors: []*graphqlError{{
+			Message:    err.Error(),
+			Extensions: map[string]interface{}{"code": "GRAPHQL_PARSE_FAILED"},
+		}}})
+		return
+	}
+
+	e := &executor{variables: body.Variables}
+	data := e.executeSelection(&root{store: s.store, caller: caller, mutation: op.kind == "mutation"}, op.selection, nil)
+	writeResponse(w, http.StatusOK, &response{Data: data, Errors: e.errors})
+}
+
+func writeResponse(w http.ResponseWriter, status int, body *response) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,429 @@
+package mockserver
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/turbot/terraform-provider-turbot/apiClient"
+)
+
+const (
+	testFolderType  = "tmod:@turbot/turbot#/resource/types/folder"
+	testAccountAka  = "arn:aws:::713469427990"
+	testIntegerType = "tmod:@turbot/provider-policy-test#/policy/types/integerPolicy"
+)
+
+// newClient builds a provider API client for the mock, as the provider would from its config
+func newClient(t *testing.T, s *Server, accessKey, secretKey string) *apiClient.Client {
+	noRetries := 0
+	client, err := apiClient.CreateClient(apiClient.ClientConfig{
+		Credentials: apiClient.ClientCredentials{AccessKey: accessKey, SecretKey: secretKey, Workspace: s.Workspace()},
+		MaxRetries:  &noRetries,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestValidate(t *testing.T) {
+	s := New()
+	defer s.Close()
+
+	assert.NoError(t, newClient(t, s, s.AccessKey, s.SecretKey).Validate())
+	err := newClient(t, s, s.AccessKey, "wrong").Validate()
+	assert.True(t, errors.Is(err, apiClient.ErrUnauthorized), "expected ErrUnauthorized, got %v", err)
+}
+
+func TestResourceLifecycle(t *testing.T) {
+	s := New()
+	defer s.Close()
+	client := newClient(t, s, s.AccessKey, s.SecretKey)
+
+	created, err := client.CreateResource(map[string]interface{}{
+		"parent": rootAka,
+		"type":   testFolderType,
+		"data":   map[string]interface{}{"title": "provider test", "description": "before"},
+		"akas":   []string{"tmod:@turbot/provider-test#/folder"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// addressable by id and by aka alike
+	byAka, err := client.ReadFullResource("tmod:@turbot/provider-test#/folder")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, created.Id, byAka.Turbot.Id)
+	assert.Equal(t, "before", byAka.Data["description"])
+
+	// an update merges into the existing data, and null removes a key
+	_, err = client.UpdateResource(map[string]interface{}{
+		"id":   created.Id,
+		"data": map[string]interface{}{"description": nil, "owner": "test"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	updated, err := client.ReadFullResource(created.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "provider test", updated.Data["title"])
+	assert.Equal(t, "test", updated.Data["owner"])
+	assert.NotContains(t, updated.Data, "description")
+
+	// akas are unique across the workspace
+	_, err = client.CreateResource(map[string]interface{}{
+		"parent": rootAka,
+		"type":   testFolderType,
+		"data":   map[string]interface{}{"title": "duplicate"},
+		"akas":   []string{"tmod:@turbot/provider-test#/folder"},
+	})
+	assert.True(t, errors.Is(err, apiClient.ErrConflict), "expected ErrConflict, got %v", err)
+
+	if err := client.DeleteResource(created.Id); err != nil {
+		t.Fatal(err)
+	}
+	exists, err := client.ResourceExists(created.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.False(t, exists)
+	_, err = client.ReadFullResource(created.Id)
+	assert.True(t, errors.Is(err, apiClient.ErrNotFound), "expected ErrNotFound, got %v", err)
+}
+
+func TestScopedIdentity(t *testing.T) {
+	s := New()
+	defer s.Close()
+	s.AddIdentity("scoped-access-key", "scoped-secret-key", testAccountAka)
+	client := newClient(t, s, "scoped-access-key", "scoped-secret-key")
+
+	// within scope
+	_, err := client.CreateResource(map[string]interface{}{
+		"parent": testAccountAka,
+		"type":   testFolderType,
+		"data":   map[string]interface{}{"title": "in scope"},
+	})
+	assert.NoError(t, err)
+
+	// outside scope, a resource is indistinguishable from a missing one
+	_, err = client.ReadFullResource("184227597889872")
+	assert.True(t, errors.Is(err, apiClient.ErrNotFound), "expected ErrNotFound, got %v", err)
+
+	// type definitions are visible to every identity, but cannot be changed
+	_, err = client.UpdateResource(map[string]interface{}{
+		"id":   testFolderType,
+		"data": map[string]interface{}{"title": "renamed"},
+	})
+	assert.True(t, errors.Is(err, apiClient.ErrUnauthorized), "expected ErrUnauthorized, got %v", err)
+}
+
+func TestPolicySettingValidation(t *testing.T) {
+	s := New()
+	defer s.Close()
+	client := newClient(t, s, s.AccessKey, s.SecretKey)
+
+	// a value must already have the schema type...
+	_, err := client.CreatePolicySetting(map[string]interface{}{
+		"resource":   rootAka,
+		"type":       testIntegerType,
+		"value":      "1",
+		"precedence": "REQUIRED",
+	})
+	assert.True(t, errors.Is(err, apiClient.ErrSchemaValidation), "expected ErrSchemaValidation, got %v", err)
+
+	// ...while a value source is parsed as YAML first
+	setting, err := client.CreatePolicySetting(map[string]interface{}{
+		"resource":    rootAka,
+		"type":        testIntegerType,
+		"valueSource": "1",
+		"precedence":  "REQUIRED",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.EqualValues(t, 1, setting.Value)
+
+	value, err := client.ReadPolicyValue(testIntegerType, testAccountAka)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.EqualValues(t, 1, value.Value)
+
+	_, err = client.UpdatePolicySetting(map[string]interface{}{"id": setting.Turbot.Id, "value": "x"})
+	assert.True(t, errors.Is(err, apiClient.ErrSchemaValidation), "expected ErrSchemaValidation, got %v", err)
+
+	if err := client.DeletePolicySetting(setting.Turbot.Id); err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.ReadPolicySetting(setting.Turbot.Id)
+	assert.True(t, errors.Is(err, apiClient.ErrNotFound), "expected ErrNotFound, got %v", err)
+}
+
+func TestSmartFolderAttachment(t *testing.T) {
+	s := New()
+	defer s.Close()
+	client := newClient(t, s, s.AccessKey, s.SecretKey)
+
+	pack, err := client.CreateSmartFolder(map[string]interface{}{
+		"parent": rootAka,
+		"title":  "provider test pack",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.CreateSmartFolderAttachment(map[string]interface{}{
+		"resource":     testAccountAka,
+		"smartFolders": []string{pack.Turbot.Id},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	attached, err := client.PolicyPackAttached(testAccountAka, pack.Turbot.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, attached)
+
+	// deleting the pack detaches it
+	if err := client.DeleteSmartFolder(pack.Turbot.Id); err != nil {
+		t.Fatal(err)
+	}
+	attached, err = client.PolicyPackAttached(testAccountAka, pack.Turbot.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.False(t, attached)
+}
+
+func TestStrictParsing(t *testing.T) {
+	for _, query := range []string{
+		`{ resource(id: "1") { ...fields } }`,
+		`{ resource(id: "1") @include(if: true) { turbot { id } } }`,
+		`{ a: resource(id: "1") { turbot { id } } } { b }`,
+	} {
+		_, err := parseOperation(query)
+		assert.Error(t, err, query)
+	}
+}
+
+func TestResourceListPaging(t *testing.T) {
+	s := New()
+	defer s.Close()
+	client := newClient(t, s, s.AccessKey, s.SecretKey)
+
+	parent, err := client.CreateResource(map[string]interface{}{
+		"parent": rootAka,
+		"type":   testFolderType,
+		"data":   map[string]interface{}{"title": "paging parent"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, env := range []string{"prod", "dev", "prod", "prod", "dev"} {
+		_, err := client.CreateResource(map[string]interface{}{
+			"parent": parent.Id,
+			"type":   testFolderType,
+			"data":   map[string]interface{}{"title": "paging child", "env": env},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// a limit is the page size; reading every page finds every match
+	resources, err := client.ReadAllResources("resource:" + parent.Id + " level:descendant $.env:prod limit:2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, resources, 3)
+	for _, resource := range resources {
+		assert.Equal(t, "prod", resource.Data["env"])
+	}
+}
+
+func TestResourceListTypeFilter(t *testing.T) {
+	s := New()
+	defer s.Close()
+	client := newClient(t, s, s.AccessKey, s.SecretKey)
+
+	for filter, expected := range map[string]int{
+		"resourceId:" + testAccountAka + " level:descendant":                                                                                2,
+		"resourceId:" + testAccountAka + " level:descendant resourceTypeId:tmod:@turbot/aws#/resource/types/region":                         1,
+		"resourceId:" + testAccountAka + " level:ancestor resourceTypeId:" + testFolderType + ",tmod:@turbot/turbot#/resource/types/turbot": 2,
+	} {
+		resources, err := client.ReadAllResources(filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Len(t, resources, expected, filter)
+	}
+}
+
+func TestControlListFilter(t *testing.T) {
+	s := New()
+	defer s.Close()
+	client := newClient(t, s, s.AccessKey, s.SecretKey)
+
+	for filter, expected := range map[string]int{
+		"resourceId:" + testAccountAka:                            2,
+		"state:alarm,ok resourceId:" + testAccountAka:             2,
+		"state:alarm,error resourceId:" + testAccountAka:          0,
+		"controlType:tmod:@turbot/aws#/control/types/accountCmdb": 1,
+	} {
+		controls, err := client.ReadAllControls(filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Len(t, controls, expected, filter)
+	}
+}
+
+func TestActor(t *testing.T) {
+	s := New()
+	defer s.Close()
+	client := newClient(t, s, s.AccessKey, s.SecretKey)
+
+	actor, err := client.ReadActor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, fixtureProfileId, actor.Identity.Turbot.Id)
+	assert.Equal(t, "184227597889872", actor.Identity.Turbot.ParentId)
+
+	activeGrants, err := client.ReadAllActiveGrants("resource:tmod:@turbot/turbot#/ identity:" + fixtureProfileId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if assert.Len(t, activeGrants, 1) {
+		assert.Equal(t, "tmod:@turbot/turbot-iam#/permission/levels/owner", activeGrants[0].Grant.Level.Uri)
+	}
+
+	// an access key without a profile has no identity
+	s.AddIdentity("scoped-access-key", "scoped-secret-key", testAccountAka)
+	actor, err = newClient(t, s, "scoped-access-key", "scoped-secret-key").ReadActor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Empty(t, actor.Identity.Turbot.Id)
+}
+
+func TestGroupProfileDirectory(t *testing.T) {
+	s := New()
+	defer s.Close()
+	client := newClient(t, s, s.AccessKey, s.SecretKey)
+
+	// a group profile is created in the directory its input names
+	groupProfile, err := client.CreateGroupProfile(map[string]interface{}{
+		"directory":      "184227597889872",
+		"title":          "admins",
+		"groupProfileId": "admins",
+		"status":         "Active",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "184227597889872", groupProfile.Parent)
+	assert.Equal(t, "admins", groupProfile.GroupProfileId)
+
+	// and only in a directory
+	_, err = client.CreateGroupProfile(map[string]interface{}{
+		"directory":      testAccountAka,
+		"title":          "admins",
+		"groupProfileId": "admins",
+	})
+	assert.True(t, errors.Is(err, apiClient.ErrValidation), "expected ErrValidation, got %v", err)
+}
+
+func TestGroupMembership(t *testing.T) {
+	s := New()
+	defer s.Close()
+	client := newClient(t, s, s.AccessKey, s.SecretKey)
+
+	groupProfile, err := client.CreateGroupProfile(map[string]interface{}{
+		"directory":      "184227597889872",
+		"title":          "admins",
+		"groupProfileId": "admins",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	groupId := groupProfile.Turbot.Id
+
+	membership, err := client.AddGroupProfileMember(groupId, fixtureProfileId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, groupId, membership.Turbot.GroupProfileId)
+	assert.Equal(t, fixtureProfileId, membership.Turbot.ProfileId)
+
+	// adding a member twice is not an error
+	if _, err = client.AddGroupProfileMember(groupId, fixtureProfileId); err != nil {
+		t.Fatal(err)
+	}
+	members, err := client.ReadGroupProfileMembers(groupId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if assert.Len(t, members, 1) {
+		assert.Equal(t, fixtureProfileId, members[0].Turbot.Id)
+	}
+
+	// only a profile can be a member
+	_, err = client.AddGroupProfileMember(groupId, "184227597889872")
+	assert.True(t, errors.Is(err, apiClient.ErrValidation), "expected ErrValidation, got %v", err)
+
+	if err = client.RemoveGroupProfileMember(groupId, fixtureProfileId); err != nil {
+		t.Fatal(err)
+	}
+	member, err := client.ReadGroupProfileMember(groupId, fixtureProfileId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Nil(t, member)
+	err = client.RemoveGroupProfileMember(groupId, fixtureProfileId)
+	assert.True(t, errors.Is(err, apiClient.ErrNotFound), "expected ErrNotFound, got %v", err)
+}
+
+func TestGrantList(t *testing.T) {
+	s := New()
+	defer s.Close()
+	client := newClient(t, s, s.AccessKey, s.SecretKey)
+
+	folder, err := client.CreateResource(map[string]interface{}{
+		"parent": rootAka,
+		"type":   testFolderType,
+		"data":   map[string]interface{}{"title": "grants"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, level := range []string{"user", "admin"} {
+		_, err := client.CreateGrant(map[string]interface{}{
+			"resource": folder.Id,
+			"identity": fixtureProfileId,
+			"type":     "tmod:@turbot/turbot-iam#/permission/types/turbot",
+			"level":    "tmod:@turbot/turbot-iam#/permission/levels/" + level,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the fixture's grant on the root is not a grant on the folder
+	grants, err := client.ReadAllGrants("resource:" + folder.Id + " level:self")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, grants, 2)
+	grants, err = client.ReadAllGrants("resource:" + rootAka + " level:sub identity:" + fixtureProfileId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, grants, 3)
+}
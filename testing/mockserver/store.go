@@ -0,0 +1,475 @@
+package mockserver
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Resource types the mock gives behaviour to. Any other type works as a plain resource, provided it
+// has been registered - see Server.AddResourceType.
+const (
+	turbotType          = "tmod:@turbot/turbot#/resource/types/turbot"
+	folderType          = "tmod:@turbot/turbot#/resource/types/folder"
+	smartFolderType     = "tmod:@turbot/turbot#/resource/types/smartFolder"
+	modType             = "tmod:@turbot/turbot#/resource/types/mod"
+	resourceTypeType    = "tmod:@turbot/turbot#/resource/types/resourceType"
+	policyTypeType      = "tmod:@turbot/turbot#/resource/types/policyType"
+	controlTypeType     = "tmod:@turbot/turbot#/resource/types/controlType"
+	permissionTypeType  = "tmod:@turbot/turbot-iam#/resource/types/permissionType"
+	permissionLevelType = "tmod:@turbot/turbot-iam#/resource/types/permissionLevel"
+	profileType         = "tmod:@turbot/turbot-iam#/resource/types/profile"
+	groupProfileType    = "tmod:@turbot/turbot-iam#/resource/types/groupProfile"
+
+	rootAka = "tmod:@turbot/turbot#/"
+)
+
+// the first id the mock hands out - above the fixture ids, and the same width as real Guardrails ids
+const firstGeneratedId = 300000000000000
+
+type resource struct {
+	id       string
+	parentId string
+	typeUri  string
+	data     map[string]interface{}
+	custom   map[string]interface{}
+	tags     map[string]interface{}
+	akas     []string
+	// public resources - mods and the type definitions they install - are visible to every identity,
+	// whatever its scope, as they are in a real workspace
+	public          bool
+	versionId       string
+	createTimestamp string
+	updateTimestamp string
+	// policy packs attached to this resource, by id, in attachment order
+	attached []string
+}
+
+type policySetting struct {
+	id            string
+	resourceId    string
+	typeUri       string
+	value         interface{}
+	valueSource   string
+	template      interface{}
+	templateInput interface{}
+	precedence    string
+	note          interface{}
+	validFrom     interface{}
+	validTo       interface{}
+}
+
+type grant struct {
+	id                string
+	profileId         string
+	resourceId        string
+	permissionTypeId  string
+	permissionLevelId string
+}
+
+type activeGrant struct {
+	id         string
+	grantId    string
+	resourceId string
+}
+
+type watch struct {
+	id          string
+	resourceId  string
+	favoriteId  interface{}
+	action      string
+	filters     []interface{}
+	description string
+}
+
+type control struct {
+	id         string
+	resourceId string
+	typeUri    string
+	state      string
+	reason     interface{}
+	details    interface{}
+	mute       map[string]interface{}
+}
+
+type modVersion struct {
+	version string
+	status  string
+}
+
+// identity is an access key pair the workspace accepts. It can see and change its scope resource and
+// everything below it; the default identity is scoped to the Turbot root.
+type identity struct {
+	accessKey string
+	secretKey string
+	scope     string
+	// the id of the profile the access key belongs to, if any
+	profileId string
+}
+
+// store is the in-memory workspace. A request holds the mutex for its whole execution, so every
+// operation sees - and leaves - a consistent workspace.
+type store struct {
+	mutex  sync.Mutex
+	nextId int64
+	rootId string
+
+	identities   []*identity
+	resources    map[string]*resource
+	settings     map[string]*policySetting
+	grants       map[string]*grant
+	activeGrants map[string]*activeGrant
+	watches      map[string]*watch
+	controls     map[string]*control
+	// the member profile ids of each group profile, keyed by group profile id
+	members map[string]map[string]bool
+	// available mod versions, keyed by "org/mod"
+	modVersions map[string][]modVersion
+}
+
+func newStore() *store {
+	return &store{
+		nextId:       firstGeneratedId,
+		resources:    map[string]*resource{},
+		settings:     map[string]*policySetting{},
+		grants:       map[string]*grant{},
+		activeGrants: map[string]*activeGrant{},
+		watches:      map[string]*watch{},
+		controls:     map[string]*control{},
+		members:      map[string]map[string]bool{},
+		modVersions:  map[string][]modVersion{},
+	}
+}
+
+func (s *store) newId() string {
+	id := strconv.FormatInt(s.nextId, 10)
+	s.nextId++
+	return id
+}
+
+func timestamp() string {
+	return time.Now().UTC().Format("2006-01-02T15:04:05.000Z")
+}
+
+func (s *store) authenticate(accessKey, secretKey string) *identity {
+	for _, identity := range s.identities {
+		if identity.accessKey == accessKey && identity.secretKey == secretKey {
+			return identity
+		}
+	}
+	return nil
+}
+
+// find looks a resource up by id or by any of its akas, regardless of who is asking
+func (s *store) find(idOrAka string) *resource {
+	if r, ok := s.resources[idOrAka]; ok {
+		return r
+	}
+	for _, r := range s.resources {
+		for _, aka := range r.akas {
+			if aka == idOrAka {
+				return r
+			}
+		}
+	}
+	return nil
+}
+
+// isWithin reports whether r is the resource with id ancestor, or somewhere below it
+func (s *store) isWithin(r *resource, ancestor string) bool {
+	for current := r; current != nil; current = s.resources[current.parentId] {
+		if current.id == ancestor {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *store) canAccess(caller *identity, r *resource) bool {
+	return r.public || s.isWithin(r, caller.scope)
+}
+
+// lookup resolves a resource the caller can see. A resource outside the caller's scope is reported
+// exactly as a missing one, as the API does, so its existence does not leak.
+func (s *store) lookup(caller *identity, idOrAka string) (*resource, error) {
+	r := s.find(idOrAka)
+	if r == nil || !s.canAccess(caller, r) {
+		return nil, notFound("Resource not found or not accessible: %s", idOrAka)
+	}
+	return r, nil
+}
+
+// lookupForUpdate resolves a resource the caller may change. Unlike lookup, a resource the caller can
+// see but not change - a public type definition, say - is refused rather than reported missing.
+func (s *store) lookupForUpdate(caller *identity, idOrAka string) (*resource, error) {
+	r := s.find(idOrAka)
+	if r == nil || !s.canAccess(caller, r) {
+		return nil, notFound("Resource not found or not accessible: %s", idOrAka)
+	}
+	if !s.isWithin(r, caller.scope) {
+		return nil, permissionDenied("identity cannot modify resource %s", r.id)
+	}
+	return r, nil
+}
+
+// ancestors returns the ids from the root down to r, inclusive
+func (s *store) ancestors(r *resource) []string {
+	var path []string
+	for current := r; current != nil; current = s.resources[current.parentId] {
+		path = append([]string{current.id}, path...)
+	}
+	return path
+}
+
+func (s *store) children(id string) []*resource {
+	var children []*resource
+	for _, r := range s.resources {
+		if r.parentId == id {
+			children = append(children, r)
+		}
+	}
+	sortResources(children)
+	return children
+}
+
+// sortResources orders resources by id, which is creation order, so list results are stable
+func sortResources(resources []*resource) {
+	sort.Slice(resources, func(i, j int) bool {
+		return idLess(resources[i].id, resources[j].id)
+	})
+}
+
+func idLess(a, b string) bool {
+	if len(a) != len(b) {
+		return len(a) < len(b)
+	}
+	return a < b
+}
+
+// akaAvailable reports whether every aka in akas is free for the resource with the given id
+func (s *store) akaAvailable(akas []string, id string) (string, bool) {
+	for _, aka := range akas {
+		if existing := s.find(aka); existing != nil && existing.id != id {
+			return aka, false
+		}
+	}
+	return "", true
+}
+
+// insertResource adds a resource under parent. The caller has already validated the input.
+func (s *store) insertResource(parent *resource, typeUri string, data, custom, tags map[string]interface{}, akas []string) *resource {
+	now := timestamp()
+	r := &resource{
+		id:              s.newId(),
+		typeUri:         typeUri,
+		data:            data,
+		custom:          custom,
+		tags:            tags,
+		akas:            akas,
+		versionId:       s.newId(),
+		createTimestamp: now,
+		updateTimestamp: now,
+	}
+	if parent != nil {
+		r.parentId = parent.id
+	}
+	if r.data == nil {
+		r.data = map[string]interface{}{}
+	}
+	s.resources[r.id] = r
+	return r
+}
+
+func (r *resource) touch(s *store) {
+	r.versionId = s.newId()
+	r.updateTimestamp = timestamp()
+}
+
+// deleteResource removes r with everything below it, and everything attached to any of it
+func (s *store) deleteResource(r *resource) {
+	for _, child := range s.children(r.id) {
+		s.deleteResource(child)
+	}
+	for id, setting := range s.settings {
+		if setting.resourceId == r.id {
+			delete(s.settings, id)
+		}
+	}
+	for id, g := range s.grants {
+		if g.resourceId == r.id {
+			s.deleteGrant(g)
+			delete(s.grants, id)
+		}
+	}
+	for id, active := range s.activeGrants {
+		if active.resourceId == r.id {
+			delete(s.activeGrants, id)
+		}
+	}
+	for id, w := range s.watches {
+		if w.resourceId == r.id {
+			delete(s.watches, id)
+		}
+	}
+	for id, c := range s.controls {
+		if c.resourceId == r.id {
+			delete(s.controls, id)
+		}
+	}
+	// a deleted group profile has no members, and a deleted profile is in no group
+	delete(s.members, r.id)
+	for _, members := range s.members {
+		delete(members, r.id)
+	}
+	// a deleted policy pack is detached from everything it was attached to
+	for _, other := range s.resources {
+		other.attached = removeString(other.attached, r.id)
+	}
+	delete(s.resources, r.id)
+}
+
+func (s *store) deleteGrant(g *grant) {
+	for id, active := range s.activeGrants {
+		if active.grantId == g.id {
+			delete(s.activeGrants, id)
+		}
+	}
+	delete(s.grants, g.id)
+}
+
+func removeString(list []string, value string) []string {
+	var result []string
+	for _, item := range list {
+		if item != value {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+func (r *resource) title() string {
+	for _, key := range []string{"title", "name"} {
+		if title, ok := r.data[key].(string); ok && title != "" {
+			return title
+		}
+	}
+	if len(r.akas) > 0 {
+		return r.akas[0]
+	}
+	return r.id
+}
+
+// metadata is the `turbot` property of the resource
+func (s *store) metadata(r *resource) map[string]interface{} {
+	var parentId, resourceTypeId interface{}
+	if r.parentId != "" {
+		parentId = r.parentId
+	}
+	if resourceType := s.find(r.typeUri); resourceType != nil {
+		resourceTypeId = resourceType.id
+	}
+	var akas interface{}
+	if len(r.akas) > 0 {
+		akas = stringsToList(r.akas)
+	}
+	return map[string]interface{}{
+		"id":                r.id,
+		"parentId":          parentId,
+		"akas":              akas,
+		"custom":            copyValue(r.custom),
+		"tags":              copyValue(r.tags),
+		"title":             r.title(),
+		"versionId":         r.versionId,
+		"createTimestamp":   r.createTimestamp,
+		"updateTimestamp":   r.updateTimestamp,
+		"deleteTimestamp":   nil,
+		"path":              strings.Join(s.ancestors(r), "."),
+		"resourceTypeId":    resourceTypeId,
+		"resourceGroupIds":  nil,
+		"state":             "active",
+		"actorIdentityId":   nil,
+		"actorPersonaId":    nil,
+		"actorRoleId":       nil,
+		"resourceParentAka": nil,
+		"terraform":         nil,
+	}
+}
+
+// document is the resource as `get(path:)` sees it: its data, with its metadata under `turbot`
+func (s *store) document(r *resource) map[string]interface{} {
+	document := copyValue(r.data).(map[string]interface{})
+	document["turbot"] = s.metadata(r)
+	return document
+}
+
+// getPath follows a dotted path - "turbot.akas.0" - through JSON data. An empty path is the whole
+// value, and a path that leads nowhere is null, as `get(path:)` answers.
+func getPath(value interface{}, path string) interface{} {
+	if path == "" {
+		return value
+	}
+	for _, segment := range strings.Split(path, ".") {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			value = v[segment]
+		case []interface{}:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(v) {
+				return nil
+			}
+			value = v[index]
+		default:
+			return nil
+		}
+	}
+	return value
+}
+
+// copyValue deep-copies JSON data, so a response never aliases the store
+func copyValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if v == nil {
+			return v
+		}
+		result := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			result[key] = copyValue(item)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			result[i] = copyValue(item)
+		}
+		return result
+	}
+	return value
+}
+
+func stringsToList(values []string) []interface{} {
+	list := make([]interface{}, len(values))
+	for i, value := range values {
+		list[i] = value
+	}
+	return list
+}
+
+// mergeData applies an update to data: each key of update replaces the existing value, and a null
+// removes it.
+func mergeData(data, update map[string]interface{}) map[string]interface{} {
+	result := map[string]interface{}{}
+	for key, value := range data {
+		result[key] = copyValue(value)
+	}
+	for key, value := range update {
+		if value == nil {
+			delete(result, key)
+			continue
+		}
+		result[key] = copyValue(value)
+	}
+	return result
+}
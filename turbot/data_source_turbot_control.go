@@ -1,10 +1,10 @@
 package turbot
 
 import (
+	"errors"
 	"fmt"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/turbot/terraform-provider-turbot/apiClient"
-	"github.com/turbot/terraform-provider-turbot/errors"
 )
 
 func dataSourceTurbotControl() *schema.Resource {
@@ -60,7 +60,7 @@ func dataSourceTurbotControlRead(d *schema.ResourceData, meta interface{}) error
 
 	control, err := client.ReadControl(args)
 	if err != nil {
-		if errors.NotFoundError(err) {
+		if errors.Is(err, apiClient.ErrNotFound) {
 			// setting was not found - clear id
 			d.SetId("")
 		}
@@ -0,0 +1,101 @@
+package turbot
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/turbot/terraform-provider-turbot/apiClient"
+	"github.com/turbot/terraform-provider-turbot/helpers"
+)
+
+// the control states Guardrails reports - state_counts has an entry for each, so a count can be read
+// without checking whether any control is in that state
+var controlStates = []string{"ok", "alarm", "error", "invalid", "skipped", "tbd"}
+
+func dataSourceTurbotControls() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceTurbotControlsRead,
+		Schema: map[string]*schema.Schema{
+			"filter": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"controls": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"resource": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"state": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"reason": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"details": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"state_counts": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeInt,
+				},
+			},
+		},
+	}
+}
+
+func dataSourceTurbotControlsRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*apiClient.Client)
+	filter := d.Get("filter").(string)
+
+	controls, err := client.ReadAllControls(filter)
+	if err != nil {
+		return err
+	}
+
+	stateCounts := map[string]interface{}{}
+	for _, state := range controlStates {
+		stateCounts[state] = 0
+	}
+	var items []map[string]interface{}
+	for _, control := range controls {
+		// control details are JSON - usually a list of title and value pairs
+		details, err := helpers.InterfaceToStringOrJson(control.Details)
+		if err != nil {
+			return err
+		}
+		items = append(items, map[string]interface{}{
+			"id":       control.Turbot.Id,
+			"type":     control.Type.Uri,
+			"resource": control.Turbot.ResourceId,
+			"state":    control.State,
+			"reason":   control.Reason,
+			"details":  details,
+		})
+		count, _ := stateCounts[control.State].(int)
+		stateCounts[control.State] = count + 1
+	}
+
+	d.SetId(filter)
+	if err := d.Set("controls", items); err != nil {
+		return err
+	}
+	return d.Set("state_counts", stateCounts)
+}
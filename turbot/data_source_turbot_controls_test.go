@@ -0,0 +1,49 @@
+package turbot
+
+import (
+	"github.com/hashicorp/terraform/helper/resource"
+	"testing"
+)
+
+func TestAccControlsDataSource_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccControlsConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.turbot_controls.test", "controls.#", "1"),
+					resource.TestCheckResourceAttr("data.turbot_controls.test", "controls.0.id", "178806515688264"),
+					resource.TestCheckResourceAttr("data.turbot_controls.test", "controls.0.type", "tmod:@turbot/turbot#/control/types/controlInstalled"),
+					resource.TestCheckResourceAttr("data.turbot_controls.test", "controls.0.resource", "178806515411691"),
+					resource.TestCheckResourceAttr("data.turbot_controls.test", "state_counts.%", "6"),
+				),
+			},
+			{
+				Config: testAccControlsStateConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.turbot_controls.test", "controls.#", "0"),
+					resource.TestCheckResourceAttr("data.turbot_controls.test", "state_counts.tbd", "0"),
+				),
+			},
+		},
+	})
+}
+
+// config
+func testAccControlsConfig() string {
+	return `
+data "turbot_controls" "test" {
+  filter = "controlType:tmod:@turbot/turbot#/control/types/controlInstalled resourceId:178806515411691"
+}
+`
+}
+
+func testAccControlsStateConfig() string {
+	return `
+data "turbot_controls" "test" {
+  filter = "state:tbd controlType:tmod:@turbot/turbot#/control/types/controlInstalled resourceId:178806515411691"
+}
+`
+}
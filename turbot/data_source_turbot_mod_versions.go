@@ -0,0 +1,104 @@
+package turbot
+
+import (
+	"fmt"
+	"github.com/Masterminds/semver"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/turbot/terraform-provider-turbot/apiClient"
+	"log"
+	"sort"
+)
+
+func dataSourceTurbotModVersions() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceTurbotModVersionsRead,
+		Schema: map[string]*schema.Schema{
+			"org": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"mod": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"version": {
+				Type:     schema.TypeString,
+				Optional: true,
+				// default the version to any version, as turbot_mod does
+				Default: "*",
+			},
+			// every version in the registry, with its status - the constraint only selects version_latest
+			"versions": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"version": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"status": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			// the latest version which satisfies the version requirement, as turbot_mod would install
+			"version_latest": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceTurbotModVersionsRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*apiClient.Client)
+	org := d.Get("org").(string)
+	modName := d.Get("mod").(string)
+	version := d.Get("version").(string)
+
+	c, err := semver.NewConstraint(version)
+	if err != nil {
+		return fmt.Errorf("invalid version constraint '%s': %s", version, err.Error())
+	}
+	modVersions, err := client.GetModVersions(org, modName)
+	if err != nil {
+		return err
+	}
+
+	// every version in the registry, oldest first. A version which is not semver cannot be ordered or
+	// satisfy a constraint, so is left out.
+	type registryVersion struct {
+		version *semver.Version
+		status  string
+	}
+	var parsed []registryVersion
+	var semverVersions []apiClient.ModRegistryVersion
+	for _, modVersion := range modVersions {
+		v, err := semver.NewVersion(modVersion.Version)
+		if err != nil {
+			log.Printf("[WARN] skipping mod %s/%s version '%s', which is not semver: %s", org, modName, modVersion.Version, err.Error())
+			continue
+		}
+		parsed = append(parsed, registryVersion{v, modVersion.Status})
+		semverVersions = append(semverVersions, modVersion)
+	}
+	versionLatest, err := latestCompatibleVersion(semverVersions, c)
+	if err != nil {
+		return err
+	}
+	sort.Slice(parsed, func(i, j int) bool { return parsed[i].version.LessThan(parsed[j].version) })
+	var versions []map[string]interface{}
+	for _, modVersion := range parsed {
+		versions = append(versions, map[string]interface{}{
+			"version": modVersion.version.String(),
+			"status":  modVersion.status,
+		})
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s", org, modName))
+	d.Set("version_latest", versionLatest)
+	return d.Set("versions", versions)
+}
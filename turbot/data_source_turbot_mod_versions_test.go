@@ -0,0 +1,54 @@
+package turbot
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform/helper/resource"
+	"testing"
+)
+
+func TestAccModVersionsDataSource_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccModVersionsDataSourceConfig("turbot-terraform-provider-test", "~5.0.0"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.turbot_mod_versions.test", "versions.#", "4"),
+					resource.TestCheckResourceAttr("data.turbot_mod_versions.test", "versions.0.version", "5.0.0"),
+					resource.TestCheckResourceAttr("data.turbot_mod_versions.test", "versions.0.status", "AVAILABLE"),
+					resource.TestCheckResourceAttr("data.turbot_mod_versions.test", "versions.3.version", "5.1.0"),
+					resource.TestCheckResourceAttr("data.turbot_mod_versions.test", "versions.3.status", "RECOMMENDED"),
+					resource.TestCheckResourceAttr("data.turbot_mod_versions.test", "version_latest", "5.0.2"),
+				),
+			},
+			{
+				Config: testAccModVersionsDataSourceConfig("turbot-terraform-provider-test", "<5.0.0"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.turbot_mod_versions.test", "versions.#", "4"),
+					resource.TestCheckResourceAttr("data.turbot_mod_versions.test", "version_latest", ""),
+				),
+			},
+			{
+				// every semver version is listed - the version which is not semver is skipped
+				Config: testAccModVersionsDataSourceConfig("aws", ">=5.0.0"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.turbot_mod_versions.test", "versions.#", "2"),
+					resource.TestCheckResourceAttr("data.turbot_mod_versions.test", "versions.0.version", "5.0.0"),
+					resource.TestCheckResourceAttr("data.turbot_mod_versions.test", "version_latest", "5.1.0"),
+				),
+			},
+		},
+	})
+}
+
+// config
+func testAccModVersionsDataSourceConfig(mod, version string) string {
+	return fmt.Sprintf(`
+data "turbot_mod_versions" "test" {
+  org     = "turbot"
+  mod     = "%s"
+  version = "%s"
+}
+`, mod, version)
+}
@@ -0,0 +1,132 @@
+package turbot
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/turbot/terraform-provider-turbot/apiClient"
+	"github.com/turbot/terraform-provider-turbot/helpers"
+	"strings"
+)
+
+// the scopes a policy settings search can take, relative to the resource
+var policySettingScopes = []string{"self", "descendant", "ancestor"}
+
+func dataSourceTurbotPolicySettings() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceTurbotPolicySettingsRead,
+		Schema: map[string]*schema.Schema{
+			"resource": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"type": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"scope": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "self",
+			},
+			"settings": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"resource": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"value": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"value_source": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"precedence": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"template": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"template_input": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"note": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"valid_from_timestamp": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"valid_to_timestamp": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceTurbotPolicySettingsRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*apiClient.Client)
+	resourceAka := d.Get("resource").(string)
+	scope := d.Get("scope").(string)
+	if !helpers.SliceContains(policySettingScopes, scope) {
+		return fmt.Errorf("'scope' must be one of %s, got '%s'", strings.Join(policySettingScopes, ", "), scope)
+	}
+
+	filter := fmt.Sprintf("resource:%s level:%s", resourceAka, scope)
+	if policyTypeUri, ok := d.GetOk("type"); ok {
+		filter = fmt.Sprintf("%s policyType:%s", filter, policyTypeUri.(string))
+	}
+	settings, err := client.ReadAllPolicySettings(filter)
+	if err != nil {
+		return err
+	}
+
+	var items []map[string]interface{}
+	for _, setting := range settings {
+		// NOTE: TemplateInput can be string or array of strings - see resourceTurbotPolicySettingRead
+		templateInput, err := helpers.InterfaceToStringOrYaml(setting.TemplateInput)
+		if err != nil {
+			return err
+		}
+		value, err := helpers.InterfaceToStringOrJson(setting.Value)
+		if err != nil {
+			return err
+		}
+		items = append(items, map[string]interface{}{
+			"id":                   setting.Turbot.Id,
+			"type":                 setting.Type.Uri,
+			"resource":             setting.Turbot.ResourceId,
+			"value":                value,
+			"value_source":         setting.ValueSource,
+			"precedence":           setting.Precedence,
+			"template":             setting.Template,
+			"template_input":       templateInput,
+			"note":                 setting.Note,
+			"valid_from_timestamp": setting.ValidFromTimestamp,
+			"valid_to_timestamp":   setting.ValidToTimestamp,
+		})
+	}
+
+	d.SetId(filter)
+	return d.Set("settings", items)
+}
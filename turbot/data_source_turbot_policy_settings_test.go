@@ -0,0 +1,73 @@
+package turbot
+
+import (
+	"github.com/hashicorp/terraform/helper/resource"
+	"testing"
+)
+
+func TestAccPolicySettingsDataSource_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPolicySettingsDataSourceConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					// self: only the setting on the folder itself
+					resource.TestCheckResourceAttr("data.turbot_policy_settings.self", "settings.#", "1"),
+					resource.TestCheckResourceAttrPair("data.turbot_policy_settings.self", "settings.0.id", "turbot_policy_setting.parent", "id"),
+					resource.TestCheckResourceAttrPair("data.turbot_policy_settings.self", "settings.0.resource", "turbot_folder.parent", "id"),
+					resource.TestCheckResourceAttr("data.turbot_policy_settings.self", "settings.0.type", stringPolicyType),
+					resource.TestCheckResourceAttr("data.turbot_policy_settings.self", "settings.0.value", "parent value"),
+					resource.TestCheckResourceAttr("data.turbot_policy_settings.self", "settings.0.precedence", "REQUIRED"),
+					// descendant: only the setting on the child folder
+					resource.TestCheckResourceAttr("data.turbot_policy_settings.descendant", "settings.#", "1"),
+					resource.TestCheckResourceAttrPair("data.turbot_policy_settings.descendant", "settings.0.id", "turbot_policy_setting.child", "id"),
+					resource.TestCheckResourceAttr("data.turbot_policy_settings.descendant", "settings.0.value", "child value"),
+				),
+			},
+		},
+	})
+}
+
+func testAccPolicySettingsDataSourceConfig() string {
+	return `
+resource "turbot_folder" "parent" {
+	parent = "tmod:@turbot/turbot#/"
+	title = "provider_test_policy_settings"
+	description = "test folder for guardrails terraform provider"
+}
+
+resource "turbot_folder" "child" {
+	parent = turbot_folder.parent.id
+	title = "provider_test_policy_settings_child"
+	description = "test folder for guardrails terraform provider"
+}
+
+resource "turbot_policy_setting" "parent" {
+	resource = turbot_folder.parent.id
+	type = "tmod:@turbot/provider-policy-test#/policy/types/stringPolicy"
+	value = "parent value"
+	precedence = "REQUIRED"
+}
+
+resource "turbot_policy_setting" "child" {
+	resource = turbot_folder.child.id
+	type = "tmod:@turbot/provider-policy-test#/policy/types/stringPolicy"
+	value = "child value"
+	precedence = "RECOMMENDED"
+}
+
+# the searches refer to the settings, so they are read once both exist
+data "turbot_policy_settings" "self" {
+	resource = turbot_policy_setting.parent.resource
+	type = turbot_policy_setting.child.type
+}
+
+data "turbot_policy_settings" "descendant" {
+	resource = turbot_policy_setting.parent.resource
+	type = turbot_policy_setting.child.type
+	scope = "descendant"
+}
+`
+}
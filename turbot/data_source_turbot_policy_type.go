@@ -0,0 +1,150 @@
+package turbot
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/turbot/terraform-provider-turbot/apiClient"
+	"github.com/turbot/terraform-provider-turbot/helpers"
+	"strings"
+)
+
+func dataSourceTurbotPolicyType() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceTurbotPolicyTypeRead,
+		Schema: map[string]*schema.Schema{
+			"uri": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"title": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"schema": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"enum": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"default": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"default_template": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"default_template_input": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"targets": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"secret": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"mod_uri": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceTurbotPolicyTypeRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*apiClient.Client)
+	policyTypeUri := d.Get("uri").(string)
+
+	policyType, err := client.ReadPolicyType(policyTypeUri)
+	if err != nil {
+		if errors.Is(err, apiClient.ErrNotFound) {
+			// policy type was not found - clear id
+			d.SetId("")
+		}
+		return err
+	}
+
+	schemaJson, err := helpers.InterfaceToStringOrJson(policyType.Schema)
+	if err != nil {
+		return err
+	}
+	defaultValue, err := helpers.InterfaceToStringOrJson(policyType.Default)
+	if err != nil {
+		return err
+	}
+	// NOTE: the template input can be a string or an array of strings - see resourceTurbotPolicySettingRead
+	defaultTemplateInput, err := helpers.InterfaceToStringOrYaml(policyType.DefaultTemplateInput)
+	if err != nil {
+		return err
+	}
+
+	uri := definitionUri(policyTypeUri, policyType.Turbot.Akas)
+
+	d.SetId(policyType.Turbot.Id)
+	d.Set("uri", uri)
+	d.Set("title", policyType.Title)
+	d.Set("description", policyType.Description)
+	d.Set("schema", schemaJson)
+	d.Set("enum", policyTypeEnum(policyType.Schema))
+	d.Set("default", defaultValue)
+	d.Set("default_template", policyType.DefaultTemplate)
+	d.Set("default_template_input", defaultTemplateInput)
+	d.Set("targets", typeUris(policyType.Targets))
+	d.Set("secret", policyType.Secret)
+	d.Set("mod_uri", strings.SplitN(uri, "#", 2)[0])
+	return nil
+}
+
+// the values a policy type's schema allows, if it lists them
+func policyTypeEnum(policySchema interface{}) []string {
+	schemaMap, _ := policySchema.(map[string]interface{})
+	values, _ := schemaMap["enum"].([]interface{})
+	var result []string
+	for _, value := range values {
+		result = append(result, fmt.Sprintf("%v", value))
+	}
+	return result
+}
+
+// definitionUri returns the uri of a definition - a policy or resource type - which is its aka in the
+// tmod: namespace, whether it was read by uri or id. The identifier it was read by is the fallback.
+func definitionUri(identifier string, akas []string) string {
+	for _, aka := range akas {
+		if strings.HasPrefix(aka, "tmod:") {
+			return aka
+		}
+	}
+	return identifier
+}
+
+// a definition refers to other types - the resource types a policy type targets, or a resource
+// type's parents - by one type uri, or a list of them
+func typeUris(uris interface{}) []string {
+	switch v := uris.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		var result []string
+		for _, uri := range v {
+			result = append(result, fmt.Sprintf("%v", uri))
+		}
+		return result
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package turbot
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform/helper/resource"
+	"testing"
+)
+
+func TestAccPolicyTypeDataSource_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPolicyTypeDataSourceConfig("tmod:@turbot/aws-s3#/policy/types/bucketApprovedUsage"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.turbot_policy_type.test", "mod_uri", "tmod:@turbot/aws-s3"),
+					resource.TestCheckResourceAttr("data.turbot_policy_type.test", "enum.#", "5"),
+					resource.TestCheckResourceAttr("data.turbot_policy_type.test", "enum.0", "Skip"),
+					resource.TestCheckResourceAttr("data.turbot_policy_type.test", "default", "Skip"),
+					resource.TestCheckResourceAttr("data.turbot_policy_type.test", "targets.0", "tmod:@turbot/aws-s3#/resource/types/bucket"),
+					resource.TestCheckResourceAttr("data.turbot_policy_type.test", "secret", "false"),
+				),
+			},
+			{
+				Config: testAccPolicyTypeDataSourceConfig(secretPolicyType),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.turbot_policy_type.test", "uri", secretPolicyType),
+					resource.TestCheckResourceAttr("data.turbot_policy_type.test", "schema", `{"type":"string"}`),
+					resource.TestCheckResourceAttr("data.turbot_policy_type.test", "enum.#", "0"),
+					resource.TestCheckResourceAttr("data.turbot_policy_type.test", "secret", "true"),
+				),
+			},
+		},
+	})
+}
+
+// config
+func testAccPolicyTypeDataSourceConfig(policyTypeUri string) string {
+	return fmt.Sprintf(`
+data "turbot_policy_type" "test" {
+  uri = "%s"
+}
+`, policyTypeUri)
+}
@@ -1,10 +1,10 @@
 package turbot
 
 import (
+	"errors"
 	"fmt"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/turbot/terraform-provider-turbot/apiClient"
-	"github.com/turbot/terraform-provider-turbot/errors"
 )
 
 func dataSourceTurbotPolicyValue() *schema.Resource {
@@ -58,7 +58,7 @@ func dataSourceTurbotPolicyValueRead(d *schema.ResourceData, meta interface{}) e
 
 	policyValue, err := client.ReadPolicyValue(policyTypeUri, resourceAka)
 	if err != nil {
-		if errors.NotFoundError(err) {
+		if errors.Is(err, apiClient.ErrNotFound) {
 			// setting was not found - clear id
 			d.SetId("")
 		}
@@ -1,9 +1,9 @@
 package turbot
 
 import (
+	"errors"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/turbot/terraform-provider-turbot/apiClient"
-	"github.com/turbot/terraform-provider-turbot/errors"
 )
 
 func dataSourceTurbotResource() *schema.Resource {
@@ -52,7 +52,7 @@ func dataSourceTurbotResourceRead(d *schema.ResourceData, meta interface{}) erro
 	resourceAka := d.Get("id").(string)
 	resource, err := client.ReadSerializableResource(resourceAka)
 	if err != nil {
-		if errors.NotFoundError(err) {
+		if errors.Is(err, apiClient.ErrNotFound) {
 			// setting was not found - clear id
 			d.SetId("")
 		}
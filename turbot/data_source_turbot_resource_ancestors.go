@@ -0,0 +1,68 @@
+package turbot
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/turbot/terraform-provider-turbot/apiClient"
+	"sort"
+)
+
+func dataSourceTurbotResourceAncestors() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceTurbotResourceAncestorsRead,
+		Schema: map[string]*schema.Schema{
+			"resource": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			// the number of levels above the resource to return - 1 is its parent, 0 is every ancestor
+			"depth": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  0,
+			},
+			"resource_types": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"ancestors": resourceTreeListSchema(),
+		},
+	}
+}
+
+func dataSourceTurbotResourceAncestorsRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*apiClient.Client)
+	resourceAka := d.Get("resource").(string)
+	depth := d.Get("depth").(int)
+	if depth < 0 {
+		return fmt.Errorf("depth must be 0 or greater, got %d", depth)
+	}
+
+	// the path of the resource orders its ancestors, and gives the depth of each
+	resource, err := client.ReadFullResource(resourceAka)
+	if err != nil {
+		return err
+	}
+	filter := resourceTreeFilter(resource.Turbot.Id, "ancestor", d.Get("resource_types").([]interface{}))
+	ancestors, err := client.ReadAllResourceMetadata(filter)
+	if err != nil {
+		return err
+	}
+
+	resourceDepth := len(resourcePath(resource))
+	var items []map[string]interface{}
+	for _, ancestor := range ancestors {
+		item := flattenResourceTreeItem(ancestor, resourceDepth-len(resourcePath(&ancestor)))
+		if depth == 0 || item["depth"].(int) <= depth {
+			items = append(items, item)
+		}
+	}
+	// the full path down to the resource: the Turbot root first, the parent last
+	sort.SliceStable(items, func(i, j int) bool { return items[i]["depth"].(int) > items[j]["depth"].(int) })
+
+	d.SetId(fmt.Sprintf("%s depth:%d", filter, depth))
+	return d.Set("ancestors", items)
+}
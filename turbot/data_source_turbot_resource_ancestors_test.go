@@ -0,0 +1,44 @@
+package turbot
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform/helper/resource"
+	"testing"
+)
+
+func TestAccResourceAncestorsDataSource_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceAncestorsDataSourceConfig(0),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.turbot_resource_ancestors.test", "ancestors.#", "4"),
+					resource.TestCheckResourceAttr("data.turbot_resource_ancestors.test", "ancestors.0.aka", "tmod:@turbot/turbot#/"),
+					resource.TestCheckResourceAttr("data.turbot_resource_ancestors.test", "ancestors.0.depth", "4"),
+					resource.TestCheckResourceAttr("data.turbot_resource_ancestors.test", "ancestors.1.title", "AWS Accounts"),
+					resource.TestCheckResourceAttr("data.turbot_resource_ancestors.test", "ancestors.3.aka", "arn:aws::us-east-2:713469427990"),
+					resource.TestCheckResourceAttr("data.turbot_resource_ancestors.test", "ancestors.3.depth", "1"),
+				),
+			},
+			{
+				Config: testAccResourceAncestorsDataSourceConfig(2),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.turbot_resource_ancestors.test", "ancestors.#", "2"),
+					resource.TestCheckResourceAttr("data.turbot_resource_ancestors.test", "ancestors.0.type", "tmod:@turbot/aws#/resource/types/account"),
+				),
+			},
+		},
+	})
+}
+
+// config
+func testAccResourceAncestorsDataSourceConfig(depth int) string {
+	return fmt.Sprintf(`
+data "turbot_resource_ancestors" "test" {
+  resource = "arn:aws:logs:us-east-2:713469427990:log-group:provider-test-hashicorp"
+  depth    = %d
+}
+`, depth)
+}
@@ -0,0 +1,192 @@
+package turbot
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/turbot/terraform-provider-turbot/apiClient"
+	"sort"
+	"strings"
+)
+
+func dataSourceTurbotResourceChildren() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceTurbotResourceChildrenRead,
+		Schema: map[string]*schema.Schema{
+			"resource": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			// the number of levels below the resource to return - 1 is its children, 0 is every descendant
+			"depth": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  1,
+			},
+			"resource_types": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"children": resourceTreeListSchema(),
+		},
+	}
+}
+
+func dataSourceTurbotResourceChildrenRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*apiClient.Client)
+	resourceAka := d.Get("resource").(string)
+	depth := d.Get("depth").(int)
+	if depth < 0 {
+		return fmt.Errorf("depth must be 0 or greater, got %d", depth)
+	}
+
+	resource, err := client.ReadFullResource(resourceAka)
+	if err != nil {
+		return err
+	}
+	resourceTypes := d.Get("resource_types").([]interface{})
+	filter := resourceTreeFilter(resource.Turbot.Id, "descendant", resourceTypes)
+	var children []map[string]interface{}
+	if depth == 0 {
+		children, err = readResourceDescendants(client, resource, filter)
+	} else {
+		children, err = readResourceChildren(client, resource.Turbot.Id, depth, resourceTypes)
+	}
+	if err != nil {
+		return err
+	}
+
+	d.SetId(fmt.Sprintf("%s depth:%d", filter, depth))
+	return d.Set("children", children)
+}
+
+// readResourceDescendants reads every descendant of the resource matching filter, nearest first. The
+// depth of a descendant is read from its path, relative to the path of the resource.
+func readResourceDescendants(client *apiClient.Client, resource *apiClient.Resource, filter string) ([]map[string]interface{}, error) {
+	descendants, err := client.ReadAllResourceMetadata(filter)
+	if err != nil {
+		return nil, err
+	}
+	rootDepth := len(resourcePath(resource))
+	var children []map[string]interface{}
+	for _, descendant := range descendants {
+		children = append(children, flattenResourceTreeItem(descendant, len(resourcePath(&descendant))-rootDepth))
+	}
+	// children, then grandchildren, each level in the order the API returned it
+	sort.SliceStable(children, func(i, j int) bool { return children[i]["depth"].(int) < children[j]["depth"].(int) })
+	return children, nil
+}
+
+// readResourceChildren reads the resources to depth levels below the resource, nearest first. The tree
+// is walked down one level at a time with a level:child read of each parent, so only the levels within
+// the depth are read. With resource types, every child but those of the last level is still read, as
+// the parents of the next level, and the children of the types are read on their own.
+func readResourceChildren(client *apiClient.Client, resourceId string, depth int, resourceTypes []interface{}) ([]map[string]interface{}, error) {
+	var children []map[string]interface{}
+	parents := []string{resourceId}
+	for level := 1; level <= depth && len(parents) > 0; level++ {
+		var next []string
+		for _, parent := range parents {
+			if level < depth || len(resourceTypes) == 0 {
+				resources, err := client.ReadAllResourceMetadata(resourceTreeFilter(parent, "child", nil))
+				if err != nil {
+					return nil, err
+				}
+				for _, child := range resources {
+					next = append(next, child.Turbot.Id)
+					if len(resourceTypes) == 0 {
+						children = append(children, flattenResourceTreeItem(child, level))
+					}
+				}
+			}
+			if len(resourceTypes) > 0 {
+				resources, err := client.ReadAllResourceMetadata(resourceTreeFilter(parent, "child", resourceTypes))
+				if err != nil {
+					return nil, err
+				}
+				for _, child := range resources {
+					children = append(children, flattenResourceTreeItem(child, level))
+				}
+			}
+		}
+		parents = next
+	}
+	return children, nil
+}
+
+// resourceTreeListSchema is the schema of the resources a tree data source returns
+func resourceTreeListSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Computed: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"id": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"aka": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"type": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"title": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"parent_id": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"depth": {
+					Type:     schema.TypeInt,
+					Computed: true,
+				},
+			},
+		},
+	}
+}
+
+// resourceTreeFilter is the filter for the resources at a level of the tree relative to resourceId,
+// optionally of the given types
+func resourceTreeFilter(resourceId, level string, resourceTypes []interface{}) string {
+	filter := fmt.Sprintf("resourceId:%s level:%s", resourceId, level)
+	if len(resourceTypes) > 0 {
+		var uris []string
+		for _, resourceType := range resourceTypes {
+			uris = append(uris, resourceType.(string))
+		}
+		filter += " resourceTypeId:" + strings.Join(uris, ",")
+	}
+	return filter
+}
+
+// resourcePath is the ids of the resource and its ancestors, from the Turbot root down
+func resourcePath(resource *apiClient.Resource) []string {
+	if resource.Turbot.Path == "" {
+		return nil
+	}
+	return strings.Split(resource.Turbot.Path, ".")
+}
+
+// flattenResourceTreeItem converts a resource into the simple types the tree list schema holds. Its
+// aka is the first of its akas, or its id when it has none.
+func flattenResourceTreeItem(resource apiClient.Resource, depth int) map[string]interface{} {
+	aka := resource.Turbot.Id
+	if len(resource.Turbot.Akas) > 0 {
+		aka = resource.Turbot.Akas[0]
+	}
+	return map[string]interface{}{
+		"id":        resource.Turbot.Id,
+		"aka":       aka,
+		"type":      resource.Type.Uri,
+		"title":     resource.Turbot.Title,
+		"parent_id": resource.Turbot.ParentId,
+		"depth":     depth,
+	}
+}
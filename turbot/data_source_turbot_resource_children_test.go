@@ -0,0 +1,74 @@
+package turbot
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform/helper/resource"
+	"testing"
+)
+
+func TestAccResourceChildrenDataSource_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceChildrenDataSourceConfig(1, ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.turbot_resource_children.test", "children.#", "1"),
+					resource.TestCheckResourceAttr("data.turbot_resource_children.test", "children.0.aka", "arn:aws:::713469427990"),
+					resource.TestCheckResourceAttr("data.turbot_resource_children.test", "children.0.type", "tmod:@turbot/aws#/resource/types/account"),
+					resource.TestCheckResourceAttr("data.turbot_resource_children.test", "children.0.parent_id", "178806515404441"),
+					resource.TestCheckResourceAttr("data.turbot_resource_children.test", "children.0.depth", "1"),
+				),
+			},
+			{
+				Config: testAccResourceChildrenDataSourceConfig(0, ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.turbot_resource_children.test", "children.#", "3"),
+					resource.TestCheckResourceAttr("data.turbot_resource_children.test", "children.1.aka", "arn:aws::us-east-2:713469427990"),
+					resource.TestCheckResourceAttr("data.turbot_resource_children.test", "children.2.depth", "3"),
+				),
+			},
+			{
+				// the tree is walked to the depth, and no further
+				Config: testAccResourceChildrenDataSourceConfig(2, ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.turbot_resource_children.test", "children.#", "2"),
+					resource.TestCheckResourceAttr("data.turbot_resource_children.test", "children.0.depth", "1"),
+					resource.TestCheckResourceAttr("data.turbot_resource_children.test", "children.1.aka", "arn:aws::us-east-2:713469427990"),
+					resource.TestCheckResourceAttr("data.turbot_resource_children.test", "children.1.depth", "2"),
+				),
+			},
+			{
+				// the account is not of the type, but is still walked through to its regions
+				Config: testAccResourceChildrenDataSourceConfig(2, "tmod:@turbot/aws#/resource/types/region"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.turbot_resource_children.test", "children.#", "1"),
+					resource.TestCheckResourceAttr("data.turbot_resource_children.test", "children.0.depth", "2"),
+				),
+			},
+			{
+				Config: testAccResourceChildrenDataSourceConfig(0, "tmod:@turbot/aws#/resource/types/region"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.turbot_resource_children.test", "children.#", "1"),
+					resource.TestCheckResourceAttr("data.turbot_resource_children.test", "children.0.depth", "2"),
+				),
+			},
+		},
+	})
+}
+
+// config
+func testAccResourceChildrenDataSourceConfig(depth int, resourceType string) string {
+	resourceTypes := ""
+	if resourceType != "" {
+		resourceTypes = fmt.Sprintf(`resource_types = ["%s"]`, resourceType)
+	}
+	return fmt.Sprintf(`
+data "turbot_resource_children" "test" {
+  resource = "178806515404441"
+  depth    = %d
+  %s
+}
+`, depth, resourceTypes)
+}
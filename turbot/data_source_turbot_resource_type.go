@@ -0,0 +1,96 @@
+package turbot
+
+import (
+	"errors"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/turbot/terraform-provider-turbot/apiClient"
+	"github.com/turbot/terraform-provider-turbot/helpers"
+	"strings"
+)
+
+func dataSourceTurbotResourceType() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceTurbotResourceTypeRead,
+		Schema: map[string]*schema.Schema{
+			"uri": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"akas": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"title": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"category": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"mod_uri": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"create_schema": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"update_schema": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"parent_types": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+func dataSourceTurbotResourceTypeRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*apiClient.Client)
+	resourceTypeUri := d.Get("uri").(string)
+
+	resourceType, err := client.ReadResourceType(resourceTypeUri)
+	if err != nil {
+		if errors.Is(err, apiClient.ErrNotFound) {
+			// resource type was not found - clear id
+			d.SetId("")
+		}
+		return err
+	}
+
+	createSchema, err := helpers.InterfaceToStringOrJson(resourceType.CreateSchema)
+	if err != nil {
+		return err
+	}
+	updateSchema, err := helpers.InterfaceToStringOrJson(resourceType.UpdateSchema)
+	if err != nil {
+		return err
+	}
+
+	uri := definitionUri(resourceTypeUri, resourceType.Turbot.Akas)
+
+	d.SetId(resourceType.Turbot.Id)
+	d.Set("uri", uri)
+	d.Set("akas", resourceType.Turbot.Akas)
+	d.Set("title", resourceType.Title)
+	d.Set("description", resourceType.Description)
+	d.Set("category", resourceType.Category)
+	d.Set("mod_uri", strings.SplitN(uri, "#", 2)[0])
+	d.Set("create_schema", createSchema)
+	d.Set("update_schema", updateSchema)
+	d.Set("parent_types", typeUris(resourceType.Parent))
+	return nil
+}
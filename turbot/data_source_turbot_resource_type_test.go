@@ -0,0 +1,38 @@
+package turbot
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform/helper/resource"
+	"testing"
+)
+
+func TestAccResourceTypeDataSource_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceTypeDataSourceConfig(folderType),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.turbot_resource_type.test", "uri", folderType),
+					resource.TestCheckResourceAttr("data.turbot_resource_type.test", "akas.0", folderType),
+					resource.TestCheckResourceAttr("data.turbot_resource_type.test", "mod_uri", "tmod:@turbot/turbot"),
+					resource.TestCheckResourceAttr("data.turbot_resource_type.test", "category", "tmod:@turbot/turbot#/resource/categories/folder"),
+					resource.TestCheckResourceAttr("data.turbot_resource_type.test", "parent_types.#", "2"),
+					resource.TestCheckResourceAttr("data.turbot_resource_type.test", "parent_types.1", folderType),
+					resource.TestCheckResourceAttrSet("data.turbot_resource_type.test", "create_schema"),
+					resource.TestCheckResourceAttrSet("data.turbot_resource_type.test", "update_schema"),
+				),
+			},
+		},
+	})
+}
+
+// config
+func testAccResourceTypeDataSourceConfig(resourceTypeUri string) string {
+	return fmt.Sprintf(`
+data "turbot_resource_type" "test" {
+  uri = "%s"
+}
+`, resourceTypeUri)
+}
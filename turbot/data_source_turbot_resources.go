@@ -0,0 +1,111 @@
+package turbot
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/turbot/terraform-provider-turbot/apiClient"
+	"github.com/turbot/terraform-provider-turbot/helpers"
+)
+
+func dataSourceTurbotResources() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceTurbotResourcesRead,
+		Schema: map[string]*schema.Schema{
+			"filter": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"resources": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"akas": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"parent_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"tags": {
+							Type:     schema.TypeMap,
+							Computed: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"data": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"metadata": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceTurbotResourcesRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*apiClient.Client)
+	filter := d.Get("filter").(string)
+
+	resources, err := client.ReadAllResources(filter)
+	if err != nil {
+		return err
+	}
+
+	var items []map[string]interface{}
+	for _, resource := range resources {
+		item, err := flattenResourceListItem(resource)
+		if err != nil {
+			return err
+		}
+		items = append(items, item)
+	}
+
+	d.SetId(filter)
+	return d.Set("resources", items)
+}
+
+// convert a resource into the simple types the list schema holds - data and metadata as JSON, as the
+// turbot_resource data source returns them
+func flattenResourceListItem(resource apiClient.Resource) (map[string]interface{}, error) {
+	data, err := helpers.MapToJsonString(resource.Data)
+	if err != nil {
+		return nil, err
+	}
+	var metadata string
+	if resource.Turbot.Custom != nil {
+		if metadata, err = helpers.MapToJsonString(resource.Turbot.Custom); err != nil {
+			return nil, err
+		}
+	}
+	tags, err := helpers.ConvertToStringMap(resource.Turbot.Tags)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"id":        resource.Turbot.Id,
+		"akas":      resource.Turbot.Akas,
+		"type":      resource.Type.Uri,
+		"parent_id": resource.Turbot.ParentId,
+		"tags":      tags,
+		"data":      data,
+		"metadata":  metadata,
+	}, nil
+}
@@ -0,0 +1,57 @@
+package turbot
+
+import (
+	"github.com/hashicorp/terraform/helper/resource"
+	"testing"
+)
+
+func TestAccResourcesDataSource_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourcesDataSourceConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.turbot_resources.test", "resources.#", "2"),
+					resource.TestCheckResourceAttr("data.turbot_resources.test", "resources.0.type", "tmod:@turbot/turbot#/resource/types/folder"),
+					resource.TestCheckResourceAttrPair("data.turbot_resources.test", "resources.0.parent_id", "turbot_folder.parent", "id"),
+					resource.TestCheckResourceAttrPair("data.turbot_resources.test", "resources.0.id", "turbot_folder.child_a", "id"),
+					resource.TestCheckResourceAttr("data.turbot_resources.test", "resources.0.tags.env", "prod"),
+					resource.TestCheckResourceAttrPair("data.turbot_resources.test", "resources.1.id", "turbot_folder.child_b", "id"),
+				),
+			},
+		},
+	})
+}
+
+func testAccResourcesDataSourceConfig() string {
+	return `
+resource "turbot_folder" "parent" {
+	parent = "tmod:@turbot/turbot#/"
+	title = "provider_test_resources"
+	description = "test folder for guardrails terraform provider"
+}
+
+resource "turbot_folder" "child_a" {
+	parent = turbot_folder.parent.id
+	title = "provider_test_resources_a"
+	description = "test folder for guardrails terraform provider"
+	tags = {
+		env = "prod"
+	}
+}
+
+resource "turbot_folder" "child_b" {
+	parent = turbot_folder.child_a.parent
+	title = "provider_test_resources_b"
+	description = "test folder for guardrails terraform provider"
+	depends_on = [turbot_folder.child_a]
+}
+
+# the filter refers to child_b, rather than using depends_on, so it is read once both children exist
+data "turbot_resources" "test" {
+	filter = "resource:${turbot_folder.child_b.parent} level:descendant resourceType:tmod:@turbot/turbot#/resource/types/folder"
+}
+`
+}
@@ -0,0 +1,147 @@
+package turbot
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/turbot/terraform-provider-turbot/apiClient"
+)
+
+func dataSourceTurbotWorkspace() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceTurbotWorkspaceRead,
+		Schema: map[string]*schema.Schema{
+			"url": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"version": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			// the parts of the version, for comparing with a minimum version in a precondition
+			"version_major": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"version_minor": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"version_patch": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"profile_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"profile_email": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"profile_directory": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			// the grants on the root the profile holds, whether made to it or to a group profile it is a
+			// member of
+			"root_permissions": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"permission_type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"permission_level": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceTurbotWorkspaceRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*apiClient.Client)
+
+	version, err := client.GetTurbotWorkspaceVersion()
+	if err != nil {
+		return err
+	}
+	actor, err := client.ReadActor()
+	if err != nil {
+		return err
+	}
+
+	// an access key that belongs to no profile has no grants of its own to report
+	var rootPermissions []map[string]interface{}
+	profile := actor.Identity.Turbot
+	if profile.Id != "" {
+		if rootPermissions, err = readRootPermissions(client, profile.Id); err != nil {
+			return err
+		}
+	}
+
+	d.SetId(client.Workspace)
+	d.Set("url", client.Workspace)
+	d.Set("version", version.String())
+	d.Set("version_major", int(version.Major))
+	d.Set("version_minor", int(version.Minor))
+	d.Set("version_patch", int(version.Patch))
+	d.Set("profile_id", profile.Id)
+	d.Set("profile_email", actor.Identity.Email)
+	d.Set("profile_directory", profile.ParentId)
+	d.Set("root_permissions", rootPermissions)
+	return nil
+}
+
+const groupProfileType = "tmod:@turbot/turbot-iam#/resource/types/groupProfile"
+
+// readRootPermissions reads the active grants on the Turbot root that the profile holds: those made to
+// the profile itself, and those made to a group profile it is a member of - SSO and directory users
+// often hold all their access through groups. There are few grants on the root, so all of them are
+// read, and each other identity they are made to is checked once for a group the profile is in.
+func readRootPermissions(client *apiClient.Client, profileId string) ([]map[string]interface{}, error) {
+	activeGrants, err := client.ReadAllActiveGrants("resource:tmod:@turbot/turbot#/ level:self")
+	if err != nil {
+		return nil, err
+	}
+	holds := map[string]bool{profileId: true}
+	var rootPermissions []map[string]interface{}
+	for _, activeGrant := range activeGrants {
+		identityId := activeGrant.Grant.Turbot.ProfileId
+		held, checked := holds[identityId]
+		if !checked {
+			if held, err = isGroupProfileMember(client, identityId, profileId); err != nil {
+				return nil, err
+			}
+			holds[identityId] = held
+		}
+		if held {
+			rootPermissions = append(rootPermissions, map[string]interface{}{
+				"permission_type":  activeGrant.Grant.Type.Uri,
+				"permission_level": activeGrant.Grant.Level.Uri,
+			})
+		}
+	}
+	return rootPermissions, nil
+}
+
+// isGroupProfileMember returns whether the identity is a group profile with the profile as a member
+func isGroupProfileMember(client *apiClient.Client, identityId, profileId string) (bool, error) {
+	identity, err := client.ReadResource(identityId, nil)
+	if err != nil {
+		return false, err
+	}
+	if identity.Type.Uri != groupProfileType {
+		return false, nil
+	}
+	member, err := client.ReadGroupProfileMember(identityId, profileId)
+	if err != nil {
+		return false, err
+	}
+	return member != nil, nil
+}
@@ -0,0 +1,84 @@
+package turbot
+
+import (
+	"github.com/hashicorp/terraform/helper/resource"
+	"regexp"
+	"testing"
+)
+
+func TestAccWorkspaceDataSource_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccWorkspaceDataSourceConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.turbot_workspace.test", "url"),
+					resource.TestMatchResourceAttr("data.turbot_workspace.test", "version", regexp.MustCompile(`^5\.\d+\.\d+`)),
+					resource.TestCheckResourceAttr("data.turbot_workspace.test", "version_major", "5"),
+					resource.TestCheckResourceAttrSet("data.turbot_workspace.test", "profile_id"),
+					resource.TestCheckResourceAttrSet("data.turbot_workspace.test", "profile_email"),
+					resource.TestCheckResourceAttrSet("data.turbot_workspace.test", "profile_directory"),
+					resource.TestCheckResourceAttrSet("data.turbot_workspace.test", "root_permissions.0.permission_level"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccWorkspaceDataSource_GroupGrant(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				// make the provider's profile a member of a group with an operator grant on the root
+				Config: testAccWorkspaceDataSourceGroupGrantConfig,
+			},
+			{
+				// then read the workspace, once the grant exists
+				Config: testAccWorkspaceDataSourceGroupGrantConfig + testAccWorkspaceDataSourceConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.turbot_workspace.test", "root_permissions.#", "2"),
+					resource.TestCheckResourceAttr("data.turbot_workspace.test", "root_permissions.0.permission_level", "tmod:@turbot/turbot-iam#/permission/levels/owner"),
+					resource.TestCheckResourceAttr("data.turbot_workspace.test", "root_permissions.1.permission_level", "tmod:@turbot/turbot-iam#/permission/levels/operator"),
+				),
+			},
+		},
+	})
+}
+
+// config
+func testAccWorkspaceDataSourceConfig() string {
+	return `
+data "turbot_workspace" "test" {}
+`
+}
+
+const testAccWorkspaceDataSourceGroupGrantConfig = `
+data "turbot_workspace" "caller" {}
+
+resource "turbot_group_profile" "test" {
+  directory        = data.turbot_workspace.caller.profile_directory
+  title            = "provider_test_workspace"
+  group_profile_id = "provider-test-workspace"
+}
+
+resource "turbot_group_membership" "test" {
+  group_profile = turbot_group_profile.test.id
+  profile       = data.turbot_workspace.caller.profile_id
+}
+
+resource "turbot_grant" "test" {
+  resource = "tmod:@turbot/turbot#/"
+  type     = "tmod:@turbot/turbot-iam#/permission/types/turbot"
+  level    = "tmod:@turbot/turbot-iam#/permission/levels/operator"
+  identity = turbot_group_profile.test.id
+}
+
+resource "turbot_grant_activation" "test" {
+  resource = turbot_grant.test.resource
+  grant    = turbot_grant.test.id
+}
+`
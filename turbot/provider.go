@@ -41,6 +41,31 @@ func Provider() terraform.ResourceProvider {
 				Type:     schema.TypeString,
 				Optional: true,
 			},
+			// max_retries is how many times a transient API failure (429, 502, 503, 504 or a dropped
+			// connection) is retried before the error is surfaced. Unset falls back to
+			// apiClient.DefaultMaxRetries; 0 disables retries.
+			"max_retries": {
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			// retry_max_backoff caps a single wait between retries, including one requested by a
+			// Retry-After header. Accepts a Go duration string. Empty falls back to
+			// apiClient.DefaultRetryMaxBackoff.
+			"retry_max_backoff": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			// max_requests_per_second and max_concurrent_requests throttle the provider against a
+			// shared workspace. Both default to 0, meaning unlimited, which preserves the previous
+			// behaviour of sending requests at Terraform's full parallelism.
+			"max_requests_per_second": {
+				Type:     schema.TypeFloat,
+				Optional: true,
+			},
+			"max_concurrent_requests": {
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
 		},
 
 		ResourcesMap: map[string]*schema.Resource{
@@ -50,27 +75,41 @@ func Provider() terraform.ResourceProvider {
 			"turbot_google_directory":        resourceGoogleDirectory(),
 			"turbot_grant":                   resourceTurbotGrant(),
 			"turbot_grant_activation":        resourceTurbotGrantActivation(),
+			"turbot_group_members":           resourceTurbotGroupMembers(),
+			"turbot_group_membership":        resourceTurbotGroupMembership(),
+			"turbot_group_profile":           resourceTurbotGroupProfile(),
 			"turbot_ldap_directory":          resourceTurbotLdapDirectory(),
 			"turbot_local_directory":         resourceTurbotLocalDirectory(),
 			"turbot_local_directory_user":    resourceTurbotLocalDirectoryUser(),
 			"turbot_mod":                     resourceTurbotMod(),
 			"turbot_policy_pack":             resourceTurbotPolicyPack(),
 			"turbot_policy_pack_attachment":  resourceTurbotPolicyPackAttachment(),
+			"turbot_policy_pack_attachments": resourceTurbotPolicyPackAttachments(),
 			"turbot_policy_setting":          resourceTurbotPolicySetting(),
+			"turbot_policy_settings":         resourceTurbotPolicySettings(),
 			"turbot_profile":                 resourceTurbotProfile(),
 			"turbot_resource":                resourceTurbotResource(),
+			"turbot_resource_grants":         resourceTurbotResourceGrants(),
 			"turbot_saml_directory":          resourceTurbotSamlDirectory(),
 			"turbot_shadow_resource":         resourceTurbotShadowResource(),
 			"turbot_smart_folder":            resourceTurbotSmartFolder(),
 			"turbot_smart_folder_attachment": resourceTurbotSmartFolderAttachemnt(),
 			"turbot_turbot_directory":        resourceTurbotTurbotDirectory(),
 			"turbot_watch":                   resourceTurbotWatch(),
-			//"turbot_group_profile":           resourceTurbotGroupProfile(),
 		},
 		DataSourcesMap: map[string]*schema.Resource{
-			"turbot_control":      dataSourceTurbotControl(),
-			"turbot_policy_value": dataSourceTurbotPolicyValue(),
-			"turbot_resource":     dataSourceTurbotResource(),
+			"turbot_control":            dataSourceTurbotControl(),
+			"turbot_controls":           dataSourceTurbotControls(),
+			"turbot_mod_versions":       dataSourceTurbotModVersions(),
+			"turbot_policy_settings":    dataSourceTurbotPolicySettings(),
+			"turbot_policy_type":        dataSourceTurbotPolicyType(),
+			"turbot_policy_value":       dataSourceTurbotPolicyValue(),
+			"turbot_resource":           dataSourceTurbotResource(),
+			"turbot_resource_ancestors": dataSourceTurbotResourceAncestors(),
+			"turbot_resource_children":  dataSourceTurbotResourceChildren(),
+			"turbot_resource_type":      dataSourceTurbotResourceType(),
+			"turbot_resources":          dataSourceTurbotResources(),
+			"turbot_workspace":          dataSourceTurbotWorkspace(),
 		},
 
 		ConfigureFunc: providerConfigure,
@@ -101,6 +140,35 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 		config.RequestTimeout = timeout
 	}
 
+	// max_retries uses GetOkExists because 0 is a meaningful value (no retries), which GetOk would
+	// report as unset.
+	if raw, ok := d.GetOkExists("max_retries"); ok {
+		maxRetries := raw.(int)
+		if maxRetries < 0 {
+			return nil, fmt.Errorf("invalid max_retries %d: must not be negative", maxRetries)
+		}
+		config.MaxRetries = &maxRetries
+	}
+	if raw := d.Get("retry_max_backoff").(string); raw != "" {
+		backoff, err := time.ParseDuration(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid retry_max_backoff %q: %s", raw, err.Error())
+		}
+		if backoff <= 0 {
+			return nil, fmt.Errorf("invalid retry_max_backoff %q: must be positive", raw)
+		}
+		config.RetryMaxBackoff = backoff
+	}
+
+	config.MaxRequestsPerSecond = d.Get("max_requests_per_second").(float64)
+	if config.MaxRequestsPerSecond < 0 {
+		return nil, fmt.Errorf("invalid max_requests_per_second %v: must not be negative", config.MaxRequestsPerSecond)
+	}
+	config.MaxConcurrentRequests = d.Get("max_concurrent_requests").(int)
+	if config.MaxConcurrentRequests < 0 {
+		return nil, fmt.Errorf("invalid max_concurrent_requests %d: must not be negative", config.MaxConcurrentRequests)
+	}
+
 	client, err := apiClient.CreateClient(config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create client: %s", err.Error())
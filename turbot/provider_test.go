@@ -1,9 +1,14 @@
 package turbot
 
 import (
+	"fmt"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
 	"github.com/turbot/terraform-provider-turbot/apiClient"
+	"github.com/turbot/terraform-provider-turbot/testing/cassette"
+	"github.com/turbot/terraform-provider-turbot/testing/mockserver"
+	"os"
+	"strings"
 	"testing"
 )
 
@@ -17,6 +22,72 @@ func init() {
 	}
 }
 
+// TestMain chooses what the acceptance tests run against:
+//   - TURBOT_MOCK set: an in-memory mock workspace, with no network and no live workspace - `make testacc-mock`
+//   - TURBOT_CASSETTE set: traffic recorded to, or replayed from, that cassette file, as TURBOT_CASSETTE_MODE
+//     is `record` or `replay` - `make testacc-record` and `make testacc-replay`. Replay needs no credentials.
+//     TURBOT_CASSETTE_REDACT is a comma separated list of further values to keep out of the cassette.
+//   - otherwise the workspace the TURBOT_* credentials point at, as before
+//
+// A recording is made against the mock when TURBOT_MOCK is also set, otherwise against the live workspace.
+func TestMain(m *testing.M) {
+	var server *mockserver.Server
+	if os.Getenv("TURBOT_MOCK") != "" {
+		server = mockserver.New()
+		os.Setenv("TURBOT_ACCESS_KEY", server.AccessKey)
+		os.Setenv("TURBOT_SECRET_KEY", server.SecretKey)
+		os.Setenv("TURBOT_WORKSPACE", server.Workspace())
+		os.Unsetenv("TURBOT_PROFILE")
+	}
+
+	var recorder *cassette.Recorder
+	if path := os.Getenv("TURBOT_CASSETTE"); path != "" {
+		mode := cassette.Replay
+		switch os.Getenv("TURBOT_CASSETTE_MODE") {
+		case "", "replay":
+			// the cassette answers every request - the credentials only need to be well formed
+			os.Setenv("TURBOT_ACCESS_KEY", "replay-access-key")
+			os.Setenv("TURBOT_SECRET_KEY", "replay-secret-key")
+			os.Setenv("TURBOT_WORKSPACE", "https://replay.invalid")
+			os.Unsetenv("TURBOT_PROFILE")
+		case "record":
+			mode = cassette.Record
+		default:
+			fmt.Fprintf(os.Stderr, "TURBOT_CASSETTE_MODE must be 'record' or 'replay', got '%s'\n", os.Getenv("TURBOT_CASSETTE_MODE"))
+			os.Exit(1)
+		}
+		var err error
+		if recorder, err = cassette.New(path, mode); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		recorder.Redact(os.Getenv("TURBOT_SECRET_KEY"))
+		if values := os.Getenv("TURBOT_CASSETTE_REDACT"); values != "" {
+			recorder.Redact(strings.Split(values, ",")...)
+		}
+		apiClient.Transport = recorder
+	}
+
+	code := m.Run()
+
+	if recorder != nil {
+		if err := recorder.Save(); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to save cassette: %s\n", err.Error())
+			code = 1
+		}
+		if misses := recorder.Misses(); len(misses) > 0 {
+			fmt.Fprintf(os.Stderr, "%d requests were not in the cassette - re-record it:\n", len(misses))
+			for _, miss := range misses {
+				fmt.Fprintln(os.Stderr, "  "+miss)
+			}
+		}
+	}
+	if server != nil {
+		server.Close()
+	}
+	os.Exit(code)
+}
+
 func TestProvider(t *testing.T) {
 	if err := Provider().(*schema.Provider).InternalValidate(); err != nil {
 		t.Fatalf("err: %s", err)
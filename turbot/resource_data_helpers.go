@@ -48,6 +48,7 @@ func mapFromResourceDataWithPropertyMap(d *schema.ResourceData, terraformToTurbo
 }
 
 // given a resource aka, fetch all akas for the resource and store in resourceData using 'propertyName'
+// the lookup is cached for the life of the provider process - see apiClient.GetResourceAkas
 func storeAkas(aka, propertyName string, d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*apiClient.Client)
 	akas, err := client.GetResourceAkas(aka)
@@ -58,3 +59,38 @@ func storeAkas(aka, propertyName string, d *schema.ResourceData, meta interface{
 	d.Set(propertyName, akas)
 	return nil
 }
+
+// createOnResource creates a resource which manages a collection on another resource - the members of a
+// group profile, say, or the grants on a folder. The other resource is resolved first, so the id is its
+// id whether the config gave an id or an aka. apply then makes the collection match the config, and
+// read stores the result.
+func createOnResource(d *schema.ResourceData, meta interface{}, aka string, apply func(client *apiClient.Client, id string) error, read schema.ReadFunc) error {
+	client := meta.(*apiClient.Client)
+	resource, err := client.ReadResource(aka, nil)
+	if err != nil {
+		return err
+	}
+	id := resource.Turbot.Id
+	if err := apply(client, id); err != nil {
+		return err
+	}
+	d.SetId(id)
+	return read(d, meta)
+}
+
+// configuredAka returns the id or aka by which the configured values name a resource, or its id if
+// none does. A collection is stored as the config names its items, so a config which names them by aka
+// does not diff against the ids the API returns.
+func configuredAka(resource apiClient.TurbotResourceMetadata, configured []interface{}) string {
+	for _, value := range configured {
+		if value.(string) == resource.Id {
+			return resource.Id
+		}
+		for _, aka := range resource.Akas {
+			if value.(string) == aka {
+				return aka
+			}
+		}
+	}
+	return resource.Id
+}
@@ -1,9 +1,9 @@
 package turbot
 
 import (
+	"errors"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/turbot/terraform-provider-turbot/apiClient"
-	"github.com/turbot/terraform-provider-turbot/errors"
 )
 
 var controlProperties = map[string]string{
@@ -157,14 +157,14 @@ func resourceTurbotControlMuteCreate(d *schema.ResourceData, meta interface{}) e
 func resourceTurbotControlMuteRead(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*apiClient.Client)
 
-	// Get the control id
-	controlId := d.Get("control_id").(string)
+	// the id is the control id - control_id is not yet set on import
+	controlId := d.Id()
 
-	control, err := client.ReadControl(controlId)
+	control, err := client.ReadControlById(controlId)
 	if err != nil {
-		if errors.NotFoundError(err) {
+		if errors.Is(err, apiClient.ErrNotFound) {
 			// control was not found - clear id
-			d.Set("id", "")
+			d.SetId("")
 		}
 		return err
 	}
@@ -174,10 +174,8 @@ func resourceTurbotControlMuteRead(d *schema.ResourceData, meta interface{}) err
 	d.Set("control_type", control.Type.Uri)
 	d.Set("state", control.State)
 
-	if d.Get("resource") != nil {
-		resourceID := d.Get("resource").(string)
-		d.Set("resource", resourceID)
-	} else {
+	// keep a configured resource aka, otherwise (e.g. on import) use the resource id
+	if d.Get("resource").(string) == "" {
 		d.Set("resource", control.Turbot["resourceId"])
 	}
 
@@ -4,10 +4,10 @@ import (
 	"fmt"
 	"testing"
 
+	"errors"
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/terraform"
 	"github.com/turbot/terraform-provider-turbot/apiClient"
-	"github.com/turbot/terraform-provider-turbot/errors"
 )
 
 // test suites
@@ -79,7 +79,7 @@ func testAccCheckControlMuteExists(resource string) resource.TestCheckFunc {
 			return fmt.Errorf("no Record ID is set")
 		}
 		client := testAccProvider.Meta().(*apiClient.Client)
-		_, err := client.ReadControl(rs.Primary.ID)
+		_, err := client.ReadControlById(rs.Primary.ID)
 		if err != nil {
 			return fmt.Errorf("error fetching item with resource %s. %s", resource, err)
 		}
@@ -91,12 +91,16 @@ func testAccCheckControlMuteDestroy(s *terraform.State) error {
 	client := testAccProvider.Meta().(*apiClient.Client)
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type == "turbot_control_mute" {
-			_, err := client.ReadControl(rs.Primary.ID)
-			if err == nil {
-				return fmt.Errorf("alert still exists")
+			// unmuting leaves the control in place, without a mute
+			control, err := client.ReadControlById(rs.Primary.ID)
+			if err != nil {
+				if errors.Is(err, apiClient.ErrNotFound) {
+					continue
+				}
+				return err
 			}
-			if !errors.NotFoundError(err) {
-				return fmt.Errorf("expected 'not found' error, got %s", err)
+			if mute, _ := control.Mute.(map[string]interface{}); len(mute) > 0 {
+				return fmt.Errorf("control %s is still muted", rs.Primary.ID)
 			}
 		}
 	}
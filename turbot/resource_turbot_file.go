@@ -3,9 +3,9 @@ package turbot
 import (
 	"fmt"
 
+	"errors"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/turbot/terraform-provider-turbot/apiClient"
-	"github.com/turbot/terraform-provider-turbot/errors"
 	"github.com/turbot/terraform-provider-turbot/helpers"
 )
 
@@ -111,7 +111,7 @@ func resourceTurbotFileRead(d *schema.ResourceData, meta interface{}) error {
 	id := d.Id()
 	resource, err := client.ReadFullResource(id)
 	if err != nil {
-		if errors.NotFoundError(err) {
+		if errors.Is(err, apiClient.ErrNotFound) {
 			// resource was not found - clear id
 			d.SetId("")
 		}
@@ -1,11 +1,11 @@
 package turbot
 
 import (
+	"errors"
 	"fmt"
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/terraform"
 	"github.com/turbot/terraform-provider-turbot/apiClient"
-	"github.com/turbot/terraform-provider-turbot/errors"
 	"github.com/turbot/terraform-provider-turbot/helpers"
 	"testing"
 )
@@ -139,7 +139,7 @@ func testAccCheckFileResourceDestroy(s *terraform.State) error {
 			if err == nil {
 				return fmt.Errorf("Alert still exists")
 			}
-			if !errors.NotFoundError(err) {
+			if !errors.Is(err, apiClient.ErrNotFound) {
 				return fmt.Errorf("expected 'not found' error, got %s", err)
 			}
 		}
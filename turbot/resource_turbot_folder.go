@@ -1,9 +1,9 @@
 package turbot
 
 import (
+	"errors"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/turbot/terraform-provider-turbot/apiClient"
-	"github.com/turbot/terraform-provider-turbot/errors"
 )
 
 // properties which must be passed to a create/update call
@@ -119,7 +119,7 @@ func resourceTurbotFolderRead(d *schema.ResourceData, meta interface{}) error {
 
 	folder, err := client.ReadFolder(id)
 	if err != nil {
-		if errors.NotFoundError(err) {
+		if errors.Is(err, apiClient.ErrNotFound) {
 			// folder was not found - clear id
 			d.SetId("")
 		}
@@ -1,11 +1,11 @@
 package turbot
 
 import (
+	"errors"
 	"fmt"
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/terraform"
 	"github.com/turbot/terraform-provider-turbot/apiClient"
-	"github.com/turbot/terraform-provider-turbot/errors"
 	"testing"
 )
 
@@ -226,7 +226,7 @@ func testAccCheckFolderDestroy(s *terraform.State) error {
 			if err == nil {
 				return fmt.Errorf("alert still exists")
 			}
-			if !errors.NotFoundError(err) {
+			if !errors.Is(err, apiClient.ErrNotFound) {
 				return fmt.Errorf("expected 'not found' error, got %s", err)
 			}
 		}
@@ -1,9 +1,9 @@
 package turbot
 
 import (
+	"errors"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/turbot/terraform-provider-turbot/apiClient"
-	"github.com/turbot/terraform-provider-turbot/errors"
 )
 
 // map of Terraform properties to Turbot properties that we pass to create and update mutations
@@ -130,7 +130,7 @@ func resourceTurbotGrantRead(d *schema.ResourceData, meta interface{}) error {
 
 	Grant, err := client.ReadGrant(id)
 	if err != nil {
-		if errors.NotFoundError(err) {
+		if errors.Is(err, apiClient.ErrNotFound) {
 			// Grant was not found - clear id
 			d.SetId("")
 		}
@@ -1,9 +1,9 @@
 package turbot
 
 import (
+	"errors"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/turbot/terraform-provider-turbot/apiClient"
-	"github.com/turbot/terraform-provider-turbot/errors"
 )
 
 var grantActivationInputProperties = []interface{}{"grant", "resource"}
@@ -79,7 +79,7 @@ func resourceTurbotGrantActivateRead(d *schema.ResourceData, meta interface{}) e
 
 	activeGrant, err := client.ReadGrantActivation(id)
 	if err != nil {
-		if errors.NotFoundError(err) {
+		if errors.Is(err, apiClient.ErrNotFound) {
 			// Grant was not found - clear id
 			d.SetId("")
 		}
@@ -97,7 +97,8 @@ func resourceTurbotGrantActivateDelete(d *schema.ResourceData, meta interface{})
 	client := meta.(*apiClient.Client)
 	id := d.Id()
 	err := client.DeleteGrantActivation(id)
-	if err != nil {
+	// deleting a grant deletes its activations, so the activation may already be gone
+	if err != nil && !errors.Is(err, apiClient.ErrNotFound) {
 		return err
 	}
 
@@ -1,11 +1,11 @@
 package turbot
 
 import (
+	"errors"
 	"fmt"
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/terraform"
 	"github.com/turbot/terraform-provider-turbot/apiClient"
-	"github.com/turbot/terraform-provider-turbot/errors"
 	"testing"
 )
 
@@ -96,7 +96,7 @@ func testAccCheckLocalGrantDestroy(s *terraform.State) error {
 			if err == nil {
 				return fmt.Errorf("Alert still exists")
 			}
-			if !errors.NotFoundError(err) {
+			if !errors.Is(err, apiClient.ErrNotFound) {
 				return fmt.Errorf("expected 'not found' error, got %s", err)
 			}
 		}
@@ -136,7 +136,7 @@ func testAccCheckActiveGrantDestroy(s *terraform.State) error {
 		if err == nil {
 			return fmt.Errorf("grant activation %s still exists after destroy", rs.Primary.ID)
 		}
-		if !errors.NotFoundError(err) {
+		if !errors.Is(err, apiClient.ErrNotFound) {
 			return fmt.Errorf("expected 'not found' error, got %s", err)
 		}
 	}
@@ -0,0 +1,160 @@
+package turbot
+
+import (
+	"errors"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/turbot/terraform-provider-turbot/apiClient"
+)
+
+// turbot_group_members is authoritative: it owns every membership of the group profile, removing
+// profiles that were added to the group outside of the config
+func resourceTurbotGroupMembers() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceTurbotGroupMembersCreate,
+		Read:   resourceTurbotGroupMembersRead,
+		Update: resourceTurbotGroupMembersUpdate,
+		Delete: resourceTurbotGroupMembersDelete,
+		Exists: resourceTurbotGroupMembersExists,
+		Importer: &schema.ResourceImporter{
+			State: resourceTurbotGroupMembersImport,
+		},
+		Schema: map[string]*schema.Schema{
+			// aka of the group profile
+			"group_profile": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: suppressIfAkaMatches("group_profile_akas"),
+			},
+			"group_profile_akas": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			// ids or akas of the member profiles - an empty set removes every member
+			"profiles": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+func resourceTurbotGroupMembersExists(d *schema.ResourceData, meta interface{}) (b bool, e error) {
+	client := meta.(*apiClient.Client)
+	id := d.Id()
+	return client.ResourceExists(id)
+}
+
+func resourceTurbotGroupMembersCreate(d *schema.ResourceData, meta interface{}) error {
+	return createOnResource(d, meta, d.Get("group_profile").(string), func(client *apiClient.Client, id string) error {
+		// a new group profile may already have members, added in the console or by its directory
+		members, err := client.ReadGroupProfileMembers(id)
+		if err != nil {
+			return err
+		}
+		return updateGroupMembers(client, id, members, d.Get("profiles").(*schema.Set))
+	}, resourceTurbotGroupMembersRead)
+}
+
+func resourceTurbotGroupMembersRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*apiClient.Client)
+	id := d.Id()
+
+	members, err := client.ReadGroupProfileMembers(id)
+	if err != nil {
+		if errors.Is(err, apiClient.ErrNotFound) {
+			// group profile was not found - clear id
+			d.SetId("")
+		}
+		return err
+	}
+
+	// a member outside of the config is stored by its id, so the plan shows it being removed
+	configured := d.Get("profiles").(*schema.Set).List()
+	var profiles []interface{}
+	for _, member := range members {
+		profiles = append(profiles, configuredAka(member.Turbot, configured))
+	}
+	d.Set("profiles", profiles)
+	d.Set("group_profile", id)
+	// set group_profile_akas property by loading the group profile and fetching the akas
+	return storeAkas(id, "group_profile_akas", d, meta)
+}
+
+func resourceTurbotGroupMembersUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*apiClient.Client)
+	id := d.Id()
+
+	if d.HasChange("profiles") {
+		members, err := client.ReadGroupProfileMembers(id)
+		if err != nil {
+			return err
+		}
+		if err := updateGroupMembers(client, id, members, d.Get("profiles").(*schema.Set)); err != nil {
+			return err
+		}
+	}
+	return resourceTurbotGroupMembersRead(d, meta)
+}
+
+func resourceTurbotGroupMembersDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*apiClient.Client)
+	id := d.Id()
+
+	members, err := client.ReadGroupProfileMembers(id)
+	if err != nil {
+		// deleting the group profile removes its members, so it may already be gone
+		if errors.Is(err, apiClient.ErrNotFound) {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+	if err := updateGroupMembers(client, id, members, &schema.Set{F: schema.HashString}); err != nil {
+		return err
+	}
+
+	// clear the id to show we have deleted
+	d.SetId("")
+	return nil
+}
+
+func resourceTurbotGroupMembersImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if err := resourceTurbotGroupMembersRead(d, meta); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+// updateGroupMembers adds each of the profiles that is not a member of the group profile, and removes
+// each member that is not one of the profiles
+func updateGroupMembers(client *apiClient.Client, groupProfileId string, members []apiClient.Profile, profiles *schema.Set) error {
+	wanted := map[string]bool{}
+	for _, profile := range profiles.List() {
+		wanted[profile.(string)] = true
+	}
+	for _, member := range members {
+		if profile := configuredAka(member.Turbot, profiles.List()); wanted[profile] {
+			// already a member
+			delete(wanted, profile)
+			continue
+		}
+		err := client.RemoveGroupProfileMember(groupProfileId, member.Turbot.Id)
+		// the profile may have been deleted since the members were read
+		if err != nil && !errors.Is(err, apiClient.ErrNotFound) {
+			return err
+		}
+	}
+	for profile := range wanted {
+		if _, err := client.AddGroupProfileMember(groupProfileId, profile); err != nil {
+			return err
+		}
+	}
+	return nil
+}
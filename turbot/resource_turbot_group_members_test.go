@@ -0,0 +1,201 @@
+package turbot
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/turbot/terraform-provider-turbot/apiClient"
+	"strings"
+	"testing"
+)
+
+// test suites
+func TestAccGroupMembers_Basic(t *testing.T) {
+	resourceName := "turbot_group_members.test"
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckGroupMembersDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGroupMembersConfig("turbot_profile.snape.id"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGroupMembersCount(resourceName, 1),
+					resource.TestCheckResourceAttr(resourceName, "profiles.#", "1"),
+					resource.TestCheckResourceAttrPair(resourceName, "group_profile", "turbot_group_profile.test", "id"),
+					// add a member outside of the config, which the next plan must remove
+					testAccAddGroupMember("turbot_group_profile.test", "turbot_profile.lupin"),
+				),
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccGroupMembersConfig("turbot_profile.snape.id"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGroupMembersCount(resourceName, 1),
+					resource.TestCheckResourceAttr(resourceName, "profiles.#", "1"),
+				),
+			},
+			{
+				Config: testAccGroupMembersConfig("turbot_profile.snape.id", "turbot_profile.lupin.id"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGroupMembersCount(resourceName, 2),
+					resource.TestCheckResourceAttr(resourceName, "profiles.#", "2"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config: testAccGroupMembersConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGroupMembersCount(resourceName, 0),
+					resource.TestCheckResourceAttr(resourceName, "profiles.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+// a SAML directory's group profiles usually take their members from the identity provider at login -
+// the resource manages them the same way, whichever kind of directory the group is in
+func TestAccGroupMembers_SamlDirectory(t *testing.T) {
+	resourceName := "turbot_group_members.test"
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckGroupMembersDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGroupMembersSamlConfig("turbot_profile.hermione.id", "turbot_profile.ron.id"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGroupMembersCount(resourceName, 2),
+					resource.TestCheckResourceAttrPair(resourceName, "group_profile", "turbot_group_profile.saml", "id"),
+				),
+			},
+			{
+				Config: testAccGroupMembersSamlConfig("turbot_profile.ron.id"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGroupMembersCount(resourceName, 1),
+					resource.TestCheckResourceAttr(resourceName, "profiles.#", "1"),
+				),
+			},
+		},
+	})
+}
+
+// configs
+func testAccGroupMembersConfig(profiles ...string) string {
+	return testAccGroupMembersProfilesConfig + fmt.Sprintf(`
+resource "turbot_group_members" "test" {
+  group_profile = turbot_group_profile.test.id
+  profiles      = [%s]
+}
+`, strings.Join(profiles, ", "))
+}
+
+// a group profile and two profiles in a SAML directory
+func testAccGroupMembersSamlConfig(profiles ...string) string {
+	return fmt.Sprintf(`
+resource "turbot_saml_directory" "test" {
+  title               = "provider_test_group_members_saml"
+  parent              = "tmod:@turbot/turbot#/"
+  profile_id_template = "{{profile.email}}"
+  description         = "Group Members SAML Testing"
+  entry_point         = "https://example.com/myapp/sso/saml"
+  certificate         = "-----BEGIN CERTIFICATE-----\nMIICiTCCAfICCQD6m7oRw0uXOjANBgkqhkiG9w0BAQUFADCBiDELMAkGA1UEBhMC\n-----END CERTIFICATE-----"
+}
+
+resource "turbot_group_profile" "saml" {
+  directory        = turbot_saml_directory.test.id
+  title            = "provider_test_group_members_saml"
+  group_profile_id = "provider-test-group-members-saml"
+}
+
+resource "turbot_profile" "hermione" {
+  parent            = turbot_saml_directory.test.id
+  title             = "Hermione"
+  email             = "hermione.granger@hogwarts.com"
+  directory_pool_id = "hermione"
+  given_name        = "Hermione"
+  family_name       = "Granger"
+  display_name      = "Hermione Granger"
+  profile_id        = "hermione.granger@hogwarts.com"
+}
+
+resource "turbot_profile" "ron" {
+  parent            = turbot_saml_directory.test.id
+  title             = "Ron"
+  email             = "ron.weasley@hogwarts.com"
+  directory_pool_id = "ron"
+  given_name        = "Ron"
+  family_name       = "Weasley"
+  display_name      = "Ron Weasley"
+  profile_id        = "ron.weasley@hogwarts.com"
+}
+
+resource "turbot_group_members" "test" {
+  group_profile = turbot_group_profile.saml.id
+  profiles      = [%s]
+}
+`, strings.Join(profiles, ", "))
+}
+
+// helper functions
+func testAccCheckGroupMembersCount(resource string, expected int) resource.TestCheckFunc {
+	return func(state *terraform.State) error {
+		rs, ok := state.RootModule().Resources[resource]
+		if !ok {
+			return fmt.Errorf("not found: %s", resource)
+		}
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("no record id is set")
+		}
+		client := testAccProvider.Meta().(*apiClient.Client)
+		members, err := client.ReadGroupProfileMembers(rs.Primary.ID)
+		if err != nil {
+			return fmt.Errorf("error fetching item with resource %s. %s", resource, err)
+		}
+		if len(members) != expected {
+			return fmt.Errorf("expected %d members of group profile %s, got %d", expected, rs.Primary.ID, len(members))
+		}
+		return nil
+	}
+}
+
+// testAccAddGroupMember makes a profile a member of a group profile outside of terraform
+func testAccAddGroupMember(groupProfile, profile string) resource.TestCheckFunc {
+	return func(state *terraform.State) error {
+		ids := map[string]string{}
+		for _, name := range []string{groupProfile, profile} {
+			rs, ok := state.RootModule().Resources[name]
+			if !ok {
+				return fmt.Errorf("not found: %s", name)
+			}
+			ids[name] = rs.Primary.ID
+		}
+		client := testAccProvider.Meta().(*apiClient.Client)
+		_, err := client.AddGroupProfileMember(ids[groupProfile], ids[profile])
+		return err
+	}
+}
+
+func testAccCheckGroupMembersDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*apiClient.Client)
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type == "turbot_group_members" {
+			// the group profile is destroyed too, which also removes its members
+			members, err := client.ReadGroupProfileMembers(rs.Primary.ID)
+			if err != nil && !errors.Is(err, apiClient.ErrNotFound) {
+				return fmt.Errorf("expected 'not found' error, got %s", err)
+			}
+			if len(members) > 0 {
+				return fmt.Errorf("group profile still has members")
+			}
+		}
+	}
+
+	return nil
+}
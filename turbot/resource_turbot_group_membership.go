@@ -0,0 +1,158 @@
+package turbot
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/turbot/terraform-provider-turbot/apiClient"
+	"strings"
+)
+
+func resourceTurbotGroupMembership() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceTurbotGroupMembershipCreate,
+		Read:   resourceTurbotGroupMembershipRead,
+		Delete: resourceTurbotGroupMembershipDelete,
+		Exists: resourceTurbotGroupMembershipExists,
+		Importer: &schema.ResourceImporter{
+			State: resourceTurbotGroupMembershipImport,
+		},
+		Schema: map[string]*schema.Schema{
+			// aka of the group profile
+			"group_profile": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: suppressIfAkaMatches("group_profile_akas"),
+			},
+			// aka of the member profile
+			"profile": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: suppressIfAkaMatches("profile_akas"),
+			},
+			"group_profile_akas": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"profile_akas": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+func resourceTurbotGroupMembershipExists(d *schema.ResourceData, meta interface{}) (b bool, e error) {
+	client := meta.(*apiClient.Client)
+	groupProfileId, profileId, err := parseGroupMembershipId(d.Id())
+	if err != nil {
+		return false, err
+	}
+	member, err := client.ReadGroupProfileMember(groupProfileId, profileId)
+	if err != nil {
+		// a deleted group profile or profile takes its memberships with it
+		if errors.Is(err, apiClient.ErrNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return member != nil, nil
+}
+
+func resourceTurbotGroupMembershipCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*apiClient.Client)
+	groupProfile := d.Get("group_profile").(string)
+	profile := d.Get("profile").(string)
+
+	membership, err := client.AddGroupProfileMember(groupProfile, profile)
+	if err != nil {
+		return err
+	}
+
+	// the id is built from the resolved ids, whether the config gave ids or akas
+	d.SetId(buildGroupMembershipId(membership.Turbot.GroupProfileId, membership.Turbot.ProfileId))
+	return storeGroupMembership(d, meta, membership.Turbot.GroupProfileId, membership.Turbot.ProfileId)
+}
+
+func resourceTurbotGroupMembershipRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*apiClient.Client)
+	groupProfileId, profileId, err := parseGroupMembershipId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	member, err := client.ReadGroupProfileMember(groupProfileId, profileId)
+	if err != nil {
+		if errors.Is(err, apiClient.ErrNotFound) {
+			// group profile or profile was not found - clear id
+			d.SetId("")
+		}
+		return err
+	}
+	if member == nil {
+		// the profile is no longer a member - clear id
+		d.SetId("")
+		return nil
+	}
+	return storeGroupMembership(d, meta, groupProfileId, profileId)
+}
+
+func resourceTurbotGroupMembershipDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*apiClient.Client)
+	groupProfileId, profileId, err := parseGroupMembershipId(d.Id())
+	if err != nil {
+		return err
+	}
+	err = client.RemoveGroupProfileMember(groupProfileId, profileId)
+	// deleting the group profile or the profile removes the membership, so it may already be gone
+	if err != nil && !errors.Is(err, apiClient.ErrNotFound) {
+		return err
+	}
+
+	// clear the id to show we have deleted
+	d.SetId("")
+	return nil
+}
+
+func resourceTurbotGroupMembershipImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	id := d.Id()
+	if err := resourceTurbotGroupMembershipRead(d, meta); err != nil {
+		return nil, err
+	}
+	if d.Id() == "" {
+		return nil, fmt.Errorf("profile is not a member of the group profile: %s", id)
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+// storeGroupMembership assigns the group profile and profile ids, and their akas, into ResourceData
+func storeGroupMembership(d *schema.ResourceData, meta interface{}, groupProfileId, profileId string) error {
+	d.Set("group_profile", groupProfileId)
+	d.Set("profile", profileId)
+	// set the akas properties by loading the group profile and profile and fetching the akas
+	if err := storeAkas(groupProfileId, "group_profile_akas", d, meta); err != nil {
+		return err
+	}
+	return storeAkas(profileId, "profile_akas", d, meta)
+}
+
+func buildGroupMembershipId(groupProfileId, profileId string) string {
+	return groupProfileId + ":" + profileId
+}
+
+// parseGroupMembershipId splits an id of the form group_id:profile_id
+func parseGroupMembershipId(id string) (groupProfileId, profileId string, err error) {
+	segments := strings.Split(id, ":")
+	if len(segments) != 2 || segments[0] == "" || segments[1] == "" {
+		return "", "", fmt.Errorf("invalid group membership id %q, expected group_id:profile_id", id)
+	}
+	return segments[0], segments[1], nil
+}
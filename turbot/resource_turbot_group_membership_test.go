@@ -0,0 +1,120 @@
+package turbot
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/turbot/terraform-provider-turbot/apiClient"
+	"testing"
+)
+
+// test suites
+func TestAccGroupMembership_Basic(t *testing.T) {
+	resourceName := "turbot_group_membership.test"
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckGroupMembershipDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGroupMembershipConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGroupMembershipExists(resourceName),
+					resource.TestCheckResourceAttrPair(resourceName, "group_profile", "turbot_group_profile.test", "id"),
+					resource.TestCheckResourceAttrPair(resourceName, "profile", "turbot_profile.snape", "id"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+// configs
+func testAccGroupMembershipConfig() string {
+	return testAccGroupMembersProfilesConfig + `
+resource "turbot_group_membership" "test" {
+  group_profile = turbot_group_profile.test.id
+  profile       = turbot_profile.snape.id
+}
+`
+}
+
+// a group profile and two profiles in the test directory, shared with the turbot_group_members tests
+const testAccGroupMembersProfilesConfig = `
+resource "turbot_group_profile" "test" {
+  directory        = "184227597889872"
+  title            = "provider_test_group_members"
+  group_profile_id = "provider-test-group-members"
+}
+
+resource "turbot_profile" "snape" {
+  parent            = "184227597889872"
+  title             = "Snape"
+  email             = "severus.snape@hogwarts.com"
+  directory_pool_id = "snape"
+  given_name        = "Severus"
+  family_name       = "Snape"
+  display_name      = "Severus Snape"
+  profile_id        = "severus.snape@hogwarts.com"
+}
+
+resource "turbot_profile" "lupin" {
+  parent            = "184227597889872"
+  title             = "Lupin"
+  email             = "remus.lupin@hogwarts.com"
+  directory_pool_id = "lupin"
+  given_name        = "Remus"
+  family_name       = "Lupin"
+  display_name      = "Remus Lupin"
+  profile_id        = "remus.lupin@hogwarts.com"
+}
+`
+
+// helper functions
+func testAccCheckGroupMembershipExists(resource string) resource.TestCheckFunc {
+	return func(state *terraform.State) error {
+		rs, ok := state.RootModule().Resources[resource]
+		if !ok {
+			return fmt.Errorf("not found: %s", resource)
+		}
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("no record id is set")
+		}
+		groupProfileId, profileId, err := parseGroupMembershipId(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		client := testAccProvider.Meta().(*apiClient.Client)
+		member, err := client.ReadGroupProfileMember(groupProfileId, profileId)
+		if err != nil {
+			return fmt.Errorf("error fetching item with resource %s. %s", resource, err)
+		}
+		if member == nil {
+			return fmt.Errorf("profile %s is not a member of group profile %s", profileId, groupProfileId)
+		}
+		return nil
+	}
+}
+
+func testAccCheckGroupMembershipDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*apiClient.Client)
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type == "turbot_group_membership" {
+			groupProfileId, profileId, err := parseGroupMembershipId(rs.Primary.ID)
+			if err != nil {
+				return err
+			}
+			// the group profile and profile are destroyed too, which also ends the membership
+			member, err := client.ReadGroupProfileMember(groupProfileId, profileId)
+			if err == nil && member != nil {
+				return fmt.Errorf("group membership still exists")
+			}
+		}
+	}
+
+	return nil
+}
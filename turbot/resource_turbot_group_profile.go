@@ -1,9 +1,9 @@
 package turbot
 
 import (
+	"errors"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/turbot/terraform-provider-turbot/apiClient"
-	"github.com/turbot/terraform-provider-turbot/errors"
 	"github.com/turbot/terraform-provider-turbot/helpers"
 )
 
@@ -30,10 +30,23 @@ func resourceTurbotGroupProfile() *schema.Resource {
 				Type:     schema.TypeString,
 				Required: true,
 				ForceNew: true,
+				// when doing a diff, the state file will contain the id of the directory but the config contains the aka,
+				// so we need custom diff code
+				DiffSuppressFunc: suppressIfAkaMatches("directory_akas"),
 			},
+			// when doing a read, fetch the directory akas to use in suppressIfAkaMatches
+			"directory_akas": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			// the group id is fixed when the group profile is created
 			"group_profile_id": {
 				Type:     schema.TypeString,
 				Required: true,
+				ForceNew: true,
 			},
 			"title": {
 				Type:     schema.TypeString,
@@ -41,7 +54,8 @@ func resourceTurbotGroupProfile() *schema.Resource {
 			},
 			"status": {
 				Type:     schema.TypeString,
-				Required: true,
+				Optional: true,
+				Default:  "Active",
 			},
 		},
 	}
@@ -63,10 +77,14 @@ func resourceTurbotGroupProfileCreate(d *schema.ResourceData, meta interface{})
 		return err
 	}
 
+	// set directory_akas property by loading the directory and fetching the akas
+	if err := storeAkas(groupProfile.Turbot.ParentId, "directory_akas", d, meta); err != nil {
+		return err
+	}
 	// assign the id
 	d.SetId(groupProfile.Turbot.Id)
 	// assign results back into ResourceData
-	d.Set("directory", groupProfile.Directory)
+	d.Set("directory", groupProfile.Parent)
 	d.Set("title", groupProfile.Title)
 	d.Set("status", groupProfile.Status)
 	d.Set("group_profile_id", groupProfile.GroupProfileId)
@@ -79,28 +97,29 @@ func resourceTurbotGroupProfileRead(d *schema.ResourceData, meta interface{}) er
 
 	groupProfile, err := client.ReadGroupProfile(id)
 	if err != nil {
-		if errors.NotFoundError(err) {
-			// profile was not found - clear id
+		if errors.Is(err, apiClient.ErrNotFound) {
+			// group profile was not found - clear id
 			d.SetId("")
 		}
 		return err
 	}
 
 	// assign results back into ResourceData
-	d.Set("directory", groupProfile.Directory)
+	d.Set("directory", groupProfile.Parent)
 	d.Set("title", groupProfile.Title)
 	d.Set("status", groupProfile.Status)
 	d.Set("group_profile_id", groupProfile.GroupProfileId)
-	return nil
+	// set directory_akas property by loading the directory and fetching the akas
+	return storeAkas(groupProfile.Turbot.ParentId, "directory_akas", d, meta)
 }
 
 func resourceTurbotGroupProfileUpdate(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*apiClient.Client)
-	// build mutation data
-	input := mapFromResourceData(d, groupProfileInputProperties)
+	// build mutation data - the directory and group id cannot be changed
+	input := mapFromResourceData(d, getGroupProfileUpdateProperties())
 	input["id"] = d.Id()
 
-	// do create
+	// do update
 	groupProfile, err := client.UpdateGroupProfile(input)
 	if err != nil {
 		return err
@@ -116,7 +135,8 @@ func resourceTurbotGroupProfileDelete(d *schema.ResourceData, meta interface{})
 	client := meta.(*apiClient.Client)
 	id := d.Id()
 	err := client.DeleteGroupProfile(id)
-	if err != nil {
+	// deleting a directory deletes its group profiles, so the group profile may already be gone
+	if err != nil && !errors.Is(err, apiClient.ErrNotFound) {
 		return err
 	}
 
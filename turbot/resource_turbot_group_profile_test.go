@@ -1,50 +1,110 @@
 package turbot
 
 import (
+	"errors"
 	"fmt"
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/terraform"
 	"github.com/turbot/terraform-provider-turbot/apiClient"
-	"github.com/turbot/terraform-provider-turbot/errors"
+	"testing"
 )
 
 // test suites
-//func TestAccGroupProfile_Basic(t *testing.T) {
-//	resource.Test(t, resource.TestCase{
-//		PreCheck:     func() { testAccPreCheck(t) },
-//		Providers:    testAccProviders,
-//		CheckDestroy: testAccCheckGroupProfileDestroy,
-//		Steps: []resource.TestStep{
-//			{
-//				Config: testAcGroupProfileConfig(),
-//				Check: resource.ComposeTestCheckFunc(
-//					testAccCheckGroupProfileExists("turbot_group_profile.admin_group"),
-//					resource.TestCheckResourceAttr("turbot_group_profile.admin_group", "title", "admin"),
-//					resource.TestCheckResourceAttr("turbot_group_profile.admin_group", "status", "Active"),
-//				),
-//			},
-//		},
-//	})
-//}
+func TestAccGroupProfile_LocalDirectory(t *testing.T) {
+	testAccGroupProfile(t, "turbot_local_directory", testAccGroupProfileLocalDirectoryConfig)
+}
 
-// configs
-func testAcGroupProfileConfig() string {
-	return `
-resource "turbot_saml_directory" "test" {
-	title               = "provider-test"
-	parent              = "tmod:@turbot/turbot#/"
-	profile_id_template = "{{profile.email}}"
-	description         = "SAML Directory Testing1"
-	entry_point         = "https://example.com/myapp/sso/saml"
-	certificate         = "-----BEGIN CERTIFICATE-----\nMIICiTCCAfICCQD6m7oRw0uXOjANBgkqhkiG9w0BAQUFADCBiDELMAkGA1UEBhMC\nVVMxCzAJBgNVBAgTAldBMRAwDgYDVQQHEwdTZWF0dGxlMQ8wDQYDVQQKEwZBbWF6\nb24xFDASBgNVBAsTC0lBTSBDb25zb2xlMRIwEAYDVQQDEwlUZXN0Q2lsYWMxHzAd\nBgkqhkiG9w0BCQEWEG5vb25lQGFtYXpvbi5jb20wHhcNMTEwNDI1MjA0NTIxWhcN\nMTIwNDI0MjA0NTIxWjCBiDELMAkGA1UEBhMCVVMxCzAJBgNVBAgTAldBMRAwDgYD\nVQQHEwdTZWF0dGxlMQ8wDQYDVQQKEwZBbWF6b24xFDASBgNVBAsTC0lBTSBDb25z\nb2xlMRIwEAYDVQQDEwlUZXN0Q2lsYWMxHzAdBgkqhkiG9w0BCQEWEG5vb25lQGFt\nYXpvbi5jb20wgZ8wDQYJKoZIhvcNAQEBBQADgY0AMIGJAoGBAMaK0dn+a4GmWIWJ\n21uUSfwfEvySWtC2XADZ4nB+BLYgVIk60CpiwsZ3G93vUEIO3IyNoH/f0wYK8m9T\nrDHudUZg3qX4waLG5M43q7Wgc/MbQITxOUSQv7c7ugFFDzQGBzZswY6786m86gpE\nIbb3OhjZnzcvQAaRHhdlQWIMm2nrAgMBAAEwDQYJKoZIhvcNAQEFBQADgYEAtCu4\nnUhVVxYUntneD9+h8Mg9q6q+auNKyExzyLwaxlAoo7TJHidbtS4J5iNmZgXL0Fkb\nFFBjvSfpJIlJ00zbhNYS5f6GuoEDmFJl0ZxBHjJnyp378OD8uTs7fLvjx79LjSTb\nNYiytVbZPQUQ5Yaxu2jXnimvw3rrszlaEXAMPLE=\n-----END CERTIFICATE-----"
+func TestAccGroupProfile_SamlDirectory(t *testing.T) {
+	testAccGroupProfile(t, "turbot_saml_directory", testAccGroupProfileSamlDirectoryConfig)
+}
+
+func TestAccGroupProfile_LdapDirectory(t *testing.T) {
+	testAccGroupProfile(t, "turbot_ldap_directory", testAccGroupProfileLdapDirectoryConfig)
 }
+
+// testAccGroupProfile creates, updates and imports a group profile in a directory of the given type
+func testAccGroupProfile(t *testing.T, directoryType, directoryConfig string) {
+	resourceName := "turbot_group_profile.admin_group"
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckGroupProfileDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGroupProfileConfig(directoryType, directoryConfig, "admin", ""),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGroupProfileExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "title", "admin"),
+					resource.TestCheckResourceAttr(resourceName, "status", "Active"),
+					resource.TestCheckResourceAttr(resourceName, "group_profile_id", "provider-test-admins"),
+					resource.TestCheckResourceAttrPair(resourceName, "directory", directoryType+".test", "id"),
+				),
+			},
+			{
+				Config: testAccGroupProfileConfig(directoryType, directoryConfig, "administrators", "Inactive"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGroupProfileExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "title", "administrators"),
+					resource.TestCheckResourceAttr(resourceName, "status", "Inactive"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+// configs
+func testAccGroupProfileConfig(directoryType, directoryConfig, title, status string) string {
+	statusConfig := ""
+	if status != "" {
+		statusConfig = fmt.Sprintf(`status = "%s"`, status)
+	}
+	return directoryConfig + fmt.Sprintf(`
 resource "turbot_group_profile" "admin_group" {
-  	directory = turbot_saml_directory.test.id
-  	title  = "admin"
-  	group_profile_id = "170759063660234"
+  directory        = %s.test.id
+  title            = "%s"
+  group_profile_id = "provider-test-admins"
+  %s
+}
+`, directoryType, title, statusConfig)
+}
+
+const testAccGroupProfileLocalDirectoryConfig = `
+resource "turbot_local_directory" "test" {
+  parent              = "tmod:@turbot/turbot#/"
+  title               = "provider_test_group_profile"
+  description         = "test directory"
+  profile_id_template = "{{profile.email}}"
 }
 `
+
+const testAccGroupProfileSamlDirectoryConfig = `
+resource "turbot_saml_directory" "test" {
+  parent              = "tmod:@turbot/turbot#/"
+  title               = "provider_test_group_profile"
+  profile_id_template = "{{profile.email}}"
+  entry_point         = "https://example.com/myapp/sso/saml"
+  certificate         = "-----BEGIN CERTIFICATE-----\nMIICiTCCAfICCQD6m7oRw0uXOjANBgkqhkiG9w0BAQUFADCBiDELMAkGA1UEBhMC\nVVMxCzAJBgNVBAgTAldBMRAwDgYDVQQHEwdTZWF0dGxlMQ8wDQYDVQQKEwZBbWF6\nb24xFDASBgNVBAsTC0lBTSBDb25zb2xlMRIwEAYDVQQDEwlUZXN0Q2lsYWMxHzAd\nBgkqhkiG9w0BCQEWEG5vb25lQGFtYXpvbi5jb20wHhcNMTEwNDI1MjA0NTIxWhcN\nMTIwNDI0MjA0NTIxWjCBiDELMAkGA1UEBhMCVVMxCzAJBgNVBAgTAldBMRAwDgYD\nVQQHEwdTZWF0dGxlMQ8wDQYDVQQKEwZBbWF6b24xFDASBgNVBAsTC0lBTSBDb25z\nb2xlMRIwEAYDVQQDEwlUZXN0Q2lsYWMxHzAdBgkqhkiG9w0BCQEWEG5vb25lQGFt\nYXpvbi5jb20wgZ8wDQYJKoZIhvcNAQEBBQADgY0AMIGJAoGBAMaK0dn+a4GmWIWJ\n21uUSfwfEvySWtC2XADZ4nB+BLYgVIk60CpiwsZ3G93vUEIO3IyNoH/f0wYK8m9T\nrDHudUZg3qX4waLG5M43q7Wgc/MbQITxOUSQv7c7ugFFDzQGBzZswY6786m86gpE\nIbb3OhjZnzcvQAaRHhdlQWIMm2nrAgMBAAEwDQYJKoZIhvcNAQEFBQADgYEAtCu4\nnUhVVxYUntneD9+h8Mg9q6q+auNKyExzyLwaxlAoo7TJHidbtS4J5iNmZgXL0Fkb\nFFBjvSfpJIlJ00zbhNYS5f6GuoEDmFJl0ZxBHjJnyp378OD8uTs7fLvjx79LjSTb\nNYiytVbZPQUQ5Yaxu2jXnimvw3rrszlaEXAMPLE=\n-----END CERTIFICATE-----"
 }
+`
+
+const testAccGroupProfileLdapDirectoryConfig = `
+resource "turbot_ldap_directory" "test" {
+  parent              = "tmod:@turbot/turbot#/"
+  title               = "provider_test_group_profile"
+  profile_id_template = "{{profile.email}}"
+  distinguished_name  = "CN=Turbot"
+  password            = "x7hjFeErf0_+"
+  url                 = "xw"
+  base                = "xw"
+  tls_enabled         = false
+  reject_unauthorized = false
+}
+`
 
 // helper functions
 func testAccCheckGroupProfileExists(resource string) resource.TestCheckFunc {
@@ -54,7 +114,7 @@ func testAccCheckGroupProfileExists(resource string) resource.TestCheckFunc {
 			return fmt.Errorf("not found: %s", resource)
 		}
 		if rs.Primary.ID == "" {
-			return fmt.Errorf("no reecord id is set")
+			return fmt.Errorf("no record id is set")
 		}
 		client := testAccProvider.Meta().(*apiClient.Client)
 		_, err := client.ReadGroupProfile(rs.Primary.ID)
@@ -71,9 +131,9 @@ func testAccCheckGroupProfileDestroy(s *terraform.State) error {
 		if rs.Type == "turbot_group_profile" {
 			_, err := client.ReadGroupProfile(rs.Primary.ID)
 			if err == nil {
-				return fmt.Errorf("alert still exists")
+				return fmt.Errorf("group profile still exists")
 			}
-			if !errors.NotFoundError(err) {
+			if !errors.Is(err, apiClient.ErrNotFound) {
 				return fmt.Errorf("expected 'not found' error, got %s", err)
 			}
 		}
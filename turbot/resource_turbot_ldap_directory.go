@@ -1,9 +1,9 @@
 package turbot
 
 import (
+	"errors"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/turbot/terraform-provider-turbot/apiClient"
-	"github.com/turbot/terraform-provider-turbot/errors"
 	"github.com/turbot/terraform-provider-turbot/helpers"
 	"strings"
 )
@@ -251,7 +251,7 @@ func resourceTurbotLdapDirectoryRead(d *schema.ResourceData, meta interface{}) e
 
 	ldapDirectory, err := client.ReadLdapDirectory(id)
 	if err != nil {
-		if errors.NotFoundError(err) {
+		if errors.Is(err, apiClient.ErrNotFound) {
 			// local directory was not found - clear id
 			d.SetId("")
 		}
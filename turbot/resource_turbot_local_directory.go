@@ -1,9 +1,9 @@
 package turbot
 
 import (
+	"errors"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/turbot/terraform-provider-turbot/apiClient"
-	"github.com/turbot/terraform-provider-turbot/errors"
 	"github.com/turbot/terraform-provider-turbot/helpers"
 	"strings"
 )
@@ -112,7 +112,7 @@ func resourceTurbotLocalDirectoryRead(d *schema.ResourceData, meta interface{})
 
 	localDirectory, err := client.ReadLocalDirectory(id)
 	if err != nil {
-		if errors.NotFoundError(err) {
+		if errors.Is(err, apiClient.ErrNotFound) {
 			// local directory was not found - clear id
 			d.SetId("")
 		}
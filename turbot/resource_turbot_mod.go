@@ -7,7 +7,6 @@ import (
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/turbot/terraform-provider-turbot/apiClient"
-	errorsHandler "github.com/turbot/terraform-provider-turbot/errors"
 	"log"
 	"strings"
 	"time"
@@ -128,7 +127,7 @@ func resourceTurbotModInstall(d *schema.ResourceData, meta interface{}) error {
 		id := mod.Turbot.Id
 		return fmt.Errorf("mod %s is already installed ( id: %s ). To manage this mod using Terraform, import the mod using command 'terraform import <resource_address> <id>'", modAka, id)
 	}
-	if !errorsHandler.NotFoundError(err) {
+	if !errors.Is(err, apiClient.ErrNotFound) {
 		// if the error is not a 'not found' error, the mod is already installed
 		return err
 	}
@@ -197,7 +196,7 @@ func resourceTurbotModRead(d *schema.ResourceData, meta interface{}) error {
 	id := d.Id()
 	mod, err := client.ReadMod(id)
 	if err != nil {
-		if errorsHandler.NotFoundError(err) {
+		if errors.Is(err, apiClient.ErrNotFound) {
 			// mod was not found - clear id
 			d.SetId("")
 		}
@@ -291,7 +290,11 @@ func getLatestCompatibleVersion(org, modName, version string, meta interface{})
 		return "", err
 	}
 
-	// now get latest version
+	return latestCompatibleVersion(modVersions, c)
+}
+
+// the newest available or recommended version that satisfies the constraint, or empty if there is none
+func latestCompatibleVersion(modVersions []apiClient.ModRegistryVersion, c *semver.Constraints) (string, error) {
 	var latestVersion *semver.Version
 	for _, modVersion := range modVersions {
 		modStatus := strings.ToLower(modVersion.Status)
@@ -312,5 +315,4 @@ func getLatestCompatibleVersion(org, modName, version string, meta interface{})
 		latestVersionString = latestVersion.String()
 	}
 	return latestVersionString, nil
-
 }
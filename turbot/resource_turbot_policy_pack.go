@@ -1,9 +1,10 @@
 package turbot
 
 import (
+	"errors"
+	"fmt"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/turbot/terraform-provider-turbot/apiClient"
-	"github.com/turbot/terraform-provider-turbot/errors"
 	"github.com/turbot/terraform-provider-turbot/helpers"
 )
 
@@ -67,6 +68,14 @@ func resourceTurbotPolicyPack() *schema.Resource {
 				},
 				DiffSuppressFunc: suppressIfAkaRemoved(),
 			},
+			// the policy settings in the pack, one per policy type. Once at least one is configured the pack
+			// owns its settings: a setting made in the pack outside of the config shows as drift, and is
+			// deleted on the next apply. Without any, the pack's settings are left to turbot_policy_setting.
+			"setting": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem:     policySettingBlockResource(),
+			},
 		},
 	}
 }
@@ -89,6 +98,11 @@ func resourceTurbotPolicyPackCreate(d *schema.ResourceData, meta interface{}) er
 
 	// assign the id
 	d.SetId(policyPack.Turbot.Id)
+	if blocks := d.Get("setting").(*schema.Set).List(); len(blocks) > 0 {
+		if err := updatePolicySettingBlocks(client, policyPack.Turbot.Id, blocks, ownsAllPolicySettings); err != nil {
+			return err
+		}
+	}
 	// TODO Remove Read call once schema changes are In.
 	return resourceTurbotPolicyPackRead(d, meta)
 }
@@ -105,6 +119,18 @@ func resourceTurbotPolicyPackUpdate(d *schema.ResourceData, meta interface{}) er
 	if err != nil {
 		return err
 	}
+	if d.HasChange("setting") {
+		old, new := d.GetChange("setting")
+		blocks := new.(*schema.Set).List()
+		owns := ownsAllPolicySettings
+		if len(blocks) == 0 {
+			// without blocks the pack no longer owns its settings - only those it configured are deleted
+			owns = ownsPolicySettingTypes(old.(*schema.Set).List())
+		}
+		if err := updatePolicySettingBlocks(client, id, blocks, owns); err != nil {
+			return err
+		}
+	}
 	// set 'Read' Properties
 	// TODO Remove Read call once schema changes are In.
 	return resourceTurbotPolicyPackRead(d, meta)
@@ -116,7 +142,7 @@ func resourceTurbotPolicyPackRead(d *schema.ResourceData, meta interface{}) erro
 
 	policyPack, err := client.ReadSmartFolder(id)
 	if err != nil {
-		if errors.NotFoundError(err) {
+		if errors.Is(err, apiClient.ErrNotFound) {
 			// folder was not found - clear id
 			d.SetId("")
 		}
@@ -138,6 +164,28 @@ func resourceTurbotPolicyPackRead(d *schema.ResourceData, meta interface{}) erro
 	d.Set("tags", policyPack.Turbot.Tags)
 	d.Set("akas", policyPack.Turbot.Akas)
 
+	// a pack without setting blocks does not own its settings, so there are none to report - an
+	// import, too, reports none, so a config without blocks does not delete the imported settings
+	state := d.Get("setting").(*schema.Set).List()
+	if len(state) == 0 {
+		return nil
+	}
+	settings, err := client.ReadAllPolicySettings(fmt.Sprintf("resource:%s level:self", id))
+	if err != nil {
+		return err
+	}
+	// a setting is stored as its configured block when it matches it, so a value the API formats
+	// differently from the config does not diff
+	configured := configuredPolicySettingBlocks(state)
+	var blocks []interface{}
+	for _, setting := range settings {
+		block, err := policySettingBlock(setting, configured[setting.Type.Uri])
+		if err != nil {
+			return err
+		}
+		blocks = append(blocks, block)
+	}
+	d.Set("setting", blocks)
 	return nil
 }
 
@@ -162,6 +210,220 @@ func resourceTurbotPolicyPackImport(d *schema.ResourceData, meta interface{}) ([
 	return []*schema.ResourceData{d}, nil
 }
 
+// ownsAllPolicySettings is the owner of every policy type, for a resource that owns all the settings
+// directly on it
+func ownsAllPolicySettings(string) bool {
+	return true
+}
+
+// ownsPolicySettingTypes is the owner of the policy types of the setting blocks
+func ownsPolicySettingTypes(blocks []interface{}) func(string) bool {
+	configured := configuredPolicySettingBlocks(blocks)
+	return func(typeUri string) bool {
+		_, ok := configured[typeUri]
+		return ok
+	}
+}
+
+// policySettingBlockResource is the schema of a setting block, which configures one policy setting
+// directly on a resource
+func policySettingBlockResource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"type": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"value": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"template": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"template_input": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"precedence": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "REQUIRED",
+			},
+			"note": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+		},
+	}
+}
+
+// updatePolicySettingBlocks makes the settings directly on the resource the configured settings: each
+// setting of an owned type that is not configured is deleted, each that differs from its configured
+// block is updated, and each configured setting that does not exist is created. Settings of types the
+// caller does not own are left alone.
+func updatePolicySettingBlocks(client *apiClient.Client, resourceId string, blocks []interface{}, owns func(string) bool) error {
+	configured := configuredPolicySettingBlocks(blocks)
+	if len(configured) != len(blocks) {
+		return fmt.Errorf("only one setting of each policy type may be configured")
+	}
+	settings, err := client.ReadAllPolicySettings(fmt.Sprintf("resource:%s level:self", resourceId))
+	if err != nil {
+		return err
+	}
+	for _, setting := range settings {
+		block, ok := configured[setting.Type.Uri]
+		delete(configured, setting.Type.Uri)
+		if !ok {
+			if !owns(setting.Type.Uri) {
+				continue
+			}
+			if err := client.DeletePolicySetting(setting.Turbot.Id); err != nil {
+				return err
+			}
+			continue
+		}
+		matches, err := policySettingBlockMatches(setting, block)
+		if err != nil {
+			return err
+		}
+		if matches {
+			continue
+		}
+		input := policySettingBlockInput(block)
+		input["id"] = setting.Turbot.Id
+		if err := writePolicySettingBlock(client.UpdatePolicySetting, input); err != nil {
+			return err
+		}
+	}
+	for typeUri, block := range configured {
+		input := policySettingBlockInput(block)
+		input["type"] = typeUri
+		input["resource"] = resourceId
+		if err := writePolicySettingBlock(client.CreatePolicySetting, input); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writePolicySettingBlock creates or updates a setting. As for turbot_policy_setting, a value that the
+// policy schema rejects is tried again as a value source - the YAML form of the value.
+func writePolicySettingBlock(write func(map[string]interface{}) (*apiClient.PolicySetting, error), input map[string]interface{}) error {
+	_, err := write(input)
+	if _, ok := input["value"].(string); !ok || !errors.Is(err, apiClient.ErrSchemaValidation) {
+		return err
+	}
+	input["valueSource"] = input["value"]
+	delete(input, "value")
+	_, err = write(input)
+	return err
+}
+
+// policySettingBlockInput builds the create or update input for a setting block: its template and
+// template input if it has a template, otherwise its value
+func policySettingBlockInput(block map[string]interface{}) map[string]interface{} {
+	input := map[string]interface{}{
+		"precedence": block["precedence"],
+		"note":       block["note"],
+	}
+	if template := block["template"].(string); template != "" {
+		input["template"] = template
+		if templateInput := block["template_input"].(string); templateInput != "" {
+			// NOTE: a single query is not valid YAML, and ParseYamlString returns it unchanged on error
+			input["templateInput"], _ = helpers.ParseYamlString(templateInput)
+		}
+		return input
+	}
+	input["value"] = block["value"]
+	return input
+}
+
+// policySettingBlock returns the configured block of the setting's type if the setting matches it,
+// and otherwise a block of the setting as the API reports it
+func policySettingBlock(setting apiClient.PolicySetting, block map[string]interface{}) (interface{}, error) {
+	if block != nil {
+		matches, err := policySettingBlockMatches(setting, block)
+		if err != nil {
+			return nil, err
+		}
+		if matches {
+			return block, nil
+		}
+	}
+	// NOTE: TemplateInput can be string or array of strings - an array is returned as a YAML string
+	templateInput, err := helpers.InterfaceToStringOrYaml(setting.TemplateInput)
+	if err != nil {
+		return nil, err
+	}
+	// a string value is reported as it is; any other as its YAML source, or failing that as JSON -
+	// which is YAML too - so an object or array value can be copied back into the block
+	value := ""
+	if setting.Template == "" {
+		if _, isString := setting.Value.(string); !isString && setting.ValueSource != "" {
+			value = setting.ValueSource
+		} else if value, err = helpers.InterfaceToStringOrJson(setting.Value); err != nil {
+			return nil, err
+		}
+	}
+	return map[string]interface{}{
+		"type":           setting.Type.Uri,
+		"value":          value,
+		"template":       setting.Template,
+		"template_input": templateInput,
+		"precedence":     setting.Precedence,
+		"note":           setting.Note,
+	}, nil
+}
+
+// policySettingBlockMatches returns whether the setting is as its block configures it. A value matches
+// either the setting's value, as JSON unless it is a string, or, as YAML, its value source; a template input matches as YAML.
+func policySettingBlockMatches(setting apiClient.PolicySetting, block map[string]interface{}) (bool, error) {
+	if setting.Precedence != block["precedence"].(string) || setting.Note != block["note"].(string) || setting.Template != block["template"].(string) {
+		return false, nil
+	}
+	if setting.Template != "" {
+		templateInput, err := helpers.InterfaceToStringOrYaml(setting.TemplateInput)
+		if err != nil {
+			return false, err
+		}
+		configuredInput := block["template_input"].(string)
+		if templateInput == configuredInput {
+			return true, nil
+		}
+		if templateInput == "" || configuredInput == "" {
+			return false, nil
+		}
+		// a single query that is not valid YAML only matches as the same string
+		equivalent, _ := helpers.YamlStringsAreEqual(templateInput, configuredInput)
+		return equivalent, nil
+	}
+	value := block["value"].(string)
+	if reported, err := helpers.InterfaceToStringOrJson(setting.Value); err == nil && value == reported {
+		return true, nil
+	}
+	if setting.ValueSource == "" {
+		return false, nil
+	}
+	equivalent, err := helpers.YamlStringsAreEqual(value, setting.ValueSource)
+	if err != nil {
+		// a value that is not valid YAML matched neither form
+		return false, nil
+	}
+	return equivalent, nil
+}
+
+// configuredPolicySettingBlocks returns the setting blocks keyed by policy type
+func configuredPolicySettingBlocks(blocks []interface{}) map[string]map[string]interface{} {
+	configured := map[string]map[string]interface{}{}
+	for _, block := range blocks {
+		setting := block.(map[string]interface{})
+		configured[setting["type"].(string)] = setting
+	}
+	return configured
+}
+
 // Suppress the diff if trying to remove the akas completely
 func suppressIfAkaRemoved() func(k, old, new string, d *schema.ResourceData) bool {
 	return func(k, old, new string, d *schema.ResourceData) bool {
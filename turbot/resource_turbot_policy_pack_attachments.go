@@ -0,0 +1,148 @@
+package turbot
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/turbot/terraform-provider-turbot/apiClient"
+)
+
+// turbot_policy_pack_attachments is authoritative: it owns the complete, ordered list of policy packs
+// attached to the resource, detaching packs that were attached outside of the config
+func resourceTurbotPolicyPackAttachments() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceTurbotPolicyPackAttachmentsCreate,
+		Read:   resourceTurbotPolicyPackAttachmentsRead,
+		Update: resourceTurbotPolicyPackAttachmentsUpdate,
+		Delete: resourceTurbotPolicyPackAttachmentsDelete,
+		Exists: resourceTurbotPolicyPackAttachmentsExists,
+		Importer: &schema.ResourceImporter{
+			State: resourceTurbotPolicyPackAttachmentsImport,
+		},
+		Schema: map[string]*schema.Schema{
+			// aka of the attachment target
+			"resource": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: suppressIfAkaMatches("resource_akas"),
+			},
+			"resource_akas": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			// ids or akas of the attached policy packs, in attachment order - an empty list detaches
+			// every pack
+			"policy_packs": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+func resourceTurbotPolicyPackAttachmentsExists(d *schema.ResourceData, meta interface{}) (b bool, e error) {
+	client := meta.(*apiClient.Client)
+	_, _, err := client.ReadAttachedPolicyPacks(d.Id())
+	if err != nil {
+		// a deleted target takes its attachments with it - see resourceTurbotPolicyPackAttachmentExists
+		if apiClient.IsTargetNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("error reading policy pack attachments: %s", err.Error())
+	}
+	return true, nil
+}
+
+func resourceTurbotPolicyPackAttachmentsCreate(d *schema.ResourceData, meta interface{}) error {
+	return createOnResource(d, meta, d.Get("resource").(string), func(client *apiClient.Client, id string) error {
+		return setAttachedPolicyPacks(client, id, d.Get("policy_packs").([]interface{}))
+	}, resourceTurbotPolicyPackAttachmentsRead)
+}
+
+func resourceTurbotPolicyPackAttachmentsRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*apiClient.Client)
+	id := d.Id()
+
+	attached, truncated, err := client.ReadAttachedPolicyPacks(id)
+	if err != nil {
+		if apiClient.IsTargetNotFound(err) || errors.Is(err, apiClient.ErrNotFound) {
+			// target was not found - clear id
+			d.SetId("")
+		}
+		return err
+	}
+	// the list cannot be paged, so a pack missing from a truncated list might be attached or not
+	if truncated {
+		return fmt.Errorf("error reading policy pack attachments: resource %s reports more attachments than a single page returns, so its attachments cannot be determined reliably", id)
+	}
+
+	// the packs are stored in attachment order, so the plan shows a reorder as well as a new pack
+	configured := d.Get("policy_packs").([]interface{})
+	var policyPacks []interface{}
+	for _, pack := range attached {
+		policyPacks = append(policyPacks, configuredAka(pack, configured))
+	}
+	d.Set("policy_packs", policyPacks)
+	d.Set("resource", id)
+	// set resource_akas property by loading resource and fetching the akas
+	return storeAkas(id, "resource_akas", d, meta)
+}
+
+func resourceTurbotPolicyPackAttachmentsUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*apiClient.Client)
+	id := d.Id()
+
+	if d.HasChange("policy_packs") {
+		if err := setAttachedPolicyPacks(client, id, d.Get("policy_packs").([]interface{})); err != nil {
+			return err
+		}
+	}
+	return resourceTurbotPolicyPackAttachmentsRead(d, meta)
+}
+
+func resourceTurbotPolicyPackAttachmentsDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*apiClient.Client)
+	id := d.Id()
+
+	err := setAttachedPolicyPacks(client, id, nil)
+	// a deleted target takes its attachments with it
+	if err != nil && !apiClient.IsTargetNotFound(err) {
+		return err
+	}
+
+	// clear the id to show we have deleted
+	d.SetId("")
+	return nil
+}
+
+func resourceTurbotPolicyPackAttachmentsImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if err := resourceTurbotPolicyPackAttachmentsRead(d, meta); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+// setAttachedPolicyPacks resolves each of the policy packs to its numeric id - the attach mutation
+// does not accept akas - and makes them the packs attached to the target, in order
+func setAttachedPolicyPacks(client *apiClient.Client, target string, policyPacks []interface{}) error {
+	var packIds []string
+	for _, policyPack := range policyPacks {
+		// read via `policyPack(id:)`, which does not require a grant on the pack - see apiClient/policy_pack.go
+		identity, err := client.ReadPolicyPackIdentity(policyPack.(string), "policy pack")
+		if err != nil {
+			return err
+		}
+		if identity.Id == "" {
+			return fmt.Errorf("policy pack %q resolved to an empty ID", policyPack)
+		}
+		packIds = append(packIds, identity.Id)
+	}
+	return client.SetAttachedPolicyPacks(target, packIds)
+}
@@ -0,0 +1,177 @@
+package turbot
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/turbot/terraform-provider-turbot/apiClient"
+	"strings"
+	"testing"
+)
+
+// test suites
+func TestAccPolicyPackAttachments_Basic(t *testing.T) {
+	resourceName := "turbot_policy_pack_attachments.test"
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPolicyPackAttachmentsDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPolicyPackAttachmentsConfig("first", "second"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPolicyPackAttachmentsOrder(resourceName, "first", "second"),
+					resource.TestCheckResourceAttr(resourceName, "policy_packs.#", "2"),
+					resource.TestCheckResourceAttrPair(resourceName, "resource", "turbot_folder.test", "id"),
+					// attach a pack outside of the config, which the next apply must detach
+					testAccAttachPolicyPack("third"),
+				),
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccPolicyPackAttachmentsConfig("first", "second"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPolicyPackAttachmentsOrder(resourceName, "first", "second"),
+				),
+			},
+			{
+				// a reorder, with the out of band pack now configured by its aka - which is how the
+				// state keeps it, while the others keep their ids
+				Config: testAccPolicyPackAttachmentsConfig("second", "third", "first"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPolicyPackAttachmentsOrder(resourceName, "second", "third", "first"),
+					resource.TestCheckResourceAttrPair(resourceName, "policy_packs.0", "turbot_policy_pack.second", "id"),
+					resource.TestCheckResourceAttr(resourceName, "policy_packs.1", "provider_test_third"),
+					resource.TestCheckResourceAttrPair(resourceName, "policy_packs.2", "turbot_policy_pack.first", "id"),
+				),
+			},
+			{
+				// an import has no config to take akas from, so it reports every pack by id
+				ResourceName: resourceName,
+				ImportState:  true,
+				ImportStateCheck: func(states []*terraform.InstanceState) error {
+					if len(states) != 1 || states[0].Attributes["policy_packs.#"] != "3" || states[0].Attributes["policy_packs.1"] == "provider_test_third" {
+						return fmt.Errorf("expected an import of 3 policy packs by id, got %v", states)
+					}
+					return nil
+				},
+			},
+			{
+				Config: testAccPolicyPackAttachmentsConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPolicyPackAttachmentsOrder(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "policy_packs.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+// configs
+func testAccPolicyPackAttachmentsConfig(packs ...string) string {
+	// the third pack is given by its aka, the others by id
+	var policyPacks []string
+	for _, pack := range packs {
+		if pack == "third" {
+			policyPacks = append(policyPacks, "turbot_policy_pack.third.akas[0]")
+			continue
+		}
+		policyPacks = append(policyPacks, fmt.Sprintf("turbot_policy_pack.%s.id", pack))
+	}
+	config := `
+resource "turbot_folder" "test" {
+  parent      = "tmod:@turbot/turbot#/"
+  title       = "provider_test_policy_pack_attachments"
+  description = "test folder"
+}
+`
+	for _, pack := range []string{"first", "second", "third"} {
+		config += fmt.Sprintf(`
+resource "turbot_policy_pack" "%s" {
+  filter      = "resourceType:181381985925765 $.turbot.tags.a:b"
+  description = "Policy Pack Attachments Testing"
+  title       = "provider_test_%s"
+  akas        = ["provider_test_%s"]
+}
+`, pack, pack, pack)
+	}
+	return config + fmt.Sprintf(`
+resource "turbot_policy_pack_attachments" "test" {
+  resource     = turbot_folder.test.id
+  policy_packs = [%s]
+}
+`, strings.Join(policyPacks, ", "))
+}
+
+// helper functions
+
+// testAccCheckPolicyPackAttachmentsOrder checks that the test config's policy packs of the given names,
+// and no others, are attached to the resource, in order
+func testAccCheckPolicyPackAttachmentsOrder(resource string, packs ...string) resource.TestCheckFunc {
+	return func(state *terraform.State) error {
+		rs, ok := state.RootModule().Resources[resource]
+		if !ok {
+			return fmt.Errorf("not found: %s", resource)
+		}
+		var expected []string
+		for _, pack := range packs {
+			packState, ok := state.RootModule().Resources["turbot_policy_pack."+pack]
+			if !ok {
+				return fmt.Errorf("not found: turbot_policy_pack.%s", pack)
+			}
+			expected = append(expected, packState.Primary.ID)
+		}
+		client := testAccProvider.Meta().(*apiClient.Client)
+		attached, _, err := client.ReadAttachedPolicyPacks(rs.Primary.ID)
+		if err != nil {
+			return fmt.Errorf("error fetching item with resource %s. %s", resource, err)
+		}
+		var actual []string
+		for _, pack := range attached {
+			actual = append(actual, pack.Id)
+		}
+		if strings.Join(actual, ",") != strings.Join(expected, ",") {
+			return fmt.Errorf("expected policy packs %v attached to %s, got %v", expected, rs.Primary.ID, actual)
+		}
+		return nil
+	}
+}
+
+// testAccAttachPolicyPack attaches one of the test config's policy packs to the test folder, outside
+// of terraform
+func testAccAttachPolicyPack(pack string) resource.TestCheckFunc {
+	return func(state *terraform.State) error {
+		folder, ok := state.RootModule().Resources["turbot_folder.test"]
+		if !ok {
+			return fmt.Errorf("not found: turbot_folder.test")
+		}
+		packState, ok := state.RootModule().Resources["turbot_policy_pack."+pack]
+		if !ok {
+			return fmt.Errorf("not found: turbot_policy_pack.%s", pack)
+		}
+		client := testAccProvider.Meta().(*apiClient.Client)
+		_, err := client.CreateSmartFolderAttachment(map[string]interface{}{
+			"resource":     folder.Primary.ID,
+			"smartFolders": packState.Primary.ID,
+		})
+		return err
+	}
+}
+
+func testAccCheckPolicyPackAttachmentsDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*apiClient.Client)
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type == "turbot_policy_pack_attachments" {
+			// the folder is destroyed too, which also takes its attachments with it
+			attached, _, err := client.ReadAttachedPolicyPacks(rs.Primary.ID)
+			if err != nil && !apiClient.IsTargetNotFound(err) {
+				return fmt.Errorf("expected 'not found' error, got %s", err)
+			}
+			if len(attached) > 0 {
+				return fmt.Errorf("policy packs are still attached")
+			}
+		}
+	}
+
+	return nil
+}
@@ -4,10 +4,10 @@ import (
 	"fmt"
 	"testing"
 
+	"errors"
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/terraform"
 	"github.com/turbot/terraform-provider-turbot/apiClient"
-	"github.com/turbot/terraform-provider-turbot/errors"
 )
 
 // test suites
@@ -48,6 +48,86 @@ func TestAccPolicyPack_Basic(t *testing.T) {
 	})
 }
 
+func TestAccPolicyPack_Settings(t *testing.T) {
+	resourceName := "turbot_policy_pack.test"
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPolicyPackDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPolicyPackSettingsConfig("5", true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPolicyPackExists(resourceName),
+					testAccCheckResourcePolicySettings(resourceName, map[string]interface{}{
+						"tmod:@turbot/provider-policy-test#/policy/types/stringPolicy":  "Skip",
+						"tmod:@turbot/provider-policy-test#/policy/types/integerPolicy": 5,
+						"tmod:@turbot/aws-s3#/policy/types/bucketApprovedUsage":         nil,
+					}),
+					resource.TestCheckResourceAttr(resourceName, "setting.#", "3"),
+					// make a setting in the pack outside of the config, which the next apply must delete
+					testAccCreateResourcePolicySetting(resourceName, "tmod:@turbot/provider-policy-test#/policy/types/stringArrayPolicy", "- a\n- b"),
+				),
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccPolicyPackSettingsConfig("5", true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourcePolicySettings(resourceName, map[string]interface{}{
+						"tmod:@turbot/provider-policy-test#/policy/types/stringPolicy":  "Skip",
+						"tmod:@turbot/provider-policy-test#/policy/types/integerPolicy": 5,
+						"tmod:@turbot/aws-s3#/policy/types/bucketApprovedUsage":         nil,
+					}),
+				),
+			},
+			{
+				Config: testAccPolicyPackSettingsConfig("7", false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourcePolicySettings(resourceName, map[string]interface{}{
+						"tmod:@turbot/provider-policy-test#/policy/types/integerPolicy": 7,
+						"tmod:@turbot/aws-s3#/policy/types/bucketApprovedUsage":         nil,
+					}),
+					resource.TestCheckResourceAttr(resourceName, "setting.#", "2"),
+				),
+			},
+			{
+				// an import has no setting blocks, so it does not take ownership of the settings
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"filter", "setting"},
+			},
+		},
+	})
+}
+
+func TestAccPolicyPack_StandaloneSetting(t *testing.T) {
+	resourceName := "turbot_policy_pack.test"
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPolicyPackDestroy,
+		Steps: []resource.TestStep{
+			{
+				// a pack without setting blocks leaves its settings to turbot_policy_setting, so the
+				// setting neither shows as drift on the pack nor is deleted by it
+				Config: testAccPolicyPackStandaloneSettingConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPolicyPackExists(resourceName),
+					testAccCheckResourcePolicySettings(resourceName, map[string]interface{}{
+						"tmod:@turbot/provider-policy-test#/policy/types/stringPolicy": "Skip",
+					}),
+					resource.TestCheckResourceAttr(resourceName, "setting.#", "0"),
+				),
+			},
+			{
+				Config:   testAccPolicyPackStandaloneSettingConfig,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
 // configs
 func testAccPolicyPackConfig() string {
 	return `
@@ -96,7 +176,7 @@ func testAccCheckPolicyPackDestroy(s *terraform.State) error {
 			if err == nil {
 				return fmt.Errorf("alert still exists")
 			}
-			if !errors.NotFoundError(err) {
+			if !errors.Is(err, apiClient.ErrNotFound) {
 				return fmt.Errorf("expected 'not found' error, got %s", err)
 			}
 		}
@@ -104,3 +184,95 @@ func testAccCheckPolicyPackDestroy(s *terraform.State) error {
 
 	return nil
 }
+
+func testAccPolicyPackSettingsConfig(integerValue string, withString bool) string {
+	stringSetting := ""
+	if withString {
+		stringSetting = `
+	setting {
+		type  = "tmod:@turbot/provider-policy-test#/policy/types/stringPolicy"
+		value = "Skip"
+		note  = "skip by default"
+	}
+`
+	}
+	return fmt.Sprintf(`
+resource "turbot_policy_pack" "test" {
+	filter = "resourceType:181381985925765 $.turbot.tags.a:b"
+	description = "Policy Pack Testing"
+	title = "policy_pack_settings"
+%s
+	setting {
+		type       = "tmod:@turbot/provider-policy-test#/policy/types/integerPolicy"
+		value      = "%s"
+		precedence = "RECOMMENDED"
+	}
+
+	setting {
+		type           = "tmod:@turbot/aws-s3#/policy/types/bucketApprovedUsage"
+		template       = "Approved"
+		template_input = "{ item: bucket { Name } }"
+	}
+}
+`, stringSetting, integerValue)
+}
+
+const testAccPolicyPackStandaloneSettingConfig = `
+resource "turbot_policy_pack" "test" {
+	filter = "resourceType:181381985925765 $.turbot.tags.a:b"
+	description = "Policy Pack Testing"
+	title = "policy_pack_standalone_setting"
+}
+
+resource "turbot_policy_setting" "test" {
+	resource = turbot_policy_pack.test.id
+	type     = "tmod:@turbot/provider-policy-test#/policy/types/stringPolicy"
+	value    = "Skip"
+}
+`
+
+// testAccCheckResourcePolicySettings checks the settings directly on the resource by policy type: the
+// value of each, or nil for a setting with a template, and that there are no others
+func testAccCheckResourcePolicySettings(resource string, expected map[string]interface{}) resource.TestCheckFunc {
+	return func(state *terraform.State) error {
+		rs, ok := state.RootModule().Resources[resource]
+		if !ok {
+			return fmt.Errorf("Not found: %s", resource)
+		}
+		client := testAccProvider.Meta().(*apiClient.Client)
+		settings, err := client.ReadAllPolicySettings(fmt.Sprintf("resource:%s level:self", rs.Primary.ID))
+		if err != nil {
+			return err
+		}
+		if len(settings) != len(expected) {
+			return fmt.Errorf("expected %d settings on resource, got %d", len(expected), len(settings))
+		}
+		for _, setting := range settings {
+			value, ok := expected[setting.Type.Uri]
+			if !ok {
+				return fmt.Errorf("unexpected setting of policy type %s on resource", setting.Type.Uri)
+			}
+			if fmt.Sprintf("%v", setting.Value) != fmt.Sprintf("%v", value) && !(value == nil && setting.Value == nil) {
+				return fmt.Errorf("expected setting of policy type %s to be %v, got %v", setting.Type.Uri, value, setting.Value)
+			}
+		}
+		return nil
+	}
+}
+
+// testAccCreateResourcePolicySetting makes a setting on the resource outside of terraform
+func testAccCreateResourcePolicySetting(resource, policyType, valueSource string) resource.TestCheckFunc {
+	return func(state *terraform.State) error {
+		rs, ok := state.RootModule().Resources[resource]
+		if !ok {
+			return fmt.Errorf("Not found: %s", resource)
+		}
+		client := testAccProvider.Meta().(*apiClient.Client)
+		_, err := client.CreatePolicySetting(map[string]interface{}{
+			"resource":    rs.Primary.ID,
+			"type":        policyType,
+			"valueSource": valueSource,
+		})
+		return err
+	}
+}
@@ -2,11 +2,13 @@ package turbot
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
+	"errors"
 	"github.com/go-yaml/yaml"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/turbot/terraform-provider-turbot/apiClient"
-	"github.com/turbot/terraform-provider-turbot/errors"
 	"github.com/turbot/terraform-provider-turbot/helpers"
 )
 
@@ -50,6 +52,17 @@ func resourceTurbotPolicySetting() *schema.Resource {
 				Optional:         true,
 				DiffSuppressFunc: suppressIfEncryptedOrValueSourceMatches,
 			},
+			// the map form of value, for a policy whose value is an object: each property is a map element,
+			// so a plan shows the properties that changed rather than the whole document.
+			// See helpers.ObjectFromStringMap for how element values are decoded. An array has no map form,
+			// so an array-valued policy is set with value.
+			"value_object": {
+				Type:             schema.TypeMap,
+				Optional:         true,
+				Elem:             &schema.Schema{Type: schema.TypeString},
+				DiffSuppressFunc: suppressIfObjectValueMatches,
+				ConflictsWith:    []string{"value", "pgp_key"},
+			},
 			"value_source": {
 				Type:      schema.TypeString,
 				Computed:  true,
@@ -99,7 +112,116 @@ func resourceTurbotPolicySetting() *schema.Resource {
 				Optional: true,
 			},
 		},
+		CustomizeDiff: resourceTurbotPolicySettingCustomizeDiff,
+	}
+}
+
+// resourceTurbotPolicySettingCustomizeDiff catches at plan time what the server would otherwise only
+// reject at apply time: a precedence that is not REQUIRED or RECOMMENDED, a validity window that ends
+// before it starts, and a value that the policy type's schema rejects
+func resourceTurbotPolicySettingCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if d.NewValueKnown("precedence") {
+		if precedence := d.Get("precedence").(string); precedence != "REQUIRED" && precedence != "RECOMMENDED" {
+			return fmt.Errorf("invalid precedence %q: must be REQUIRED or RECOMMENDED", precedence)
+		}
+	}
+	if d.NewValueKnown("valid_from_timestamp") && d.NewValueKnown("valid_to_timestamp") {
+		// a timestamp that is not RFC 3339 is left to the server
+		validFrom, errFrom := time.Parse(time.RFC3339, d.Get("valid_from_timestamp").(string))
+		validTo, errTo := time.Parse(time.RFC3339, d.Get("valid_to_timestamp").(string))
+		if errFrom == nil && errTo == nil && !validFrom.Before(validTo) {
+			return fmt.Errorf("valid_from_timestamp %s must be earlier than valid_to_timestamp %s", d.Get("valid_from_timestamp"), d.Get("valid_to_timestamp"))
+		}
+	}
+	// only a changed value is checked, so an unchanged plan does not read the policy type
+	if !d.HasChange("value") && !d.HasChange("value_object") && !d.HasChange("type") {
+		return nil
+	}
+	for _, property := range []string{"type", "value", "value_object", "template"} {
+		if !d.NewValueKnown(property) {
+			return nil
+		}
+	}
+	// a templated setting's value is only known once the template is run
+	if d.Get("template").(string) != "" {
+		return nil
 	}
+	return validatePolicySettingValue(d, meta)
+}
+
+// validatePolicySettingValue checks the value, or the value object, against the policy type's schema
+func validatePolicySettingValue(d *schema.ResourceDiff, meta interface{}) error {
+	client := meta.(*apiClient.Client)
+	policyTypeUri := d.Get("type").(string)
+	value := d.Get("value").(string)
+	valueObject := d.Get("value_object").(map[string]interface{})
+	if value == "" && len(valueObject) == 0 {
+		return nil
+	}
+
+	policyType, err := client.FindPolicyType(policyTypeUri)
+	if err != nil {
+		return err
+	}
+	policySchema, ok := policyType.Schema.(map[string]interface{})
+	// a policy type that is not installed yet - its mod may be installed in the same apply - is left
+	// to the create, which reports it
+	if policyType.ModUri == "" || !ok {
+		return nil
+	}
+
+	var violations []helpers.SchemaViolation
+	if len(valueObject) > 0 {
+		violations = helpers.ValidateJsonSchema(helpers.ObjectFromStringMap(valueObject), policySchema)
+	} else {
+		violations = validatePolicyValueString(value, policySchema)
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+	var messages []string
+	for _, violation := range violations {
+		messages = append(messages, violation.Error())
+	}
+	return fmt.Errorf("invalid value for policy type %s:\n  %s", policyTypeUri, strings.Join(messages, "\n  "))
+}
+
+// validatePolicyValueString checks a value as the create does: as a string and, if the schema rejects
+// the string, as YAML. The violations reported are those of the string if the schema allows a string -
+// "Check: Enable" is a misspelt enum value, not a YAML object - and otherwise those of the YAML value.
+func validatePolicyValueString(value string, policySchema map[string]interface{}) []helpers.SchemaViolation {
+	violations := helpers.ValidateJsonSchema(value, policySchema)
+	if len(violations) == 0 {
+		return nil
+	}
+	// NOTE: ParseYamlString returns the string unchanged if it is not valid YAML
+	parsed, _ := helpers.ParseYamlString(value)
+	if _, ok := parsed.(string); ok {
+		return violations
+	}
+	yamlViolations := helpers.ValidateJsonSchema(parsed, policySchema)
+	if len(yamlViolations) == 0 {
+		return nil
+	}
+	if policySchemaAllowsString(policySchema) {
+		return violations
+	}
+	return yamlViolations
+}
+
+func policySchemaAllowsString(policySchema map[string]interface{}) bool {
+	switch schemaType := policySchema["type"].(type) {
+	case string:
+		return schemaType == "string"
+	case []interface{}:
+		for _, t := range schemaType {
+			if t == "string" {
+				return true
+			}
+		}
+		return false
+	}
+	return true
 }
 
 func resourceTurbotPolicySettingExists(d *schema.ResourceData, meta interface{}) (b bool, e error) {
@@ -110,7 +232,7 @@ func resourceTurbotPolicySettingExists(d *schema.ResourceData, meta interface{})
 
 	_, err := client.ReadPolicySetting(id)
 	if err != nil {
-		if errors.NotFoundError(err) {
+		if errors.Is(err, apiClient.ErrNotFound) {
 			return false, nil
 		}
 		return false, err
@@ -150,6 +272,7 @@ func resourceTurbotPolicySettingCreate(d *schema.ResourceData, meta interface{})
 	// 1) pass value as 'value'
 	// 2) pass value as 'valueSource'. update d.value to be the yaml parsed version of 'value'
 	input := mapFromResourceData(d, policySettingInputProperties)
+	setValueObjectInput(d, input)
 
 	if value, ok := d.GetOk("template_input"); ok {
 		// NOTE: ParseYamlString doesn't validate input as valid YAML format, on error it returns value
@@ -159,7 +282,8 @@ func resourceTurbotPolicySettingCreate(d *schema.ResourceData, meta interface{})
 
 	policySetting, err := client.CreatePolicySetting(input)
 	if err != nil {
-		if !errors.FailedValidationError(err) {
+		// an object value has no string form to retry as valueSource
+		if _, ok := input["value"].(string); !ok || !errors.Is(err, apiClient.ErrSchemaValidation) {
 			d.SetId("")
 			return err
 		}
@@ -209,7 +333,7 @@ func resourceTurbotPolicySettingRead(d *schema.ResourceData, meta interface{}) e
 
 	policySetting, err := client.ReadPolicySetting(id)
 	if err != nil {
-		if errors.NotFoundError(err) {
+		if errors.Is(err, apiClient.ErrNotFound) {
 			// setting was not found - clear id
 			d.SetId("")
 		}
@@ -255,6 +379,7 @@ func resourceTurbotPolicySettingUpdate(d *schema.ResourceData, meta interface{})
 	// 1) pass value as 'value'
 	// 2) pass value as 'valueSource'. update d.value to be the yaml parsed version of 'value'
 	input := mapFromResourceData(d, getPolicySettingUpdateProperties())
+	setValueObjectInput(d, input)
 	input["id"] = id
 
 	var err error
@@ -266,7 +391,8 @@ func resourceTurbotPolicySettingUpdate(d *schema.ResourceData, meta interface{})
 
 	policySetting, err := client.UpdatePolicySetting(input)
 	if err != nil {
-		if !errors.FailedValidationError(err) {
+		// an object value has no string form to retry as valueSource
+		if _, ok := input["value"].(string); !ok || !errors.Is(err, apiClient.ErrSchemaValidation) {
 			d.SetId("")
 			return err
 		}
@@ -389,6 +515,28 @@ func storeValue(d *schema.ResourceData, setting *apiClient.PolicySetting) error
 		}
 		d.Set("value_source", encryptedValueSource)
 		d.Set("value_source_key_fingerprint", valueSourceFingerprint)
+	} else if _, ok := d.GetOk("value_object"); ok {
+		valueObject, isObject := setting.Value.(map[string]interface{})
+		if !isObject {
+			// an array or a scalar has no map form, so store it as value - value_object is cleared, and
+			// the plan shows the change back to the configured object
+			value, err := helpers.InterfaceToStringOrJson(setting.Value)
+			if err != nil {
+				return err
+			}
+			d.Set("value_object", nil)
+			d.Set("value", value)
+			d.Set("value_source", setting.ValueSource)
+			return nil
+		}
+		// value is not in config, so leave it empty rather than show a diff against it
+		valueObjectMap, err := helpers.StringMapFromObject(valueObject)
+		if err != nil {
+			return err
+		}
+		d.Set("value", nil)
+		d.Set("value_object", valueObjectMap)
+		d.Set("value_source", setting.ValueSource)
 	} else {
 		d.Set("value", helpers.InterfaceToString(setting.Value))
 		d.Set("value_source", setting.ValueSource)
@@ -397,6 +545,13 @@ func storeValue(d *schema.ResourceData, setting *apiClient.PolicySetting) error
 	return nil
 }
 
+// if value_object is set, send it as the object value
+func setValueObjectInput(d *schema.ResourceData, input map[string]interface{}) {
+	if valueObject, ok := d.GetOk("value_object"); ok {
+		input["value"] = helpers.ObjectFromStringMap(valueObject.(map[string]interface{}))
+	}
+}
+
 func suppressIfTemplateInputEquivalent(k, old, new string, d *schema.ResourceData) bool {
 	if old == "" {
 		return false
@@ -1,11 +1,12 @@
 package turbot
 
 import (
+	"errors"
 	"fmt"
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/terraform"
 	"github.com/turbot/terraform-provider-turbot/apiClient"
-	"github.com/turbot/terraform-provider-turbot/errors"
+	"regexp"
 	"strings"
 	"testing"
 )
@@ -137,6 +138,42 @@ func TestAccPolicySetting_Array(t *testing.T) {
 	})
 }
 
+func TestAccPolicySetting_ValueObject(t *testing.T) {
+	resourceName := "turbot_policy_setting.test_policy"
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPolicySettingDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPolicySettingValueObjectConfig("a1"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPolicySettingExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "value_object.a", "a1"),
+					resource.TestCheckResourceAttr(resourceName, "value_object.n", "1"),
+					resource.TestCheckResourceAttr(resourceName, "value", ""),
+				),
+			},
+			{
+				Config: testAccPolicySettingValueObjectConfig("a2"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "value_object.a", "a2"),
+					// an array has no map form - the next refresh reads it as value, and plans the object back
+					testAccUpdatePolicySettingValue(resourceName, []interface{}{"x", "y"}),
+				),
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccPolicySettingValueObjectConfig("a2"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "value_object.a", "a2"),
+					resource.TestCheckResourceAttr(resourceName, "value", ""),
+				),
+			},
+		},
+	})
+}
+
 func TestAccPolicySetting_ArrayEncrypted(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
@@ -218,6 +255,44 @@ func TestAccPolicySetting_Precedence_Value_Check(t *testing.T) {
 	})
 }
 
+// each of these mistakes is caught when planning, before anything is applied
+func TestAccPolicySetting_PlanValidation(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPolicySettingDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccPolicySettingStringConfig(bucketApprovedUsagePolicyType, "Check: Approve", "REQUIRED"),
+				ExpectError: regexp.MustCompile(`did you mean "Check: Approved"\?`),
+			},
+			{
+				Config:      testAccPolicySettingStringConfig(intPolicyType, "five", "REQUIRED"),
+				ExpectError: regexp.MustCompile(`must be integer, got string`),
+			},
+			{
+				Config:      testAccPolicySettingStringConfig(stringArrayPolicyType, "<<EOF\n- a\n- b: c\nEOF", "REQUIRED"),
+				ExpectError: regexp.MustCompile(`/1 must be string, got object`),
+			},
+			{
+				Config:      testAccPolicySettingStringConfig(stringPolicyType, "testValue", "MANDATORY"),
+				ExpectError: regexp.MustCompile(`must be REQUIRED or RECOMMENDED`),
+			},
+			{
+				Config:      testAccPolicySettingValidityConfig("2030-01-02T00:00:00Z", "2030-01-01T00:00:00Z"),
+				ExpectError: regexp.MustCompile(`must be earlier than valid_to_timestamp`),
+			},
+			{
+				Config: testAccPolicySettingValidityConfig("2030-01-01T00:00:00Z", "2030-01-02T00:00:00Z"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPolicySettingExists("turbot_policy_setting.test_policy"),
+					resource.TestCheckResourceAttr("turbot_policy_setting.test_policy", "value", "Check: Approved"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccPolicySetting_TemplateInputJsonValue(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
@@ -294,6 +369,8 @@ var stringPolicyType = "tmod:@turbot/provider-policy-test#/policy/types/stringPo
 var intPolicyType = "tmod:@turbot/provider-policy-test#/policy/types/integerPolicy"
 var stringArrayPolicyType = "tmod:@turbot/provider-policy-test#/policy/types/stringArrayPolicy"
 var secretPolicyType = "tmod:@turbot/provider-policy-test#/policy/types/secretPolicy"
+var bucketApprovedUsagePolicyType = "tmod:@turbot/aws-s3#/policy/types/bucketApprovedUsage"
+var objectOrArrayPolicyType = "tmod:@turbot/provider-policy-test#/policy/types/objectOrArrayPolicy"
 var stringPolicyTemplate = "{% if $.account.Id == '650022101893' %}Skip{% else %}'Check: Configured'{% endif %}"
 var stringPolicyTemplateInput = "{ account{ Id } }"
 
@@ -406,6 +483,29 @@ resource "turbot_policy_setting" "test_policy" {
 	return config
 }
 
+func testAccPolicySettingValidityConfig(validFrom, validTo string) string {
+	return fmt.Sprintf(`
+resource "turbot_policy_setting" "test_policy" {
+	resource = "tmod:@turbot/turbot#/"
+	type = "%s"
+	value = "Check: Approved"
+	valid_from_timestamp = "%s"
+	valid_to_timestamp = "%s"
+}`, bucketApprovedUsagePolicyType, validFrom, validTo)
+}
+
+func testAccPolicySettingValueObjectConfig(a string) string {
+	return fmt.Sprintf(`
+resource "turbot_policy_setting" "test_policy" {
+	resource = "tmod:@turbot/turbot#/"
+	type = "%s"
+	value_object = {
+		a = "%s"
+		n = 1
+	}
+}`, objectOrArrayPolicyType, a)
+}
+
 func testAccPolicySettingIntConfig(policyType string, value int, precedence string) string {
 	return buildConfig(policyType, fmt.Sprintf("%d", value), precedence)
 }
@@ -461,7 +561,7 @@ func testAccCheckPolicySettingDestroy(s *terraform.State) error {
 			if err == nil {
 				return fmt.Errorf("Alert still exists")
 			}
-			if !errors.NotFoundError(err) {
+			if !errors.Is(err, apiClient.ErrNotFound) {
 				return fmt.Errorf("expected 'not found' error, got %s", err)
 			}
 		}
@@ -469,3 +569,16 @@ func testAccCheckPolicySettingDestroy(s *terraform.State) error {
 
 	return nil
 }
+
+// testAccUpdatePolicySettingValue changes the value of the setting outside of terraform
+func testAccUpdatePolicySettingValue(resource string, value interface{}) resource.TestCheckFunc {
+	return func(state *terraform.State) error {
+		rs, ok := state.RootModule().Resources[resource]
+		if !ok {
+			return fmt.Errorf("Not found: %s", resource)
+		}
+		client := testAccProvider.Meta().(*apiClient.Client)
+		_, err := client.UpdatePolicySetting(map[string]interface{}{"id": rs.Primary.ID, "value": value})
+		return err
+	}
+}
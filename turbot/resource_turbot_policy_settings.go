@@ -0,0 +1,156 @@
+package turbot
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/turbot/terraform-provider-turbot/apiClient"
+)
+
+// turbot_policy_settings manages many policy settings on one resource, one per policy type. By default
+// it manages only the settings of the configured types; when authoritative it owns every setting
+// directly on the resource, deleting settings made outside of the config.
+func resourceTurbotPolicySettings() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceTurbotPolicySettingsCreate,
+		Read:   resourceTurbotPolicySettingsRead,
+		Update: resourceTurbotPolicySettingsUpdate,
+		Delete: resourceTurbotPolicySettingsDelete,
+		Exists: resourceTurbotPolicySettingsExists,
+		Importer: &schema.ResourceImporter{
+			State: resourceTurbotPolicySettingsImport,
+		},
+		Schema: map[string]*schema.Schema{
+			// aka of the resource the settings are on
+			"resource": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: suppressIfAkaMatches("resource_akas"),
+			},
+			"resource_akas": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			// the settings, one per policy type. Settings are matched to the blocks by type, so a changed
+			// block updates its setting in place.
+			"setting": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem:     policySettingBlockResource(),
+			},
+			"authoritative": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+		},
+	}
+}
+
+func resourceTurbotPolicySettingsExists(d *schema.ResourceData, meta interface{}) (b bool, e error) {
+	client := meta.(*apiClient.Client)
+	id := d.Id()
+	return client.ResourceExists(id)
+}
+
+func resourceTurbotPolicySettingsCreate(d *schema.ResourceData, meta interface{}) error {
+	// the settings are keyed by the resource id, so a config naming the resource by aka still matches
+	// the settings an import of the id reports
+	blocks := d.Get("setting").(*schema.Set).List()
+	apply := func(client *apiClient.Client, id string) error {
+		return updatePolicySettingBlocks(client, id, blocks, policySettingsOwner(d, blocks))
+	}
+	return createOnResource(d, meta, d.Get("resource").(string), apply, resourceTurbotPolicySettingsRead)
+}
+
+func resourceTurbotPolicySettingsRead(d *schema.ResourceData, meta interface{}) error {
+	return readPolicySettings(d, meta, policySettingsOwner(d, d.Get("setting").(*schema.Set).List()))
+}
+
+func resourceTurbotPolicySettingsUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*apiClient.Client)
+	id := d.Id()
+
+	if d.HasChange("setting") || d.HasChange("authoritative") {
+		// a setting removed from the config is deleted, as is one of a type no longer configured
+		old, new := d.GetChange("setting")
+		blocks := new.(*schema.Set).List()
+		owns := policySettingsOwner(d, append(old.(*schema.Set).List(), blocks...))
+		if err := updatePolicySettingBlocks(client, id, blocks, owns); err != nil {
+			return err
+		}
+	}
+	return resourceTurbotPolicySettingsRead(d, meta)
+}
+
+func resourceTurbotPolicySettingsDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*apiClient.Client)
+	id := d.Id()
+
+	// delete only the settings in the state - the settings of other types are left alone
+	err := updatePolicySettingBlocks(client, id, nil, policySettingsOwner(d, d.Get("setting").(*schema.Set).List()))
+	// deleting the resource deletes its settings, so they may already be gone
+	if err != nil && !errors.Is(err, apiClient.ErrNotFound) {
+		return err
+	}
+
+	// clear the id to show we have deleted
+	d.SetId("")
+	return nil
+}
+
+// the import reports every setting directly on the resource, as there is no config to select types by
+func resourceTurbotPolicySettingsImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if err := readPolicySettings(d, meta, ownsAllPolicySettings); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+// readPolicySettings reads the settings directly on the resource in one list query, and stores those of
+// the types the resource owns
+func readPolicySettings(d *schema.ResourceData, meta interface{}, owns func(string) bool) error {
+	client := meta.(*apiClient.Client)
+	id := d.Id()
+
+	settings, err := client.ReadAllPolicySettings(fmt.Sprintf("resource:%s level:self", id))
+	if err != nil {
+		if errors.Is(err, apiClient.ErrNotFound) {
+			// resource was not found - clear id
+			d.SetId("")
+		}
+		return err
+	}
+
+	// a setting is stored as its configured block when it matches it, so a value the API formats
+	// differently from the config does not diff
+	configured := configuredPolicySettingBlocks(d.Get("setting").(*schema.Set).List())
+	var blocks []interface{}
+	for _, setting := range settings {
+		if !owns(setting.Type.Uri) {
+			continue
+		}
+		block, err := policySettingBlock(setting, configured[setting.Type.Uri])
+		if err != nil {
+			return err
+		}
+		blocks = append(blocks, block)
+	}
+	d.Set("setting", blocks)
+	d.Set("resource", id)
+	// set resource_akas property by loading resource and fetching the akas
+	return storeAkas(id, "resource_akas", d, meta)
+}
+
+// policySettingsOwner returns whether the resource owns the settings of a policy type: every type if
+// it is authoritative, otherwise the types of the blocks
+func policySettingsOwner(d *schema.ResourceData, blocks []interface{}) func(string) bool {
+	if d.Get("authoritative").(bool) {
+		return ownsAllPolicySettings
+	}
+	return ownsPolicySettingTypes(blocks)
+}
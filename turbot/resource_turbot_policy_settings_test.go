@@ -0,0 +1,158 @@
+package turbot
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// test suites
+func TestAccPolicySettings_Basic(t *testing.T) {
+	resourceName := "turbot_policy_settings.test"
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckFolderDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPolicySettingsConfig("5", false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourcePolicySettings(resourceName, map[string]interface{}{
+						stringPolicyType: "Skip",
+						intPolicyType:    5,
+					}),
+					resource.TestCheckResourceAttr(resourceName, "setting.#", "2"),
+					// a setting of a type that is not configured is not managed, so it does not diff
+					testAccCreateResourcePolicySetting(resourceName, stringArrayPolicyType, "- a\n- b"),
+				),
+			},
+			{
+				Config: testAccPolicySettingsConfig("7", false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourcePolicySettings(resourceName, map[string]interface{}{
+						stringPolicyType:      "Skip",
+						intPolicyType:         7,
+						stringArrayPolicyType: []interface{}{"a", "b"},
+					}),
+					resource.TestCheckResourceAttr(resourceName, "setting.#", "2"),
+				),
+			},
+			{
+				// an import reports every setting on the folder, the unmanaged array as its YAML source
+				ResourceName: resourceName,
+				ImportState:  true,
+				ImportStateCheck: func(states []*terraform.InstanceState) error {
+					if len(states) != 1 || states[0].Attributes["setting.#"] != "3" {
+						return fmt.Errorf("expected an import of 3 settings, got %v", states)
+					}
+					for key, value := range states[0].Attributes {
+						if strings.HasSuffix(key, ".type") && value == stringArrayPolicyType {
+							if reported := states[0].Attributes[strings.TrimSuffix(key, ".type")+".value"]; reported != "- a\n- b" {
+								return fmt.Errorf("expected the array setting to be reported as its YAML source, got %q", reported)
+							}
+							return nil
+						}
+					}
+					return fmt.Errorf("no setting of policy type %s was imported", stringArrayPolicyType)
+				},
+			},
+			{
+				// once authoritative, the unmanaged setting is deleted
+				Config: testAccPolicySettingsConfig("7", true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourcePolicySettings(resourceName, map[string]interface{}{
+						stringPolicyType: "Skip",
+						intPolicyType:    7,
+					}),
+					resource.TestCheckResourceAttr(resourceName, "setting.#", "2"),
+					resource.TestCheckResourceAttr(resourceName, "authoritative", "true"),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"authoritative"},
+			},
+		},
+	})
+}
+
+func TestAccPolicySettings_Aka(t *testing.T) {
+	resourceName := "turbot_policy_settings.test"
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckFolderDestroy,
+		Steps: []resource.TestStep{
+			{
+				// the folder named by aka, the settings are still keyed by its id
+				Config: testAccPolicySettingsAkaConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(resourceName, "id", "turbot_folder.test", "id"),
+					testAccCheckResourcePolicySettings(resourceName, map[string]interface{}{
+						stringPolicyType: "Skip",
+					}),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+				// the import names the folder by id, and has no config to be authoritative by
+				ImportStateVerifyIgnore: []string{"resource", "authoritative"},
+			},
+		},
+	})
+}
+
+// configs
+func testAccPolicySettingsConfig(integerValue string, authoritative bool) string {
+	return fmt.Sprintf(`
+resource "turbot_folder" "test" {
+	parent = "tmod:@turbot/turbot#/"
+	title = "provider_test"
+	description = "test folder"
+}
+
+resource "turbot_policy_settings" "test" {
+	resource      = turbot_folder.test.id
+	authoritative = %t
+
+	setting {
+		type  = "%s"
+		value = "Skip"
+		note  = "skip by default"
+	}
+
+	setting {
+		type       = "%s"
+		value      = "%s"
+		precedence = "RECOMMENDED"
+	}
+}
+`, authoritative, stringPolicyType, intPolicyType, integerValue)
+}
+
+func testAccPolicySettingsAkaConfig() string {
+	return fmt.Sprintf(`
+resource "turbot_folder" "test" {
+	parent = "tmod:@turbot/turbot#/"
+	title = "provider_test"
+	description = "test folder"
+	akas = ["provider_test_policy_settings"]
+}
+
+resource "turbot_policy_settings" "test" {
+	resource = turbot_folder.test.akas[0]
+
+	setting {
+		type  = "%s"
+		value = "Skip"
+	}
+}
+`, stringPolicyType)
+}
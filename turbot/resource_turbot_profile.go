@@ -1,9 +1,9 @@
 package turbot
 
 import (
+	"errors"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/turbot/terraform-provider-turbot/apiClient"
-	"github.com/turbot/terraform-provider-turbot/errors"
 	"github.com/turbot/terraform-provider-turbot/helpers"
 )
 
@@ -137,7 +137,7 @@ func resourceTurbotProfileRead(d *schema.ResourceData, meta interface{}) error {
 
 	profile, err := client.ReadProfile(id)
 	if err != nil {
-		if errors.NotFoundError(err) {
+		if errors.Is(err, apiClient.ErrNotFound) {
 			// profile was not found - clear id
 			d.SetId("")
 		}
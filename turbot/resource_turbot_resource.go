@@ -1,16 +1,21 @@
 package turbot
 
 import (
+	"errors"
 	"fmt"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/turbot/terraform-provider-turbot/apiClient"
-	"github.com/turbot/terraform-provider-turbot/errors"
 	"github.com/turbot/terraform-provider-turbot/helpers"
 	"strings"
 )
 
 var resourceProperties = []interface{}{"parent", "type", "tags", "akas"}
 
+// data and metadata can each be set by one of four properties: a JSON string or its map form, each
+// managing either only the properties it names or the full content
+var resourceDataProperties = []string{"data", "data_object", "full_data", "full_data_object"}
+var resourceMetadataProperties = []string{"metadata", "metadata_object", "full_metadata", "full_metadata_object"}
+
 func getResourceUpdateProperties() []interface{} {
 	excludedProperties := []string{"type"}
 	return helpers.RemoveProperties(resourceProperties, excludedProperties)
@@ -70,6 +75,37 @@ func resourceTurbotResource() *schema.Resource {
 				DiffSuppressFunc: suppressIfDataMatches,
 				ConflictsWith:    []string{"metadata"},
 			},
+			// the map forms of the properties above: each top-level property is a map element, so a plan
+			// shows the properties that changed rather than the whole document.
+			// See helpers.ObjectFromStringMap for how element values are decoded.
+			"data_object": {
+				Type:             schema.TypeMap,
+				Optional:         true,
+				Elem:             &schema.Schema{Type: schema.TypeString},
+				DiffSuppressFunc: suppressIfObjectValueMatches,
+				ConflictsWith:    []string{"data", "full_data", "full_data_object"},
+			},
+			"metadata_object": {
+				Type:             schema.TypeMap,
+				Optional:         true,
+				Elem:             &schema.Schema{Type: schema.TypeString},
+				DiffSuppressFunc: suppressIfObjectValueMatches,
+				ConflictsWith:    []string{"metadata", "full_metadata", "full_metadata_object"},
+			},
+			"full_data_object": {
+				Type:             schema.TypeMap,
+				Optional:         true,
+				Elem:             &schema.Schema{Type: schema.TypeString},
+				DiffSuppressFunc: suppressIfObjectValueMatches,
+				ConflictsWith:    []string{"data", "full_data", "data_object"},
+			},
+			"full_metadata_object": {
+				Type:             schema.TypeMap,
+				Optional:         true,
+				Elem:             &schema.Schema{Type: schema.TypeString},
+				DiffSuppressFunc: suppressIfObjectValueMatches,
+				ConflictsWith:    []string{"metadata", "full_metadata", "metadata_object"},
+			},
 			"tags": {
 				Type:     schema.TypeMap,
 				Optional: true,
@@ -82,9 +118,60 @@ func resourceTurbotResource() *schema.Resource {
 				},
 			},
 		},
+		CustomizeDiff: resourceTurbotResourceCustomizeDiff,
 	}
 }
 
+// resourceTurbotResourceCustomizeDiff validates new or changed data against the resource type's
+// createSchema, or its updateSchema for an existing resource, so malformed data fails the plan rather
+// than the apply
+func resourceTurbotResourceCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	client := meta.(*apiClient.Client)
+	if !d.NewValueKnown("type") {
+		return nil
+	}
+	property, content, ok := getContentPropertyFromDiff(d, resourceDataProperties)
+	// unchanged data is not validated, so an unchanged plan does not read the resource type
+	if !ok || (d.Id() != "" && !d.HasChange(property)) || !d.NewValueKnown(property) {
+		return nil
+	}
+	data, err := contentFromValue(content)
+	if err != nil {
+		return fmt.Errorf("%s is not a JSON object: %s", property, err.Error())
+	}
+
+	resourceTypeUri := d.Get("type").(string)
+	resourceType, err := client.ReadCachedResourceType(resourceTypeUri)
+	if err != nil {
+		// a resource type that is not installed yet - its mod may be installed in the same apply - is
+		// left to the create, which reports it
+		if errors.Is(err, apiClient.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	dataSchema := resourceType.CreateSchema
+	if d.Id() != "" {
+		dataSchema = resourceType.UpdateSchema
+		// the properties the update schema forbids are not sent - see buildUpdatePayloadForData
+		data = buildDataUpdateProperties(data, apiClient.UpdateSchemaExcludedProperties(dataSchema))
+	}
+	schemaMap, ok := dataSchema.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	violations := helpers.ValidateJsonSchema(data, schemaMap)
+	if len(violations) == 0 {
+		return nil
+	}
+	var messages []string
+	for _, violation := range violations {
+		messages = append(messages, violation.Error())
+	}
+	return fmt.Errorf("invalid %s for resource type %s:\n  %s", property, resourceTypeUri, strings.Join(messages, "\n  "))
+}
+
 func resourceTurbotResourceExists(d *schema.ResourceData, meta interface{}) (b bool, e error) {
 	client := meta.(*apiClient.Client)
 	id := d.Id()
@@ -137,7 +224,7 @@ func resourceTurbotResourceRead(d *schema.ResourceData, meta interface{}) error
 	// read full resource
 	resource, err := client.ReadFullResource(id)
 	if err != nil {
-		if errors.NotFoundError(err) {
+		if errors.Is(err, apiClient.ErrNotFound) {
 			// resource was not found - clear id
 			d.SetId("")
 		}
@@ -151,12 +238,24 @@ func resourceTurbotResourceRead(d *schema.ResourceData, meta interface{}) error
 			return err
 		}
 		d.Set("data", data)
+	} else if _, ok := d.GetOk("data_object"); ok {
+		dataObject, err := getObjectValueForKey(d, "data_object", resource.Data)
+		if err != nil {
+			return err
+		}
+		d.Set("data_object", dataObject)
 	} else if _, ok := d.GetOk("full_data"); ok {
 		// if full_data is set, include all data read from API
 		if data, err = helpers.MapToJsonString(resource.Data); err != nil {
 			return fmt.Errorf("error retrieving data properties: %s", err.Error())
 		}
 		d.Set("full_data", data)
+	} else if _, ok := d.GetOk("full_data_object"); ok {
+		dataObject, err := helpers.StringMapFromObject(resource.Data)
+		if err != nil {
+			return fmt.Errorf("error retrieving data properties: %s", err.Error())
+		}
+		d.Set("full_data_object", dataObject)
 	}
 
 	// In the import case, we won't have this
@@ -167,12 +266,24 @@ func resourceTurbotResourceRead(d *schema.ResourceData, meta interface{}) error
 			return fmt.Errorf("error retrieving metadata properties: %s", err.Error())
 		}
 		d.Set("metadata", metadata)
+	} else if _, ok := d.GetOk("metadata_object"); ok {
+		metadataObject, err := getObjectValueForKey(d, "metadata_object", resource.Turbot.Custom)
+		if err != nil {
+			return fmt.Errorf("error retrieving metadata properties: %s", err.Error())
+		}
+		d.Set("metadata_object", metadataObject)
 	} else if _, ok := d.GetOk("full_metadata"); ok {
 		// if full_metadata is set, include all data read from API
 		if metadata, err = helpers.MapToJsonString(resource.Turbot.Custom); err != nil {
 			return fmt.Errorf("error retrieving metadata properties: %s", err.Error())
 		}
 		d.Set("full_metadata", metadata)
+	} else if _, ok := d.GetOk("full_metadata_object"); ok {
+		metadataObject, err := helpers.StringMapFromObject(resource.Turbot.Custom)
+		if err != nil {
+			return fmt.Errorf("error retrieving metadata properties: %s", err.Error())
+		}
+		d.Set("full_metadata_object", metadataObject)
 	}
 	// set parent_akas property by loading resource and fetching the akas
 	if err := storeAkas(resource.Turbot.ParentId, "parent_akas", d, meta); err != nil {
@@ -194,28 +305,15 @@ func resourceTurbotResourceUpdate(d *schema.ResourceData, meta interface{}) erro
 	if err != nil {
 		return err
 	}
-	// Identify data property (data/full_data)
-	var dataProperty string
-	var ok bool
-	if _, ok = d.GetOk("data"); ok {
-		dataProperty = "data"
-	} else if _, ok = d.GetOk("full_data"); ok {
-		dataProperty = "full_data"
-	}
-	if ok {
+	// Identify data property (data/data_object/full_data/full_data_object)
+	if dataProperty, _, ok := getContentProperty(d, resourceDataProperties); ok {
 		input["data"], err = buildUpdatePayloadForData(d, client, dataProperty)
 		if err != nil {
 			return err
 		}
 	}
-	// Identify metadata property (metadata/full_netadata)
-	var metaProperty string
-	if _, ok = d.GetOk("metadata"); ok {
-		metaProperty = "metadata"
-	} else if _, ok = d.GetOk("full_metadata"); ok {
-		metaProperty = "full_metadata"
-	}
-	if ok {
+	// Identify metadata property (metadata/metadata_object/full_metadata/full_metadata_object)
+	if metaProperty, _, ok := getContentProperty(d, resourceMetadataProperties); ok {
 		input["metadata"], err = buildUpdatePayloadForMetadata(d, metaProperty)
 		if err != nil {
 			return err
@@ -268,24 +366,16 @@ func buildDataUpdateProperties(data map[string]interface{}, forbiddenProperties
 
 func buildResourceInput(d *schema.ResourceData, properties []interface{}) (map[string]interface{}, error) {
 	var err error
-	var ok bool
-	var dataString, metaDataString interface{}
 	input := mapFromResourceData(d, properties)
-	if dataString, ok = d.GetOk("data"); !ok {
-		dataString, ok = d.GetOk("full_data")
-	}
-	if ok {
-		if input["data"], err = helpers.JsonStringToMap(dataString.(string)); err != nil {
-			return nil, fmt.Errorf("error build resource mutation input, failed to unmarshal data: \n%s\nerror: %s", dataString, err.Error())
+	if _, data, ok := getContentProperty(d, resourceDataProperties); ok {
+		if input["data"], err = contentFromValue(data); err != nil {
+			return nil, fmt.Errorf("error build resource mutation input, failed to unmarshal data: \n%s\nerror: %s", data, err.Error())
 		}
 	}
-	// convert metadata from json string to map (if present)
-	if metaDataString, ok = d.GetOk("metadata"); !ok {
-		metaDataString, ok = d.GetOk("full_metadata")
-	}
-	if ok {
-		if input["metadata"], err = helpers.JsonStringToMap(metaDataString.(string)); err != nil {
-			return nil, fmt.Errorf("error build resource mutation input, failed to unmarshal data: \n%s\nerror: %s", dataString, err.Error())
+	// convert metadata from json string or map to object (if present)
+	if _, metadata, ok := getContentProperty(d, resourceMetadataProperties); ok {
+		if input["metadata"], err = contentFromValue(metadata); err != nil {
+			return nil, fmt.Errorf("error build resource mutation input, failed to unmarshal data: \n%s\nerror: %s", metadata, err.Error())
 		}
 	}
 	return input, nil
@@ -329,10 +419,65 @@ func suppressIfDataMatches(k, old, new string, d *schema.ResourceData) bool {
 	return oldFormatted == newFormatted
 }
 
+// an element of a *_object map is decoded before sending, so compare decoded values:
+// jsonencode output and the value read back from the API differ only in formatting
+func suppressIfObjectValueMatches(k, old, new string, d *schema.ResourceData) bool {
+	if old == "" || new == "" {
+		return false
+	}
+	return helpers.ObjectValuesAreEqual(old, new)
+}
+
+// getContentProperty returns the first of the given properties which is set, and its value
+func getContentProperty(d *schema.ResourceData, properties []string) (string, interface{}, bool) {
+	for _, property := range properties {
+		if value, ok := d.GetOk(property); ok {
+			return property, value, true
+		}
+	}
+	return "", nil, false
+}
+
+// getContentPropertyFromDiff is getContentProperty for a plan
+func getContentPropertyFromDiff(d *schema.ResourceDiff, properties []string) (string, interface{}, bool) {
+	for _, property := range properties {
+		switch value := d.Get(property).(type) {
+		case string:
+			if value != "" {
+				return property, value, true
+			}
+		case map[string]interface{}:
+			if len(value) > 0 {
+				return property, value, true
+			}
+		}
+	}
+	return "", nil, false
+}
+
+// contentFromValue converts the value of a data or metadata property - a JSON string, or the map
+// of a *_object property - to the object sent to the API
+func contentFromValue(value interface{}) (map[string]interface{}, error) {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		return helpers.ObjectFromStringMap(value), nil
+	case string:
+		return helpers.JsonStringToMap(value)
+	}
+	return nil, fmt.Errorf("unexpected content type %T", value)
+}
+
 func getPropertiesFromConfig(d *schema.ResourceData, key string) (map[string]string, error) {
 	var properties map[string]string = nil
 	var err error = nil
 	if keyValue, ok := d.GetOk(key); ok {
+		if object, ok := keyValue.(map[string]interface{}); ok {
+			properties = map[string]string{}
+			for k := range object {
+				properties[k] = k
+			}
+			return properties, nil
+		}
 		if properties, err = helpers.PropertyMapFromJson(keyValue.(string)); err != nil {
 			return nil, fmt.Errorf("error retrieving properties: %s", err.Error())
 		}
@@ -381,11 +526,11 @@ func markPropertiesForDeletion(d *schema.ResourceData, key string) (map[string]i
 	var err error
 	// fetch old(state-file) and new(config) content
 	if old, new := d.GetChange(key); old != nil {
-		if oldContent, err = helpers.JsonStringToMap(old.(string)); err != nil {
-			return nil, fmt.Errorf("error build resource mutation input, failed to unmarshal content: \n%s\nerror: %s", old.(string), err.Error())
+		if oldContent, err = contentFromValue(old); err != nil {
+			return nil, fmt.Errorf("error build resource mutation input, failed to unmarshal content: \n%s\nerror: %s", old, err.Error())
 		}
-		if newContent, err = helpers.JsonStringToMap(new.(string)); err != nil {
-			return nil, fmt.Errorf("error build resource mutation input, failed to unmarshal content: \n%s\nerror: %s", new.(string), err.Error())
+		if newContent, err = contentFromValue(new); err != nil {
+			return nil, fmt.Errorf("error build resource mutation input, failed to unmarshal content: \n%s\nerror: %s", new, err.Error())
 		}
 		// extract keys from old content not in new
 		excludeContentProperties := helpers.GetOldMapProperties(oldContent, newContent)
@@ -427,3 +572,16 @@ func getResourceType(uri string) string {
 	}
 	return typeUri
 }
+
+// the map form of getStringValueForKey, for the *_object properties
+func getObjectValueForKey(d *schema.ResourceData, key string, readResponse map[string]interface{}) (map[string]interface{}, error) {
+	propertiesOfKey, err := getPropertiesFromConfig(d, key)
+	if err != nil {
+		return nil, err
+	}
+	object, err := helpers.StringMapFromObject(buildResourceMapFromProperties(readResponse, propertiesOfKey))
+	if err != nil {
+		return nil, fmt.Errorf("error building resource data: %s", err.Error())
+	}
+	return object, nil
+}
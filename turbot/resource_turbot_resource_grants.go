@@ -0,0 +1,289 @@
+package turbot
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/turbot/terraform-provider-turbot/apiClient"
+)
+
+// turbot_resource_grants is authoritative: it owns every grant on the resource, removing grants that
+// were made outside of the config - except those to the ignore_identities
+func resourceTurbotResourceGrants() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceTurbotResourceGrantsCreate,
+		Read:   resourceTurbotResourceGrantsRead,
+		Update: resourceTurbotResourceGrantsUpdate,
+		Delete: resourceTurbotResourceGrantsDelete,
+		Exists: resourceTurbotResourceGrantsExists,
+		Importer: &schema.ResourceImporter{
+			State: resourceTurbotResourceGrantsImport,
+		},
+		Schema: map[string]*schema.Schema{
+			// aka of the resource the grants are on
+			"resource": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+				// when doing a diff, the state file will contain the id of the resource but the config contains the aka,
+				// so we need custom diff code
+				DiffSuppressFunc: suppressIfAkaMatches("resource_akas"),
+			},
+			"resource_akas": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			// each grant is stored as the ids or akas the config gives it by, see resourceGrantBlock
+			"grant": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"identity": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"type": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"level": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+					},
+				},
+			},
+			// ids or akas of identities whose grants on the resource are neither reported nor removed,
+			// e.g. break-glass accounts
+			"ignore_identities": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+func resourceTurbotResourceGrantsExists(d *schema.ResourceData, meta interface{}) (b bool, e error) {
+	client := meta.(*apiClient.Client)
+	id := d.Id()
+	return client.ResourceExists(id)
+}
+
+func resourceTurbotResourceGrantsCreate(d *schema.ResourceData, meta interface{}) error {
+	return createOnResource(d, meta, d.Get("resource").(string), func(client *apiClient.Client, id string) error {
+		return updateResourceGrants(client, id, d.Get("grant").(*schema.Set).List(), d.Get("ignore_identities").(*schema.Set))
+	}, resourceTurbotResourceGrantsRead)
+}
+
+func resourceTurbotResourceGrantsRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*apiClient.Client)
+	id := d.Id()
+
+	grants, err := readManagedGrants(client, id, d.Get("ignore_identities").(*schema.Set))
+	if err != nil {
+		if errors.Is(err, apiClient.ErrNotFound) {
+			// resource was not found - clear id
+			d.SetId("")
+		}
+		return err
+	}
+
+	// a grant that is not in the config is reported by its ids, so the plan shows it being removed
+	configured := d.Get("grant").(*schema.Set).List()
+	var blocks []interface{}
+	for _, grant := range grants {
+		block, err := resourceGrantBlock(client, grant, configured)
+		if err != nil {
+			return err
+		}
+		blocks = append(blocks, block)
+	}
+	d.Set("grant", blocks)
+	d.Set("resource", id)
+	// set resource_akas property by loading resource and fetching the akas
+	return storeAkas(id, "resource_akas", d, meta)
+}
+
+func resourceTurbotResourceGrantsUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*apiClient.Client)
+	id := d.Id()
+
+	if d.HasChange("grant") || d.HasChange("ignore_identities") {
+		if err := updateResourceGrants(client, id, d.Get("grant").(*schema.Set).List(), d.Get("ignore_identities").(*schema.Set)); err != nil {
+			return err
+		}
+	}
+	return resourceTurbotResourceGrantsRead(d, meta)
+}
+
+func resourceTurbotResourceGrantsDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*apiClient.Client)
+	id := d.Id()
+
+	grants, err := readManagedGrants(client, id, d.Get("ignore_identities").(*schema.Set))
+	if err != nil {
+		// deleting the resource deletes its grants, so they may already be gone
+		if errors.Is(err, apiClient.ErrNotFound) {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+	// remove only the grants in the state - a grant made since the last refresh is left alone
+	configured := d.Get("grant").(*schema.Set).List()
+	for _, grant := range grants {
+		index, err := matchingGrantBlock(client, grant, configured)
+		if err != nil {
+			return err
+		}
+		if index < 0 {
+			continue
+		}
+		if err := client.DeleteGrant(grant.Turbot.Id); err != nil && !errors.Is(err, apiClient.ErrNotFound) {
+			return err
+		}
+	}
+
+	// clear the id to show we have deleted
+	d.SetId("")
+	return nil
+}
+
+func resourceTurbotResourceGrantsImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if err := resourceTurbotResourceGrantsRead(d, meta); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+// readManagedGrants returns the grants directly on the resource, other than those to the ignored
+// identities
+func readManagedGrants(client *apiClient.Client, resourceId string, ignoreIdentities *schema.Set) ([]apiClient.Grant, error) {
+	grants, err := client.ReadAllGrants(fmt.Sprintf("resource:%s level:self", resourceId))
+	if err != nil {
+		return nil, err
+	}
+	var managed []apiClient.Grant
+	for _, grant := range grants {
+		ignored, err := matchesAnyAka(client, ignoreIdentities.List(), grant.Turbot.ProfileId)
+		if err != nil {
+			return nil, err
+		}
+		if !ignored {
+			managed = append(managed, grant)
+		}
+	}
+	return managed, nil
+}
+
+// updateResourceGrants makes the grants on the resource exactly the configured grants: each grant that
+// is not configured is deleted, and each configured grant that does not exist is created. Grants to
+// the ignored identities are left alone.
+func updateResourceGrants(client *apiClient.Client, resourceId string, configured []interface{}, ignoreIdentities *schema.Set) error {
+	grants, err := readManagedGrants(client, resourceId, ignoreIdentities)
+	if err != nil {
+		return err
+	}
+	// the configured grants that do not exist yet
+	missing := append([]interface{}(nil), configured...)
+	for _, grant := range grants {
+		index, err := matchingGrantBlock(client, grant, missing)
+		if err != nil {
+			return err
+		}
+		if index >= 0 {
+			missing = append(missing[:index], missing[index+1:]...)
+			continue
+		}
+		if err := client.DeleteGrant(grant.Turbot.Id); err != nil && !errors.Is(err, apiClient.ErrNotFound) {
+			return err
+		}
+	}
+	for _, block := range missing {
+		grant := block.(map[string]interface{})
+		input := map[string]interface{}{
+			"resource": resourceId,
+			"identity": grant["identity"],
+			"type":     grant["type"],
+			"level":    grant["level"],
+		}
+		if _, err := client.CreateGrant(input); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resourceGrantBlock returns the configured grant block the grant matches, or a block of the grant's
+// ids if it matches none
+func resourceGrantBlock(client *apiClient.Client, grant apiClient.Grant, configured []interface{}) (interface{}, error) {
+	index, err := matchingGrantBlock(client, grant, configured)
+	if err != nil {
+		return nil, err
+	}
+	if index >= 0 {
+		return configured[index], nil
+	}
+	return map[string]interface{}{
+		"identity": grant.Turbot.ProfileId,
+		"type":     grant.PermissionTypeId,
+		"level":    grant.PermissionLevelId,
+	}, nil
+}
+
+// matchingGrantBlock returns the index of the first of the grant blocks whose identity, type and level
+// each identify those of the grant, by id or aka, or -1 if there is none
+func matchingGrantBlock(client *apiClient.Client, grant apiClient.Grant, blocks []interface{}) (int, error) {
+	for i, block := range blocks {
+		values := block.(map[string]interface{})
+		matches := true
+		for property, id := range map[string]string{
+			"identity": grant.Turbot.ProfileId,
+			"type":     grant.PermissionTypeId,
+			"level":    grant.PermissionLevelId,
+		} {
+			match, err := matchesAnyAka(client, []interface{}{values[property]}, id)
+			if err != nil {
+				return -1, err
+			}
+			if !match {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return i, nil
+		}
+	}
+	return -1, nil
+}
+
+// matchesAnyAka returns whether any of the akas is the id, or one of the akas, of the resource with
+// the given id
+func matchesAnyAka(client *apiClient.Client, akas []interface{}, id string) (bool, error) {
+	if len(akas) == 0 {
+		return false, nil
+	}
+	resourceAkas, err := client.GetResourceAkas(id)
+	if err != nil {
+		return false, err
+	}
+	for _, aka := range akas {
+		if aka.(string) == id {
+			return true, nil
+		}
+		for _, resourceAka := range resourceAkas {
+			if aka.(string) == resourceAka {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,179 @@
+package turbot
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/turbot/terraform-provider-turbot/apiClient"
+	"testing"
+)
+
+// test suites
+func TestAccResourceGrants_Basic(t *testing.T) {
+	resourceName := "turbot_resource_grants.test"
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckResourceGrantsDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceGrantsConfig("user"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourceGrantsCount(resourceName, 1),
+					resource.TestCheckResourceAttr(resourceName, "grant.#", "1"),
+					resource.TestCheckResourceAttrPair(resourceName, "resource", "turbot_folder.test", "id"),
+					// grant outside of the config: the grant to the ignored identity must survive the
+					// next apply, and the other must be removed by it
+					testAccCreateGrant("turbot_profile.lupin", "admin"),
+					testAccCreateGrant("turbot_profile.snape", "admin"),
+				),
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccResourceGrantsConfig("user"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourceGrantsCount(resourceName, 2),
+					resource.TestCheckResourceAttr(resourceName, "grant.#", "1"),
+				),
+			},
+			{
+				// a grant matches its block by id as well as by uri, so naming the level by id does
+				// not diff against the grant the API reports
+				Config: testAccResourceGrantsLevelIdConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourceGrantsCount(resourceName, 3),
+					resource.TestCheckResourceAttr(resourceName, "grant.#", "2"),
+				),
+			},
+			{
+				// an import has no ignore_identities, so it reports every grant on the resource
+				ResourceName: resourceName,
+				ImportState:  true,
+				ImportStateCheck: func(states []*terraform.InstanceState) error {
+					if len(states) != 1 || states[0].Attributes["grant.#"] != "3" {
+						return fmt.Errorf("expected an import of 3 grants, got %v", states)
+					}
+					return nil
+				},
+			},
+			{
+				Config: testAccResourceGrantsConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourceGrantsCount(resourceName, 1),
+					resource.TestCheckResourceAttr(resourceName, "grant.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+// configs
+func testAccResourceGrantsConfig(levels ...string) string {
+	var levelUris []string
+	for _, level := range levels {
+		levelUris = append(levelUris, fmt.Sprintf(`"tmod:@turbot/turbot-iam#/permission/levels/%s"`, level))
+	}
+	return testAccResourceGrantsLevelsConfig(levelUris...)
+}
+
+// a user grant and an admin grant, with the admin level named by its id
+func testAccResourceGrantsLevelIdConfig() string {
+	return testAccResourceGrantsLevelsConfig(`"tmod:@turbot/turbot-iam#/permission/levels/user"`, "data.turbot_resource.admin.id") + `
+data "turbot_resource" "admin" {
+  id = "tmod:@turbot/turbot-iam#/permission/levels/admin"
+}
+`
+}
+
+// a grant to snape on the test folder for each of the levels, given as HCL expressions
+func testAccResourceGrantsLevelsConfig(levels ...string) string {
+	grants := ""
+	for _, level := range levels {
+		grants += fmt.Sprintf(`
+  grant {
+    identity = turbot_profile.snape.id
+    type     = "tmod:@turbot/turbot-iam#/permission/types/turbot"
+    level    = %s
+  }
+`, level)
+	}
+	return testAccGroupMembersProfilesConfig + fmt.Sprintf(`
+resource "turbot_folder" "test" {
+  parent      = "tmod:@turbot/turbot#/"
+  title       = "provider_test_resource_grants"
+  description = "test folder"
+}
+
+resource "turbot_resource_grants" "test" {
+  resource          = turbot_folder.test.id
+  ignore_identities = [turbot_profile.lupin.id]
+%s
+}
+`, grants)
+}
+
+// helper functions
+
+// testAccCheckResourceGrantsCount checks the number of grants on the resource, including those to the
+// ignored identities
+func testAccCheckResourceGrantsCount(resource string, expected int) resource.TestCheckFunc {
+	return func(state *terraform.State) error {
+		rs, ok := state.RootModule().Resources[resource]
+		if !ok {
+			return fmt.Errorf("not found: %s", resource)
+		}
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("no record id is set")
+		}
+		client := testAccProvider.Meta().(*apiClient.Client)
+		grants, err := client.ReadAllGrants(fmt.Sprintf("resource:%s level:self", rs.Primary.ID))
+		if err != nil {
+			return fmt.Errorf("error fetching item with resource %s. %s", resource, err)
+		}
+		if len(grants) != expected {
+			return fmt.Errorf("expected %d grants on resource %s, got %d", expected, rs.Primary.ID, len(grants))
+		}
+		return nil
+	}
+}
+
+// testAccCreateGrant grants a Turbot permission level on the test folder to a profile, outside of terraform
+func testAccCreateGrant(profile, level string) resource.TestCheckFunc {
+	return func(state *terraform.State) error {
+		ids := map[string]string{}
+		for _, name := range []string{"turbot_folder.test", profile} {
+			rs, ok := state.RootModule().Resources[name]
+			if !ok {
+				return fmt.Errorf("not found: %s", name)
+			}
+			ids[name] = rs.Primary.ID
+		}
+		client := testAccProvider.Meta().(*apiClient.Client)
+		_, err := client.CreateGrant(map[string]interface{}{
+			"resource": ids["turbot_folder.test"],
+			"identity": ids[profile],
+			"type":     "tmod:@turbot/turbot-iam#/permission/types/turbot",
+			"level":    "tmod:@turbot/turbot-iam#/permission/levels/" + level,
+		})
+		return err
+	}
+}
+
+func testAccCheckResourceGrantsDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*apiClient.Client)
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type == "turbot_resource_grants" {
+			// the folder is destroyed too, which also deletes its grants
+			grants, err := client.ReadAllGrants(fmt.Sprintf("resource:%s level:self", rs.Primary.ID))
+			if err != nil && !errors.Is(err, apiClient.ErrNotFound) {
+				return fmt.Errorf("expected 'not found' error, got %s", err)
+			}
+			if len(grants) > 0 {
+				return fmt.Errorf("resource still has grants")
+			}
+		}
+	}
+
+	return nil
+}
@@ -1,12 +1,13 @@
 package turbot
 
 import (
+	"errors"
 	"fmt"
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/terraform"
 	"github.com/turbot/terraform-provider-turbot/apiClient"
-	"github.com/turbot/terraform-provider-turbot/errors"
 	"github.com/turbot/terraform-provider-turbot/helpers"
+	"regexp"
 	"testing"
 )
 
@@ -71,13 +72,14 @@ func TestAccResource_BasicFolderResource(t *testing.T) {
 				),
 			},
 			{
+				// data holds only the configured properties - the description is left on the folder, but not read
 				Config: testAccResourceConfigFolder(folderType, folderWithNoDescription, metadataUpdated),
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckResourceExists("turbot_resource.test"),
 					resource.TestCheckResourceAttr(
 						"turbot_resource.test", "type", folderType),
 					resource.TestCheckResourceAttr(
-						"turbot_resource.test", "data", helpers.FormatJson(folderDataUpdatedTitle)),
+						"turbot_resource.test", "data", helpers.FormatJson(folderWithNoDescription)),
 					resource.TestCheckResourceAttr(
 						"turbot_resource.test", "metadata", helpers.FormatJson(metadataUpdated)),
 				),
@@ -123,7 +125,7 @@ func TestAccResource_AccountResourceWithFullData(t *testing.T) {
 		CheckDestroy: testAccCheckResourceDestroy,
 		Steps: []resource.TestStep{
 			{
-				Config: testAccResourceConfigAccount(accountType, metadata, accountData),
+				Config: testAccResourceConfigAccountProperties(accountType, "full_data", fullAccountData, "metadata", metadata),
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckResourceExists("turbot_resource.account_resource"),
 					resource.TestCheckResourceAttr(
@@ -144,7 +146,7 @@ func TestAccResource_AccountResourceWithFullMetadata(t *testing.T) {
 		CheckDestroy: testAccCheckResourceDestroy,
 		Steps: []resource.TestStep{
 			{
-				Config: testAccResourceConfigAccount(accountType, metadata, accountData),
+				Config: testAccResourceConfigAccountProperties(accountType, "full_data", fullAccountData, "full_metadata", fullAccountMetadata),
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckResourceExists("turbot_resource.account_resource"),
 					resource.TestCheckResourceAttr(
@@ -152,9 +154,72 @@ func TestAccResource_AccountResourceWithFullMetadata(t *testing.T) {
 					resource.TestCheckResourceAttr(
 						"turbot_resource.account_resource", "full_data", helpers.FormatJson(fullAccountData)),
 					resource.TestCheckResourceAttr(
-						"turbot_resource.account_resource", "full_metadat", helpers.FormatJson(metadata)),
+						"turbot_resource.account_resource", "full_metadata", helpers.FormatJson(fullAccountMetadata)),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResource_FolderResourceDataObject(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceConfigFolderObject("provider_test", `jsonencode({ c1 = "custom1", n = 1 })`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourceExists("turbot_resource.test"),
+					resource.TestCheckResourceAttr(
+						"turbot_resource.test", "data_object.title", "provider_test"),
+					resource.TestCheckResourceAttr(
+						"turbot_resource.test", "metadata_object.custom", `{"c1":"custom1","n":1}`),
+				),
+			},
+			{
+				Config: testAccResourceConfigFolderObject("provider_test_updated", `jsonencode({ c1 = "custom1", n = 1 })`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourceExists("turbot_resource.test"),
+					resource.TestCheckResourceAttr(
+						"turbot_resource.test", "data_object.title", "provider_test_updated"),
+				),
+			},
+		},
+	})
+}
+
+// data the resource type's schema rejects fails the plan, naming the JSON pointer of each violation
+func TestAccResource_PlanValidation(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccResourceConfigFolder(folderType, folderWithNoTitle, metadata),
+				ExpectError: regexp.MustCompile(`invalid data for resource type .*folder:\s+/ must have the property "title"`),
+			},
+			{
+				Config:      testAccResourceConfigFolderObject("", `"custom1"`),
+				ExpectError: regexp.MustCompile(`invalid data_object for resource type .*folder:\s+/title must be at least 1 characters long`),
+			},
+			{
+				// a map element that is valid JSON is decoded, so this title is a number
+				Config:      testAccResourceConfigFolderObject("5", `"custom1"`),
+				ExpectError: regexp.MustCompile(`/title must be string, got number`),
+			},
+			{
+				Config: testAccResourceConfigFolderObject("provider_test", `"custom1"`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourceExists("turbot_resource.test"),
 				),
 			},
+			{
+				// an update is checked against the update schema
+				Config:      testAccResourceConfigFolderObject("", `"custom1"`),
+				ExpectError: regexp.MustCompile(`/title must be at least 1 characters long`),
+			},
 		},
 	})
 }
@@ -165,7 +230,7 @@ var accountType = `tmod:@turbot/aws#/resource/types/account`
 
 var fullAccountData = `{
  "Id": "112233445566",
- "title": "account"
+ "title": "account",
  "description": "full data account"
 }
 `
@@ -212,6 +277,10 @@ var folderDataUpdatedDescription = `{
  "description": "test resource_updated"
 }
 `
+var folderWithNoTitle = `{
+ "description": "test resource"
+}
+`
 var folderWithNoDescription = `{
  "title": "provider_test_updated"
 }
@@ -231,6 +300,23 @@ resource "turbot_resource" "test" {
 	return config
 }
 
+func testAccResourceConfigFolderObject(title, custom string) string {
+	config := fmt.Sprintf(`
+resource "turbot_resource" "test" {
+	parent = "tmod:@turbot/turbot#/"
+	type = "tmod:@turbot/turbot#/resource/types/folder"
+	data_object = {
+		title       = "%s"
+		description = "test resource"
+	}
+	metadata_object = {
+		custom = %s
+	}
+}
+`, title, custom)
+	return config
+}
+
 func testAccResourceConfigAccount(resourceType, metadata, data string) string {
 	config := fmt.Sprintf(`
 resource "turbot_folder" "test" {
@@ -249,6 +335,23 @@ resource "turbot_resource" "account_resource" {
 	return config
 }
 
+func testAccResourceConfigAccountProperties(resourceType, dataProperty, data, metadataProperty, metadata string) string {
+	return fmt.Sprintf(`
+resource "turbot_folder" "test" {
+	parent = "tmod:@turbot/turbot#/"
+	title = "account_import"
+	description = "test folder for turbot terraform provider"
+}
+resource "turbot_resource" "account_resource" {
+  parent     = turbot_folder.test.id
+  type       = "%s"
+  %s = <<EOF
+%sEOF
+  %s = <<EOF
+%sEOF
+}`, resourceType, dataProperty, data, metadataProperty, metadata)
+}
+
 // helper functions
 func testAccCheckResourceExists(resource string) resource.TestCheckFunc {
 	return func(state *terraform.State) error {
@@ -276,7 +379,7 @@ func testAccCheckResourceDestroy(s *terraform.State) error {
 			if err == nil {
 				return fmt.Errorf("Alert still exists")
 			}
-			if !errors.NotFoundError(err) {
+			if !errors.Is(err, apiClient.ErrNotFound) {
 				return fmt.Errorf("expected 'not found' error, got %s", err)
 			}
 		}
@@ -1,9 +1,9 @@
 package turbot
 
 import (
+	"errors"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/turbot/terraform-provider-turbot/apiClient"
-	"github.com/turbot/terraform-provider-turbot/errors"
 	"github.com/turbot/terraform-provider-turbot/helpers"
 )
 
@@ -112,7 +112,7 @@ func resourceTurbotSmartFolderRead(d *schema.ResourceData, meta interface{}) err
 
 	smartFolder, err := client.ReadSmartFolder(id)
 	if err != nil {
-		if errors.NotFoundError(err) {
+		if errors.Is(err, apiClient.ErrNotFound) {
 			// folder was not found - clear id
 			d.SetId("")
 		}
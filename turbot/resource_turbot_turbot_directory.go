@@ -1,9 +1,9 @@
 package turbot
 
 import (
+	"errors"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/turbot/terraform-provider-turbot/apiClient"
-	"github.com/turbot/terraform-provider-turbot/errors"
 	"github.com/turbot/terraform-provider-turbot/helpers"
 	"strings"
 )
@@ -109,7 +109,7 @@ func resourceTurbotTurbotDirectoryRead(d *schema.ResourceData, meta interface{})
 
 	turbotDirectory, err := client.ReadTurbotDirectory(id)
 	if err != nil {
-		if errors.NotFoundError(err) {
+		if errors.Is(err, apiClient.ErrNotFound) {
 			// local directoery was not found - clear id
 			d.SetId("")
 		}
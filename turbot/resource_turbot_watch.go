@@ -1,9 +1,9 @@
 package turbot
 
 import (
+	"errors"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/turbot/terraform-provider-turbot/apiClient"
-	"github.com/turbot/terraform-provider-turbot/errors"
 	"github.com/turbot/terraform-provider-turbot/helpers"
 )
 
@@ -106,7 +106,7 @@ func resourceTurbotWatchRead(d *schema.ResourceData, meta interface{}) error {
 
 	watch, err := client.ReadWatch(id)
 	if err != nil {
-		if errors.NotFoundError(err) {
+		if errors.Is(err, apiClient.ErrNotFound) {
 			// watch was not found - clear id
 			d.SetId("")
 		}
@@ -135,7 +135,8 @@ func resourceTurbotWatchDelete(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*apiClient.Client)
 	id := d.Id()
 	err := client.DeleteWatch(id)
-	if err != nil {
+	// deleting a resource deletes its watches, so the watch may already be gone
+	if err != nil && !errors.Is(err, apiClient.ErrNotFound) {
 		return err
 	}
 